@@ -34,6 +34,10 @@ type initParameter struct {
 	CgroupPrefix string `json:"cgroupPrefix"`
 	CPUSet       string `json:"cpuset"`
 	CredStart    int    `json:"credStart"`
+
+	DefaultLimits       model.Limits `json:"defaultLimits"`
+	MaxLimits           model.Limits `json:"maxLimits"`
+	RejectExceededLimit bool         `json:"rejectExceededLimit"`
 }
 
 var (
@@ -107,6 +111,10 @@ func Init(i *C.char) C.int {
 		Parallelism:           ip.Parallelism,
 		WorkDir:               ip.Dir,
 		TimeLimitTickInterval: 100 * time.Millisecond,
+		DefaultLimits:         model.ConvertLimits(ip.DefaultLimits),
+		MaxLimits:             model.ConvertLimits(ip.MaxLimits),
+		RejectExceededLimits:  ip.RejectExceededLimit,
+		NetShare:              ip.NetShare,
 	})
 	work.Start()
 
@@ -3,26 +3,38 @@ package main
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"flag"
 	"io"
 	"log"
+	"mime"
 	"net/http"
 	"os"
+	"strconv"
 
+	"github.com/criyle/go-judge/cmd/go-judge/tlsconfig"
 	"github.com/criyle/go-judge/pb"
 	"github.com/gin-gonic/gin"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/protobuf/encoding/protojson"
-	"google.golang.org/protobuf/types/known/emptypb"
 )
 
 var (
 	addr    = flag.String("addr", ":7755", "Rest api server addr")
 	srvAddr = flag.String("srvaddr", "localhost:5051", "GRPC server addr")
+	useTLS  = flag.Bool("tls", false, "connect GRPC server over TLS")
+	tlsCA   = flag.String("tls-ca", "", "CA file to verify the server certificate (system roots by default)")
+	tlsCert = flag.String("tls-cert", "", "client certificate file for mutual TLS")
+	tlsKey  = flag.String("tls-key", "", "client private key file for mutual TLS")
 )
 
+// chunkSize is the content size of each upload chunk
+const chunkSize = 1 << 20
+
 type execProxy struct {
 	client pb.ExecutorClient
 }
@@ -48,7 +60,7 @@ func (p *execProxy) Exec(c *gin.Context) {
 }
 
 func (p *execProxy) FileList(c *gin.Context) {
-	rep, err := p.client.FileList(c, &emptypb.Empty{})
+	rep, err := p.client.FileList(c, &pb.FileListRequest{})
 	if err != nil {
 		c.AbortWithError(http.StatusInternalServerError, err)
 		return
@@ -107,6 +119,108 @@ func (p *execProxy) FilePost(c *gin.Context) {
 	c.JSON(http.StatusOK, rep)
 }
 
+// FileUpload uploads the file by chunks through streaming RPC
+func (p *execProxy) FileUpload(c *gin.Context) {
+	fh, err := c.FormFile("file")
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+	fi, err := fh.Open()
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+	defer fi.Close()
+
+	s, err := p.client.FileUpload(c)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+	err = s.Send(&pb.FileChunk{Chunk: &pb.FileChunk_Header_{Header: &pb.FileChunk_Header{
+		Name: fh.Filename,
+	}}})
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+	h := sha256.New()
+	buf := make([]byte, chunkSize)
+	for {
+		n, err := fi.Read(buf)
+		if n > 0 {
+			h.Write(buf[:n])
+			if err := s.Send(&pb.FileChunk{Chunk: &pb.FileChunk_Content{Content: buf[:n]}}); err != nil {
+				break // error is returned by CloseAndRecv
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+	}
+	s.Send(&pb.FileChunk{Chunk: &pb.FileChunk_Trailer_{Trailer: &pb.FileChunk_Trailer{
+		Sha256: hex.EncodeToString(h.Sum(nil)),
+	}}})
+	rep, err := s.CloseAndRecv()
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, rep)
+}
+
+// FileDownload downloads the file by chunks through streaming RPC and
+// verifies the digest in the trailer
+func (p *execProxy) FileDownload(c *gin.Context) {
+	type fileURI struct {
+		FileID string `uri:"fid"`
+	}
+	var uri fileURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	s, err := p.client.FileDownload(c, &pb.FileID{FileID: uri.FileID})
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+	h := sha256.New()
+	for {
+		rep, err := s.Recv()
+		if err != nil {
+			if !c.Writer.Written() {
+				c.AbortWithError(http.StatusInternalServerError, err)
+			}
+			log.Println("download", err)
+			return
+		}
+		switch chunk := rep.GetChunk().(type) {
+		case *pb.FileChunk_Header_:
+			c.Header("Content-Type", "application/octet-stream")
+			c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": chunk.Header.GetName()}))
+			if size := chunk.Header.GetSize(); size > 0 {
+				c.Header("Content-Length", strconv.FormatInt(size, 10))
+			}
+			c.Status(http.StatusOK)
+		case *pb.FileChunk_Content:
+			h.Write(chunk.Content)
+			c.Writer.Write(chunk.Content)
+		case *pb.FileChunk_Trailer_:
+			if digest := hex.EncodeToString(h.Sum(nil)); digest != chunk.Trailer.GetSha256() {
+				log.Println("download: sha256 mismatch", digest, chunk.Trailer.GetSha256())
+			}
+			return
+		}
+	}
+}
+
 func (p *execProxy) FileDelete(c *gin.Context) {
 	type fileURI struct {
 		FileID string `uri:"fid"`
@@ -131,7 +245,15 @@ func (p *execProxy) FileDelete(c *gin.Context) {
 func main() {
 	flag.Parse()
 	token := os.Getenv("TOKEN")
-	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	creds := insecure.NewCredentials()
+	if *useTLS || *tlsCA != "" || *tlsCert != "" {
+		tlsConf, err := tlsconfig.Client(*tlsCA, *tlsCert, *tlsKey)
+		if err != nil {
+			log.Fatalln("tls", err)
+		}
+		creds = credentials.NewTLS(tlsConf)
+	}
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
 	if token != "" {
 		opts = append(opts, grpc.WithPerRPCCredentials(newTokenAuth(token)))
 	}
@@ -149,6 +271,8 @@ func main() {
 	r.GET("/file/:fid", p.FileGet)
 	r.POST("/file", p.FilePost)
 	r.DELETE("/file/:fid", p.FileDelete)
+	r.POST("/file/upload", p.FileUpload)
+	r.GET("/file/:fid/download", p.FileDownload)
 
 	log.Println(r.Run(*addr))
 }
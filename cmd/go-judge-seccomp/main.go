@@ -0,0 +1,212 @@
+// Command go-judge-seccomp generates seccomp.yaml from the results of requests
+// running in seccomp audit mode.
+//
+// It reads JSON results from the files given as arguments (or stdin), collects
+// every "syscalls" list inside and writes a policy that allows all of them.
+//
+// Syscalls allowed by the seccomp config of the server are not logged in
+// audit mode, so the config should be given by -base to be merged into the
+// generated policy. Argument conditions in the base are kept as is.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/elastic/go-seccomp-bpf"
+	"github.com/elastic/go-seccomp-bpf/arch"
+	"github.com/elastic/go-ucfg/yaml"
+)
+
+var (
+	output        = flag.String("o", "", "output file (stdout by default)")
+	defaultAction = flag.String("default-action", "kill_process", "action for syscalls not in the reports (kill_process / errno / trap / log)")
+	base          = flag.String("base", "", "seccomp config used by the server, its allowed syscalls are not logged and are merged into the output")
+)
+
+func main() {
+	flag.Parse()
+
+	var action seccomp.Action
+	if err := action.Unpack(*defaultAction); err != nil {
+		log.Fatalln("invalid default action:", err)
+	}
+
+	syscalls := make(map[string]struct{})
+	reports := 0
+	if flag.NArg() == 0 {
+		if err := readReport(os.Stdin, syscalls); err != nil {
+			log.Fatalln("read stdin:", err)
+		}
+		reports++
+	}
+	for _, name := range flag.Args() {
+		f, err := os.Open(name)
+		if err != nil {
+			log.Fatalln("open report:", err)
+		}
+		err = readReport(f, syscalls)
+		f.Close()
+		if err != nil {
+			log.Fatalf("read report %s: %v", name, err)
+		}
+		reports++
+	}
+
+	var groups []seccomp.SyscallGroup
+	if *base != "" {
+		policy, err := readPolicy(*base)
+		if err != nil {
+			log.Fatalln("read base:", err)
+		}
+		groups = mergePolicy(policy, syscalls)
+	}
+
+	names := make([]string, 0, len(syscalls))
+	info, err := arch.GetInfo("")
+	for s := range syscalls {
+		// numeric names are syscalls unknown to the audit arch table
+		if err == nil {
+			if _, ok := info.SyscallNames[s]; !ok {
+				log.Printf("skipped unknown syscall %q", s)
+				continue
+			}
+		}
+		names = append(names, s)
+	}
+	slices.Sort(names)
+
+	w := os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			log.Fatalln("create output:", err)
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := writePolicy(w, action, names, groups, reports); err != nil {
+		log.Fatalln("write policy:", err)
+	}
+}
+
+// readReport decodes JSON values and collects all "syscalls" entries regardless
+// of the nesting (result, result list or response)
+func readReport(r io.Reader, syscalls map[string]struct{}) error {
+	dec := json.NewDecoder(r)
+	for {
+		var v any
+		if err := dec.Decode(&v); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		collectSyscalls(v, syscalls)
+	}
+}
+
+func collectSyscalls(v any, syscalls map[string]struct{}) {
+	switch v := v.(type) {
+	case []any:
+		for _, e := range v {
+			collectSyscalls(e, syscalls)
+		}
+	case map[string]any:
+		for k, e := range v {
+			if l, ok := e.([]any); ok && k == "syscalls" {
+				for _, s := range l {
+					if s, ok := s.(string); ok {
+						syscalls[s] = struct{}{}
+					}
+				}
+				continue
+			}
+			collectSyscalls(e, syscalls)
+		}
+	}
+}
+
+// readPolicy reads the seccomp config in the same way as go-judge
+func readPolicy(name string) (*seccomp.Policy, error) {
+	conf, err := yaml.NewConfigWithFile(name)
+	if err != nil {
+		return nil, err
+	}
+	var policy seccomp.Policy
+	if err := conf.Unpack(&policy); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// mergePolicy adds the syscalls allowed by the base policy without argument
+// conditions into syscalls and returns the other groups of the base policy in
+// order. Syscalls allowed by syscalls are removed from these groups, entries
+// with argument conditions are kept since the calls matching the conditions
+// are not logged.
+func mergePolicy(policy *seccomp.Policy, syscalls map[string]struct{}) []seccomp.SyscallGroup {
+	for _, g := range policy.Syscalls {
+		if g.Action == seccomp.ActionAllow {
+			for _, n := range g.Names {
+				syscalls[n] = struct{}{}
+			}
+		}
+	}
+	allowed := func(n string) bool {
+		_, ok := syscalls[n]
+		return ok
+	}
+	var groups []seccomp.SyscallGroup
+	for _, g := range policy.Syscalls {
+		g.Names = slices.DeleteFunc(slices.Clone(g.Names), allowed)
+		g.NamesWithCondtions = slices.DeleteFunc(slices.Clone(g.NamesWithCondtions), func(n seccomp.NameWithConditions) bool {
+			return allowed(n.Name)
+		})
+		if len(g.Names) > 0 || len(g.NamesWithCondtions) > 0 {
+			groups = append(groups, g)
+		}
+	}
+	return groups
+}
+
+// writePolicy writes the policy in the format of seccomp.yaml read by go-judge
+func writePolicy(w io.Writer, action seccomp.Action, names []string, groups []seccomp.SyscallGroup, reports int) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# generated by go-judge-seccomp from %d report(s)\n", reports)
+	fmt.Fprintf(&b, "default_action: %s\n", action)
+	b.WriteString("syscalls:\n")
+	b.WriteString("  - action: allow\n")
+	b.WriteString("    names:\n")
+	for _, n := range names {
+		fmt.Fprintf(&b, "      - %s\n", n)
+	}
+	for _, g := range groups {
+		fmt.Fprintf(&b, "  - action: %s\n", g.Action)
+		if len(g.Names) > 0 {
+			b.WriteString("    names:\n")
+			for _, n := range g.Names {
+				fmt.Fprintf(&b, "      - %s\n", n)
+			}
+		}
+		if len(g.NamesWithCondtions) > 0 {
+			b.WriteString("    names_with_args:\n")
+			for _, n := range g.NamesWithCondtions {
+				fmt.Fprintf(&b, "      - name: %s\n", n.Name)
+				b.WriteString("        arguments:\n")
+				for _, c := range n.Conditions {
+					fmt.Fprintf(&b, "          - argument: %d\n", c.Argument)
+					fmt.Fprintf(&b, "            operation: %s\n", c.Operation)
+					fmt.Fprintf(&b, "            value: %d\n", c.Value)
+				}
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
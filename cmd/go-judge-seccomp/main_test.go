@@ -0,0 +1,85 @@
+package main
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/elastic/go-seccomp-bpf"
+)
+
+const testBase = `
+default_action: kill_process
+syscalls:
+  - action: allow
+    names:
+      - read
+    names_with_args:
+      - name: clone
+        arguments:
+          - argument: 0
+            operation: BitsSet
+            value: 256
+      - name: socket
+        arguments:
+          - argument: 0
+            operation: Equal
+            value: 1
+  - action: errno
+    names:
+      - ptrace
+      - write
+`
+
+func TestMergePolicy(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.yaml")
+	if err := os.WriteFile(base, []byte(testBase), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	policy, err := readPolicy(base)
+	if err != nil {
+		t.Fatalf("readPolicy: %v", err)
+	}
+
+	// socket is logged so it is allowed without conditions
+	syscalls := map[string]struct{}{"write": {}, "socket": {}}
+	groups := mergePolicy(policy, syscalls)
+	names := slices.Sorted(maps.Keys(syscalls))
+
+	out := filepath.Join(dir, "out.yaml")
+	f, err := os.Create(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = writePolicy(f, seccomp.ActionKillProcess, names, groups, 1)
+	f.Close()
+	if err != nil {
+		t.Fatalf("writePolicy: %v", err)
+	}
+	got, err := readPolicy(out)
+	if err != nil {
+		t.Fatalf("readPolicy output: %v", err)
+	}
+
+	if len(got.Syscalls) != 3 {
+		t.Fatalf("expected 3 groups, got %+v", got.Syscalls)
+	}
+	if g := got.Syscalls[0]; g.Action != seccomp.ActionAllow || !slices.Equal(g.Names, []string{"read", "socket", "write"}) {
+		t.Fatalf("unexpected allow group %+v", g)
+	}
+	g := got.Syscalls[1]
+	if g.Action != seccomp.ActionAllow || len(g.Names) != 0 || len(g.NamesWithCondtions) != 1 {
+		t.Fatalf("unexpected conditional group %+v", g)
+	}
+	if n := g.NamesWithCondtions[0]; n.Name != "clone" || !slices.Equal(n.Conditions, seccomp.ArgumentConditions{
+		{Argument: 0, Operation: seccomp.BitsSet, Value: 256},
+	}) {
+		t.Fatalf("unexpected conditions %+v", n)
+	}
+	if g := got.Syscalls[2]; g.Action != seccomp.ActionErrno || !slices.Equal(g.Names, []string{"ptrace"}) {
+		t.Fatalf("unexpected errno group %+v", g)
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"log"
 	"os"
@@ -21,9 +22,13 @@ type grpcWrapper struct {
 	sc pb.Executor_ExecStreamClient
 }
 
-func newGrpc(args []string, srvAddr string) Stream {
+func newGrpc(args []string, srvAddr string, tlsConf *tls.Config) Stream {
 	token := os.Getenv("TOKEN")
-	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	creds := insecure.NewCredentials()
+	if tlsConf != nil {
+		creds = credentials.NewTLS(tlsConf)
+	}
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
 	if token != "" {
 		opts = append(opts, grpc.WithPerRPCCredentials(newTokenAuth(token)))
 	}
@@ -175,7 +180,9 @@ func convertPBCopyIn(copyIn map[string]model.CmdFile) map[string]*pb.Request_Fil
 		if i.Symlink != nil {
 			continue
 		}
-		rt[k] = convertPBFile(i)
+		f := convertPBFile(i)
+		f.Extract = i.Extract
+		rt[k] = f
 	}
 	return rt
 }
@@ -183,14 +190,19 @@ func convertPBCopyIn(copyIn map[string]model.CmdFile) map[string]*pb.Request_Fil
 func convertPBCopyOut(copyOut []string) []*pb.Request_CmdCopyOutFile {
 	rt := make([]*pb.Request_CmdCopyOutFile, 0, len(copyOut))
 	for _, n := range copyOut {
-		optional := false
+		optional, archive := false, false
 		if strings.HasSuffix(n, "?") {
 			optional = true
 			n = strings.TrimSuffix(n, "?")
 		}
+		if strings.HasSuffix(n, "/") {
+			archive = true
+			n = strings.TrimSuffix(n, "/")
+		}
 		rt = append(rt, &pb.Request_CmdCopyOutFile{
 			Name:     n,
 			Optional: optional,
+			Archive:  archive,
 		})
 	}
 	return rt
@@ -13,6 +13,7 @@ import (
 
 	"github.com/criyle/go-judge/cmd/go-judge/model"
 	"github.com/criyle/go-judge/cmd/go-judge/stream"
+	"github.com/criyle/go-judge/cmd/go-judge/tlsconfig"
 	"golang.org/x/term"
 )
 
@@ -21,6 +22,10 @@ var (
 	wsURL     = flag.String("ws-url", "ws://localhost:5050/stream", "HTTP server url")
 	grpcAddr  = flag.String("grpc-addr", "localhost:5051", "GRPC server addr")
 	copyInDir = flag.String("copy-in-dir", "", "directory to copy files from")
+	useTLS    = flag.Bool("tls", false, "connect gRPC server over TLS (websocket uses TLS with wss:// url)")
+	tlsCA     = flag.String("tls-ca", "", "CA file to verify the server certificate (system roots by default)")
+	tlsCert   = flag.String("tls-cert", "", "client certificate file for mutual TLS")
+	tlsKey    = flag.String("tls-key", "", "client private key file for mutual TLS")
 )
 
 const (
@@ -49,12 +54,19 @@ func main() {
 	if len(args) == 0 {
 		args = []string{"/bin/bash"}
 	}
+	tlsConf, err := tlsconfig.Client(*tlsCA, *tlsCert, *tlsKey)
+	if err != nil {
+		log.Fatalln("tls: ", err)
+	}
 	var s Stream
 	switch *transport {
 	case "websocket":
-		s = newWebsocket(args, *wsURL)
+		s = newWebsocket(args, *wsURL, tlsConf)
 	case "grpc":
-		s = newGrpc(args, *grpcAddr)
+		if !*useTLS && *tlsCA == "" && *tlsCert == "" {
+			tlsConf = nil
+		}
+		s = newGrpc(args, *grpcAddr, tlsConf)
 	default:
 		log.Fatalln("invalid transport: ", *transport)
 	}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -19,13 +20,15 @@ type websocketStream struct {
 	conn *websocket.Conn
 }
 
-func newWebsocket(args []string, wsURL string) Stream {
+func newWebsocket(args []string, wsURL string, tlsConf *tls.Config) Stream {
 	header := make(http.Header)
 	token := os.Getenv("TOKEN")
 	if token != "" {
 		header.Add("Authorization", "Bearer "+token)
 	}
-	conn, _, err := websocket.DefaultDialer.Dial(wsURL, header)
+	dialer := *websocket.DefaultDialer
+	dialer.TLSClientConfig = tlsConf
+	conn, _, err := dialer.Dial(wsURL, header)
 	if err != nil {
 		log.Fatalln("ws connect: ", err)
 	}
@@ -0,0 +1,283 @@
+// Package auth implements the bearer token authentication with scopes, rate
+// limit and concurrency limit for each token
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+
+	"github.com/goccy/go-yaml"
+)
+
+// Scope defines the operations allowed by a token
+type Scope string
+
+// Defines scopes
+const (
+	ScopeRun       Scope = "run"       // run commands through /run, /ws and Exec
+	ScopeStream    Scope = "stream"    // run interactive commands through /stream and ExecStream
+	ScopeFileRead  Scope = "fileRead"  // list and download files
+	ScopeFileWrite Scope = "fileWrite" // upload and delete files
+	ScopeAdmin     Scope = "admin"     // administrative operations (e.g. /calibrate)
+)
+
+// AllScopes contains all scopes
+var AllScopes = []Scope{ScopeRun, ScopeStream, ScopeFileRead, ScopeFileWrite, ScopeAdmin}
+
+// Errors returned when the token is not accepted
+var (
+	ErrUnauthenticated = errors.New("invalid auth token")
+	ErrForbidden       = errors.New("token scope not allowed")
+	ErrRateLimited     = errors.New("token rate limit exceeded")
+	ErrTooManyRequests = errors.New("token concurrency limit exceeded")
+)
+
+// Token defines a token in the token file
+type Token struct {
+	Name           string   `yaml:"name"`
+	Token          string   `yaml:"token"`
+	Subject        string   `yaml:"subject"` // common name of the verified client certificate
+	Scopes         []Scope  `yaml:"scopes"`
+	RateLimit      float64  `yaml:"rateLimit"`      // requests per second, 0 for unlimited
+	RateBurst      int      `yaml:"rateBurst"`      // bucket size, default to rate limit
+	MaxConcurrency int      `yaml:"maxConcurrency"` // concurrent requests, 0 for unlimited
+	SrcPrefix      []string `yaml:"srcPrefix"`      // allowed local src prefixes, empty for the server default
+}
+
+type tokenFile struct {
+	Tokens []Token `yaml:"tokens"`
+}
+
+// Authenticator checks tokens loaded from the token file
+type Authenticator struct {
+	path   string
+	static []Token
+
+	// Observer is called with the token name (empty if not authenticated),
+	// scope and the result of every check
+	Observer func(name string, scope Scope, err error)
+
+	mu     sync.RWMutex
+	tokens []*entry
+}
+
+type entry struct {
+	Token
+	bucket *bucket
+	sem    chan struct{}
+}
+
+// New creates the authenticator with tokens from the token file at path (can
+// be empty) and the static tokens
+func New(path string, static ...Token) (*Authenticator, error) {
+	a := &Authenticator{path: path, static: static}
+	if err := a.Reload(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Reload reloads the token file, the rate limit and concurrency state is kept
+// for tokens with the same limits
+func (a *Authenticator) Reload() error {
+	tokens := slices.Clone(a.static)
+	if a.path != "" {
+		b, err := os.ReadFile(a.path)
+		if err != nil {
+			return fmt.Errorf("token file: %w", err)
+		}
+		var f tokenFile
+		if err := yaml.Unmarshal(b, &f); err != nil {
+			return fmt.Errorf("token file: %w", err)
+		}
+		tokens = append(tokens, f.Tokens...)
+	}
+	if err := validate(tokens); err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	entries := make([]*entry, 0, len(tokens))
+	for _, t := range tokens {
+		e := &entry{Token: t}
+		if old := a.findName(t.Name); old != nil && old.RateLimit == t.RateLimit &&
+			old.RateBurst == t.RateBurst && old.MaxConcurrency == t.MaxConcurrency {
+			e.bucket, e.sem = old.bucket, old.sem
+		} else {
+			if t.RateLimit > 0 {
+				e.bucket = newBucket(t.RateLimit, t.RateBurst)
+			}
+			if t.MaxConcurrency > 0 {
+				e.sem = make(chan struct{}, t.MaxConcurrency)
+			}
+		}
+		entries = append(entries, e)
+	}
+	a.tokens = entries
+	return nil
+}
+
+// Len returns the number of tokens
+func (a *Authenticator) Len() int {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return len(a.tokens)
+}
+
+func validate(tokens []Token) error {
+	names := make(map[string]bool)
+	values := make(map[string]bool)
+	subjects := make(map[string]bool)
+	for i, t := range tokens {
+		if t.Name == "" || (t.Token == "" && t.Subject == "") {
+			return fmt.Errorf("token %d: name and token or subject are required", i)
+		}
+		if names[t.Name] || values[t.Token] || subjects[t.Subject] {
+			return fmt.Errorf("token %q: duplicated name, token or subject", t.Name)
+		}
+		names[t.Name] = true
+		if t.Token != "" {
+			values[t.Token] = true
+		}
+		if t.Subject != "" {
+			subjects[t.Subject] = true
+		}
+		for _, s := range t.Scopes {
+			if !slices.Contains(AllScopes, s) {
+				return fmt.Errorf("token %q: unknown scope %q", t.Name, s)
+			}
+		}
+		if t.RateLimit < 0 || t.RateBurst < 0 || t.MaxConcurrency < 0 {
+			return fmt.Errorf("token %q: negative limit", t.Name)
+		}
+	}
+	return nil
+}
+
+// find finds the entry by token value in constant time per entry, caller
+// should hold the lock
+func (a *Authenticator) find(token string) *entry {
+	if token == "" {
+		return nil
+	}
+	var found *entry
+	for _, e := range a.tokens {
+		if subtle.ConstantTimeCompare([]byte(e.Token.Token), []byte(token)) == 1 {
+			found = e
+		}
+	}
+	return found
+}
+
+// findSubject finds the entry by client certificate subject, caller should
+// hold the lock
+func (a *Authenticator) findSubject(subject string) *entry {
+	if subject == "" {
+		return nil
+	}
+	for _, e := range a.tokens {
+		if e.Subject == subject {
+			return e
+		}
+	}
+	return nil
+}
+
+// findName finds the entry by name, caller should hold the lock
+func (a *Authenticator) findName(name string) *entry {
+	for _, e := range a.tokens {
+		if e.Name == name {
+			return e
+		}
+	}
+	return nil
+}
+
+// Acquire checks the token for the scope and its quotas. When it succeeds,
+// release should be called after the request finished.
+func (a *Authenticator) Acquire(token string, scope Scope) (t *Token, release func(), err error) {
+	a.mu.RLock()
+	e := a.find(token)
+	a.mu.RUnlock()
+	return a.acquire(e, scope)
+}
+
+// AcquireSubject is the same as Acquire but identifies the token by the
+// verified client certificate subject
+func (a *Authenticator) AcquireSubject(subject string, scope Scope) (t *Token, release func(), err error) {
+	a.mu.RLock()
+	e := a.findSubject(subject)
+	a.mu.RUnlock()
+	return a.acquire(e, scope)
+}
+
+func (a *Authenticator) acquire(e *entry, scope Scope) (t *Token, release func(), err error) {
+	var name string
+	if e != nil {
+		name = e.Name
+	}
+	defer func() {
+		if a.Observer != nil {
+			a.Observer(name, scope, err)
+		}
+	}()
+
+	switch {
+	case e == nil:
+		return nil, nil, ErrUnauthenticated
+	case !slices.Contains(e.Scopes, scope):
+		return nil, nil, ErrForbidden
+	case e.bucket != nil && !e.bucket.take():
+		return nil, nil, ErrRateLimited
+	}
+	release = func() {}
+	if e.sem != nil {
+		select {
+		case e.sem <- struct{}{}:
+			var once sync.Once
+			release = func() { once.Do(func() { <-e.sem }) }
+		default:
+			return nil, nil, ErrTooManyRequests
+		}
+	}
+	return &e.Token, release, nil
+}
+
+type tokenKey struct{}
+
+// WithToken returns the context with the authenticated token
+func WithToken(ctx context.Context, t *Token) context.Context {
+	return context.WithValue(ctx, tokenKey{}, t)
+}
+
+// FromContext returns the authenticated token, nil if not authenticated
+func FromContext(ctx context.Context) *Token {
+	t, _ := ctx.Value(tokenKey{}).(*Token)
+	return t
+}
+
+// certSubject returns the common name of the verified client certificate,
+// empty if the client did not present one
+func certSubject(state *tls.ConnectionState) string {
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return ""
+	}
+	return state.VerifiedChains[0][0].Subject.CommonName
+}
+
+// SrcPrefix returns the allowed src prefixes of the authenticated token, or
+// the default if the token does not specify
+func SrcPrefix(ctx context.Context, def []string) []string {
+	if t := FromContext(ctx); t != nil && len(t.SrcPrefix) > 0 {
+		return t.SrcPrefix
+	}
+	return def
+}
@@ -0,0 +1,39 @@
+package auth
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// bucket is a token bucket rate limiter
+type bucket struct {
+	mu     sync.Mutex
+	rate   float64 // tokens per second
+	size   float64
+	tokens float64
+	last   time.Time
+}
+
+func newBucket(rate float64, burst int) *bucket {
+	size := float64(burst)
+	if size == 0 {
+		size = math.Max(1, math.Ceil(rate))
+	}
+	return &bucket{rate: rate, size: size, tokens: size, last: time.Now()}
+}
+
+// take takes a token from the bucket if available
+func (b *bucket) take() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens = math.Min(b.size, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
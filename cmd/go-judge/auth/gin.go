@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GinMiddleware returns the middleware that authenticates the bearer token,
+// or the client certificate subject without bearer token, for the scope of
+// the route, the token is attached to the request context
+func (a *Authenticator) GinMiddleware(scope func(*gin.Context) Scope) gin.HandlerFunc {
+	const bearer = "Bearer "
+	return func(c *gin.Context) {
+		var (
+			t       *Token
+			release func()
+			err     error
+		)
+		if reqToken, ok := strings.CutPrefix(c.GetHeader("Authorization"), bearer); ok {
+			t, release, err = a.Acquire(reqToken, scope(c))
+		} else if subject := certSubject(c.Request.TLS); subject != "" {
+			t, release, err = a.AcquireSubject(subject, scope(c))
+		} else {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		if err != nil {
+			c.AbortWithStatusJSON(httpStatus(err), err.Error())
+			return
+		}
+		defer release()
+
+		c.Request = c.Request.WithContext(WithToken(c.Request.Context(), t))
+		c.Next()
+	}
+}
+
+func httpStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, ErrRateLimited), errors.Is(err, ErrTooManyRequests):
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusUnauthorized
+	}
+}
@@ -0,0 +1,89 @@
+package auth
+
+import (
+	"context"
+	"errors"
+
+	middleware "github.com/grpc-ecosystem/go-grpc-middleware/v2"
+	grpc_auth "github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/auth"
+	"github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/logging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor returns the interceptor that authenticates the
+// bearer token for the scope of the method
+func (a *Authenticator) UnaryServerInterceptor(scope func(method string) Scope) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, release, err := a.grpcAcquire(ctx, scope(info.FullMethod))
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor returns the interceptor that authenticates the
+// bearer token for the scope of the method
+func (a *Authenticator) StreamServerInterceptor(scope func(method string) Scope) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, release, err := a.grpcAcquire(ss.Context(), scope(info.FullMethod))
+		if err != nil {
+			return err
+		}
+		defer release()
+		wrapped := middleware.WrapServerStream(ss)
+		wrapped.WrappedContext = ctx
+		return handler(srv, wrapped)
+	}
+}
+
+// grpcAcquire acquires the token, or the client certificate subject without
+// token, and attaches the token and its name as the logging field to the context
+func (a *Authenticator) grpcAcquire(ctx context.Context, scope Scope) (context.Context, func(), error) {
+	var (
+		t       *Token
+		release func()
+	)
+	reqToken, err := grpc_auth.AuthFromMD(ctx, "bearer")
+	if err == nil {
+		t, release, err = a.Acquire(reqToken, scope)
+	} else if subject := peerSubject(ctx); subject != "" {
+		t, release, err = a.AcquireSubject(subject, scope)
+	} else {
+		return nil, nil, err
+	}
+	if err != nil {
+		return nil, nil, status.Error(grpcCode(err), err.Error())
+	}
+	ctx = WithToken(ctx, t)
+	ctx = logging.InjectFields(ctx, logging.Fields{"token", t.Name})
+	return ctx, release, nil
+}
+
+func peerSubject(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return ""
+	}
+	return certSubject(&info.State)
+}
+
+func grpcCode(err error) codes.Code {
+	switch {
+	case errors.Is(err, ErrForbidden):
+		return codes.PermissionDenied
+	case errors.Is(err, ErrRateLimited), errors.Is(err, ErrTooManyRequests):
+		return codes.ResourceExhausted
+	default:
+		return codes.Unauthenticated
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/criyle/go-judge/envexec"
+	"github.com/criyle/go-judge/worker"
+)
+
+const (
+	// calibrateEnv runs the executable as the calibration benchmark
+	calibrateEnv = "GO_JUDGE_CALIBRATE"
+
+	calibrateRuns      = 3
+	calibrateName      = "go-judge-calibrate"
+	calibrateTimeLimit = 10 * time.Second
+	calibrateMemory    = 256 << 20
+)
+
+func init() {
+	if os.Getenv(calibrateEnv) == "1" {
+		fmt.Println(calibrateBench())
+		os.Exit(0)
+	}
+}
+
+// calibrateBench runs a fixed single threaded workload mixed with integer
+// arithmetic and cache unfriendly memory access
+func calibrateBench() uint64 {
+	const size = 1 << 21
+	a := make([]uint32, size)
+	var x, sum uint64 = 88172645463325252, 0
+	for i := 0; i < 1<<26; i++ {
+		x ^= x << 13
+		x ^= x >> 7
+		x ^= x << 17
+		j := x & (size - 1)
+		a[j] += uint32(x >> 32)
+		sum += uint64(a[(j*7+uint64(i))&(size-1)])
+	}
+	return sum
+}
+
+// speedCalibrator measures the speed factor of the machine by running the
+// calibration benchmark inside the sandbox, the factor is reference time
+// divided by the benchmark time so that slower machine has factor below 1
+type speedCalibrator struct {
+	work      worker.Worker
+	reference time.Duration
+
+	mu     sync.Mutex
+	factor atomic.Uint64 // float64 bits, 0 if not calibrated
+}
+
+// Factor returns the speed factor, 1 if not calibrated
+func (s *speedCalibrator) Factor() float64 {
+	if b := s.factor.Load(); b != 0 {
+		return math.Float64frombits(b)
+	}
+	return 1
+}
+
+// Calibrate runs the benchmark and updates the speed factor with the minimum
+// time of the runs
+func (s *speedCalibrator) Calibrate(ctx context.Context) (float64, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	exe, err := os.Executable()
+	if err != nil {
+		return 0, 0, fmt.Errorf("calibrate: %w", err)
+	}
+	req := &worker.Request{
+		Cmd: []worker.Cmd{{
+			Args: []string{"./" + calibrateName},
+			Env:  []string{calibrateEnv + "=1", "GOMAXPROCS=1", "GOGC=off"},
+			Files: []worker.CmdFile{
+				&worker.MemoryFile{},
+				&worker.Collector{Name: "stdout", Max: 4096},
+				&worker.Collector{Name: "stderr", Max: 4096},
+			},
+			CPULimit:    calibrateTimeLimit,
+			ClockLimit:  2 * calibrateTimeLimit,
+			MemoryLimit: calibrateMemory,
+			ProcLimit:   64,
+			CopyIn: map[string]worker.CmdFile{
+				calibrateName: &worker.LocalFile{Src: exe},
+			},
+		}},
+	}
+	var best time.Duration
+	for range calibrateRuns {
+		rt := <-s.work.Execute(ctx, req)
+		if rt.Error != nil {
+			return 0, 0, fmt.Errorf("calibrate: %w", rt.Error)
+		}
+		r := rt.Results[0]
+		for _, f := range r.Files {
+			f.Close()
+			os.Remove(f.Name())
+		}
+		if r.Status != envexec.StatusAccepted {
+			return 0, 0, fmt.Errorf("calibrate: benchmark %v: %s", r.Status, r.Error)
+		}
+		if best == 0 || r.Time < best {
+			best = r.Time
+		}
+	}
+	factor := float64(s.reference) / float64(best)
+	s.factor.Store(math.Float64bits(factor))
+	return factor, best, nil
+}
@@ -18,6 +18,7 @@ type Config struct {
 	NetShare           bool   `flagUsage:"share net namespace with host"`
 	MountConf          string `flagUsage:"specifies mount configuration file" default:"mount.yaml"`
 	SeccompConf        string `flagUsage:"specifies seccomp filter" default:"seccomp.yaml"`
+	SeccompAudit       bool   `flagUsage:"enable seccomp audit mode that logs instead of kills for requests with seccompAudit (reads /dev/kmsg)"`
 	Parallelism        int    `flagUsage:"control the # of concurrency execution (default equal to number of cpu)"`
 	CgroupPrefix       string `flagUsage:"control cgroup prefix" default:"gojudge"`
 	ContainerCredStart int    `flagUsage:"control the start uid&gid for container (0 uses unprivileged root)" default:"0"`
@@ -26,6 +27,26 @@ type Config struct {
 	// file store
 	SrcPrefix []string `flagUsage:"specifies directory prefix for source type copyin (example: -src-prefix=/home,/usr)"`
 	Dir       string   `flagUsage:"specifies directory to store file upload / download (in memory by default)"`
+	FileStore string   `flagUsage:"specifies file store type (local / content: SHA-256 content-addressed with deduplication / s3: S3-compatible storage cached in dir)" default:"local"`
+
+	// S3-compatible storage for s3 file store
+	FileStoreEndpoint  string        `flagUsage:"specifies S3 endpoint host:port for s3 file store"`
+	FileStoreBucket    string        `flagUsage:"specifies S3 bucket for s3 file store"`
+	FileStorePrefix    string        `flagUsage:"specifies S3 object key prefix for s3 file store"`
+	FileStoreRegion    string        `flagUsage:"specifies S3 region for s3 file store"`
+	FileStoreAccessKey string        `flagUsage:"specifies S3 access key for s3 file store"`
+	FileStoreSecretKey string        `flagUsage:"specifies S3 secret key for s3 file store"`
+	FileStoreInsecure  bool          `flagUsage:"use plain HTTP for S3 endpoint of s3 file store"`
+	FileStoreCacheSize *envexec.Size `flagUsage:"specifies max total size of the local copies of s3 file store in dir (0 for unlimited)" default:"4g"`
+
+	// file store capacity, least recently used files are evicted when full
+	FileStoreMaxSize  *envexec.Size `flagUsage:"specifies max total size of files in the file store (0 for unlimited)" default:"0"`
+	FileStoreMaxCount int           `flagUsage:"specifies max number of files in the file store (0 for unlimited)"`
+
+	// file bundle and copy in archive extraction
+	BundleMaxEntries   int           `flagUsage:"specifies max number of entries in an uploaded bundle, copy in archive or copy out bundle, glob and archive (0 for unlimited)" default:"10000"`
+	BundleMaxEntrySize *envexec.Size `flagUsage:"specifies max size of each file in an uploaded bundle or copy in archive (0 for unlimited)" default:"256m"`
+	BundleMaxTotalSize *envexec.Size `flagUsage:"specifies max total size of files in an uploaded bundle or copy in archive (0 for unlimited)" default:"1g"`
 
 	// runner limit
 	TimeLimitCheckerInterval time.Duration `flagUsage:"specifies time limit checker interval" default:"100ms"`
@@ -33,21 +54,64 @@ type Config struct {
 	OutputLimit              *envexec.Size `flagUsage:"specifies POSIX rlimit for output for each command" default:"256m"`
 	CopyOutLimit             *envexec.Size `flagUsage:"specifies default file copy out max" default:"256m"`
 	OpenFileLimit            int           `flagUsage:"specifies max open file count" default:"256"`
+	IOReadBps                *envexec.Size `flagUsage:"specifies default block device read bandwidth limit for each command (cgroup v2)" default:"0"`
+	IOWriteBps               *envexec.Size `flagUsage:"specifies default block device write bandwidth limit for each command (cgroup v2)" default:"0"`
+	IOReadIOPS               int           `flagUsage:"specifies default block device read IOPS limit for each command (cgroup v2)"`
+	IOWriteIOPS              int           `flagUsage:"specifies default block device write IOPS limit for each command (cgroup v2)"`
+	EnableSyscallTrace       bool          `flagUsage:"enable ptrace syscall trace for requests with syscallTrace (debug only)"`
+	RepeatThreshold          float64       `flagUsage:"specifies default fraction of cpu limit above which a command with repeat is rerun" default:"0.8"`
+	RepeatMax                int           `flagUsage:"specifies max repeat for each command" default:"5"`
+	Calibrate                bool          `flagUsage:"run the calibration benchmark at startup to measure the speed factor"`
+	SpeedReference           time.Duration `flagUsage:"specifies the calibration benchmark time of the reference machine" default:"500ms"`
 	Cpuset                   string        `flagUsage:"control the usage of cpuset for all container process"`
 	EnableCPURate            bool          `flagUsage:"enable cpu cgroup rate control"`
 	CPUCfsPeriod             time.Duration `flagUsage:"set cpu.cfs_period" default:"100ms"`
 	FileTimeout              time.Duration `flagUsage:"specified timeout for filestore files"`
 
+	// default limits for commands without limit specified
+	DefaultCPULimit       time.Duration `flagUsage:"specifies default cpu limit for each command"`
+	DefaultClockLimit     time.Duration `flagUsage:"specifies default clock limit for each command (cpu limit if not set)"`
+	DefaultMemoryLimit    *envexec.Size `flagUsage:"specifies default memory limit for each command" default:"0"`
+	DefaultStackLimit     *envexec.Size `flagUsage:"specifies default stack limit for each command" default:"0"`
+	DefaultProcLimit      int           `flagUsage:"specifies default process count limit for each command"`
+	DefaultDiskLimit      *envexec.Size `flagUsage:"specifies default work dir disk limit for each command" default:"0"`
+	DefaultFileCountLimit int           `flagUsage:"specifies default work dir file count limit for each command"`
+
+	// maximum limits for commands, commands without limit get the maximums
+	MaxCPULimit         time.Duration `flagUsage:"specifies max cpu limit for each command"`
+	MaxClockLimit       time.Duration `flagUsage:"specifies max clock limit for each command"`
+	MaxMemoryLimit      *envexec.Size `flagUsage:"specifies max memory limit for each command" default:"0"`
+	MaxStackLimit       *envexec.Size `flagUsage:"specifies max stack limit for each command" default:"0"`
+	MaxOutputLimit      *envexec.Size `flagUsage:"specifies max output limit for each command" default:"0"`
+	MaxProcLimit        int           `flagUsage:"specifies max process count limit for each command"`
+	MaxOpenFileLimit    int           `flagUsage:"specifies max open file count limit for each command"`
+	MaxCPURateLimit     int           `flagUsage:"specifies max cpu rate limit for each command"`
+	MaxDiskLimit        *envexec.Size `flagUsage:"specifies max work dir disk limit for each command" default:"0"`
+	MaxFileCountLimit   int           `flagUsage:"specifies max work dir file count limit for each command"`
+	MaxCopyOut          *envexec.Size `flagUsage:"specifies max copy out file size for each command" default:"0"`
+	MaxIOReadBps        *envexec.Size `flagUsage:"specifies max block device read bandwidth for each command" default:"0"`
+	MaxIOWriteBps       *envexec.Size `flagUsage:"specifies max block device write bandwidth for each command" default:"0"`
+	MaxIOReadIOPS       int           `flagUsage:"specifies max block device read IOPS for each command"`
+	MaxIOWriteIOPS      int           `flagUsage:"specifies max block device write IOPS for each command"`
+	RejectExceededLimit bool          `flagUsage:"reject commands with limits exceed the maximums instead of clamping"`
+
 	// server config
 	HTTPAddr      string        `flagUsage:"specifies the http binding address"`
 	EnableGRPC    bool          `flagUsage:"enable gRPC endpoint"`
 	GRPCAddr      string        `flagUsage:"specifies the grpc binding address"`
 	MonitorAddr   string        `flagUsage:"specifies the metrics binding address"`
 	AuthToken     string        `flagUsage:"bearer token auth for REST / gRPC"`
+	AuthTokenFile string        `flagUsage:"specifies the token file with token scopes and quotas for REST / gRPC (reload on SIGHUP)"`
 	GRPCMsgSize   *envexec.Size `flagUsage:"message size limit for gRPC message" default:"64m"`
 	EnableDebug   bool          `flagUsage:"enable debug endpoint"`
 	EnableMetrics bool          `flagUsage:"enable prometheus metrics endpoint"`
 
+	// TLS config for http, gRPC and monitor servers
+	TLSCert              string `flagUsage:"specifies the TLS certificate file to serve HTTP / gRPC / monitor over TLS (reload on SIGHUP)"`
+	TLSKey               string `flagUsage:"specifies the TLS private key file"`
+	TLSClientCA          string `flagUsage:"specifies the CA file to verify client certificates"`
+	TLSRequireClientCert bool   `flagUsage:"require client certificates verified by the client CA (mutual TLS)"`
+
 	// logger config
 	Release bool `flagUsage:"release level of logs"`
 	Silent  bool `flagUsage:"do not print logs"`
@@ -1,11 +1,14 @@
 package grpcexecutor
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"time"
 
+	"github.com/criyle/go-judge/cmd/go-judge/auth"
 	"github.com/criyle/go-judge/cmd/go-judge/model"
 	"github.com/criyle/go-judge/envexec"
 	"github.com/criyle/go-judge/filestore"
@@ -15,28 +18,31 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // New creates grpc executor server
-func New(worker worker.Worker, fs filestore.FileStore, srcPrefix []string, logger *zap.Logger) pb.ExecutorServer {
+func New(worker worker.Worker, fs filestore.FileStore, srcPrefix []string, bundleLimit filestore.BundleLimit, logger *zap.Logger) pb.ExecutorServer {
 	return &execServer{
-		worker:    worker,
-		fs:        fs,
-		srcPrefix: srcPrefix,
-		logger:    logger,
+		worker:      worker,
+		fs:          fs,
+		srcPrefix:   srcPrefix,
+		bundleLimit: bundleLimit,
+		logger:      logger,
 	}
 }
 
 type execServer struct {
 	pb.UnimplementedExecutorServer
-	worker    worker.Worker
-	fs        filestore.FileStore
-	srcPrefix []string
-	logger    *zap.Logger
+	worker      worker.Worker
+	fs          filestore.FileStore
+	srcPrefix   []string
+	bundleLimit filestore.BundleLimit
+	logger      *zap.Logger
 }
 
 func (e *execServer) Exec(ctx context.Context, req *pb.Request) (*pb.Response, error) {
-	r, err := convertPBRequest(req, e.srcPrefix)
+	r, err := convertPBRequest(req, auth.SrcPrefix(ctx, e.srcPrefix))
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
@@ -62,10 +68,60 @@ func (e *execServer) Exec(ctx context.Context, req *pb.Request) (*pb.Response, e
 	return resp, nil
 }
 
-func (e *execServer) FileList(c context.Context, n *emptypb.Empty) (*pb.FileListType, error) {
-	return &pb.FileListType{
-		FileIDs: e.fs.List(),
-	}, nil
+func (e *execServer) FileList(c context.Context, r *pb.FileListRequest) (*pb.FileListType, error) {
+	if !r.GetMeta() && len(r.GetLabels()) == 0 && r.GetOlderThan() == 0 && r.GetNewerThan() == 0 {
+		return &pb.FileListType{
+			FileIDs: e.fs.List(),
+		}, nil
+	}
+
+	files := filestore.ListMeta(e.fs, &filestore.Filter{
+		Labels:    r.GetLabels(),
+		OlderThan: time.Duration(r.GetOlderThan()),
+		NewerThan: time.Duration(r.GetNewerThan()),
+	})
+	ret := &pb.FileListType{
+		FileIDs: make(map[string]string, len(files)),
+	}
+	if r.GetMeta() {
+		ret.Files = make(map[string]*pb.FileMeta, len(files))
+	}
+	for id, m := range files {
+		ret.FileIDs[id] = m.Name
+		if r.GetMeta() {
+			ret.Files[id] = convertPBFileMeta(m)
+		}
+	}
+	return ret, nil
+}
+
+func fileStoreCode(err error) codes.Code {
+	if errors.Is(err, filestore.ErrStoreFull) {
+		return codes.ResourceExhausted
+	}
+	return codes.Internal
+}
+
+// bundleCode returns the status code of the error to add a bundle, archives
+// failed to extract are invalid
+func bundleCode(err error) codes.Code {
+	if errors.Is(err, filestore.ErrInvalidBundle) {
+		return codes.InvalidArgument
+	}
+	return fileStoreCode(err)
+}
+
+func convertPBFileMeta(m filestore.FileMeta) *pb.FileMeta {
+	return &pb.FileMeta{
+		Name:     m.Name,
+		Size:     m.Size,
+		Sha256:   m.SHA256,
+		Created:  timestamppb.New(m.Created),
+		Accessed: timestamppb.New(m.Accessed),
+		Labels:   m.Labels,
+		Leases:   int32(m.Leases),
+		Bundle:   m.Bundle,
+	}
 }
 
 func (e *execServer) FileGet(c context.Context, f *pb.FileID) (*pb.FileContent, error) {
@@ -73,6 +129,17 @@ func (e *execServer) FileGet(c context.Context, f *pb.FileID) (*pb.FileContent,
 	if file == nil {
 		return nil, status.Errorf(codes.NotFound, "file not found: %q", f.GetFileID())
 	}
+	if dir, ok := e.bundleDir(f.GetFileID(), file); ok {
+		var buf bytes.Buffer
+		if err := filestore.WriteBundle(&buf, dir); err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		return &pb.FileContent{
+			Name:    name,
+			Content: buf.Bytes(),
+			Bundle:  true,
+		}, nil
+	}
 	r, err := envexec.FileToReader(file)
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
@@ -89,19 +156,61 @@ func (e *execServer) FileGet(c context.Context, f *pb.FileID) (*pb.FileContent,
 	}, nil
 }
 
+// bundleDir returns the directory of the file if it is a bundle
+func (e *execServer) bundleDir(id string, file envexec.File) (string, bool) {
+	fi, ok := file.(*envexec.FileInput)
+	if !ok {
+		return "", false
+	}
+	m, ok := e.fs.Stat(id)
+	return fi.Path, ok && m.Bundle
+}
+
 func (e *execServer) FileAdd(c context.Context, fc *pb.FileContent) (*pb.FileID, error) {
-	f, err := e.fs.New()
-	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+	if digest := fc.GetSha256(); digest != "" {
+		ok, err := filestore.AddRef(e.fs, digest)
+		if errors.Is(err, filestore.ErrNotContentAddressed) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		if !ok {
+			return nil, status.Errorf(codes.NotFound, "file id does not exists: %q", digest)
+		}
+		e.fs.SetLabels(digest, fc.GetLabels())
+		if fc.GetPin() {
+			filestore.Pin(e.fs, digest)
+		}
+		return &pb.FileID{FileID: digest}, nil
 	}
-	defer f.Close()
+	var fid string
+	if fc.GetBundle() {
+		content := fc.GetContent()
+		id, err := filestore.AddBundle(e.fs, fc.GetName(), bytes.NewReader(content), int64(len(content)), e.bundleLimit)
+		if err != nil {
+			return nil, status.Error(bundleCode(err), err.Error())
+		}
+		fid = id
+	} else {
+		f, err := e.fs.New()
+		if err != nil {
+			return nil, status.Error(fileStoreCode(err), err.Error())
+		}
+		defer f.Close()
 
-	if _, err := f.Write(fc.GetContent()); err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		if _, err := f.Write(fc.GetContent()); err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		id, err := e.fs.Add(fc.GetName(), f.Name())
+		if err != nil {
+			return nil, status.Error(fileStoreCode(err), err.Error())
+		}
+		fid = id
 	}
-	fid, err := e.fs.Add(fc.GetName(), f.Name())
-	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+	e.fs.SetLabels(fid, fc.GetLabels())
+	if fc.GetPin() {
+		filestore.Pin(e.fs, fid)
 	}
 	return &pb.FileID{
 		FileID: fid,
@@ -134,19 +243,114 @@ func convertPBResponse(r model.Response) (*pb.Response, error) {
 
 func convertPBResult(r model.Result) (*pb.Response_Result, error) {
 	return &pb.Response_Result{
-		Status:     pb.Response_Result_StatusType(r.Status),
-		ExitStatus: int32(r.ExitStatus),
-		Error:      r.Error,
-		Time:       r.Time,
-		RunTime:    r.RunTime,
-		Memory:     r.Memory,
-		ProcPeak:   r.ProcPeak,
-		Files:      r.Buffs,
-		FileIDs:    r.FileIDs,
-		FileError:  convertPBFileError(r.FileError),
+		Status:           pb.Response_Result_StatusType(r.Status),
+		ExitStatus:       int32(r.ExitStatus),
+		Error:            r.Error,
+		Time:             r.Time,
+		RunTime:          r.RunTime,
+		Memory:           r.Memory,
+		ProcPeak:         r.ProcPeak,
+		Files:            r.Buffs,
+		FileIDs:          r.FileIDs,
+		FileError:        convertPBFileError(r.FileError),
+		Syscalls:         r.Syscalls,
+		DiskUsage:        r.DiskUsage,
+		IoRead:           r.IORead,
+		IoWrite:          r.IOWrite,
+		MemoryHighEvents: r.MemoryHighEvents,
+		Crash:            convertPBCrash(r.Crash),
+		Processes:        convertPBProcesses(r.Processes),
+		Perf:             convertPBPerf(r.Perf),
+		Attempts:         convertPBAttempts(r.Attempts),
+		MinTime:          r.MinTime,
+		MedianTime:       r.MedianTime,
+		Limits:           convertPBLimits(r.Limits),
 	}, nil
 }
 
+func convertPBLimits(l *model.Limits) *pb.Response_Limits {
+	if l == nil {
+		return nil
+	}
+	return &pb.Response_Limits{
+		CpuLimit:       l.CPULimit,
+		ClockLimit:     l.ClockLimit,
+		MemoryLimit:    l.MemoryLimit,
+		StackLimit:     l.StackLimit,
+		OutputLimit:    l.OutputLimit,
+		ProcLimit:      l.ProcLimit,
+		OpenFileLimit:  l.OpenFileLimit,
+		CpuRateLimit:   l.CPURateLimit,
+		DiskLimit:      l.DiskLimit,
+		FileCountLimit: l.FileCountLimit,
+		CopyOutMax:     l.CopyOutMax,
+		IoReadBps:      l.IOReadBps,
+		IoWriteBps:     l.IOWriteBps,
+		IoReadIops:     l.IOReadIOPS,
+		IoWriteIops:    l.IOWriteIOPS,
+	}
+}
+
+func convertPBAttempts(as []model.Attempt) []*pb.Response_Attempt {
+	if as == nil {
+		return nil
+	}
+	rt := make([]*pb.Response_Attempt, 0, len(as))
+	for _, a := range as {
+		rt = append(rt, &pb.Response_Attempt{
+			Status:     pb.Response_Result_StatusType(a.Status),
+			ExitStatus: int32(a.ExitStatus),
+			Time:       a.Time,
+			RunTime:    a.RunTime,
+			Memory:     a.Memory,
+		})
+	}
+	return rt
+}
+
+func convertPBPerf(p *model.PerfStat) *pb.Response_PerfStat {
+	if p == nil {
+		return nil
+	}
+	return &pb.Response_PerfStat{
+		TaskClock:    p.TaskClock,
+		Instructions: p.Instructions,
+		Cycles:       p.Cycles,
+		Unsupported:  p.Unsupported,
+	}
+}
+
+func convertPBProcesses(ps []model.ProcessInfo) []*pb.Response_ProcessInfo {
+	if ps == nil {
+		return nil
+	}
+	rt := make([]*pb.Response_ProcessInfo, 0, len(ps))
+	for _, p := range ps {
+		rt = append(rt, &pb.Response_ProcessInfo{
+			Pid:        int32(p.Pid),
+			Ppid:       int32(p.PPid),
+			Args:       p.Args,
+			Start:      p.Start,
+			End:        p.End,
+			ExitStatus: int32(p.ExitStatus),
+			Signal:     p.Signal,
+		})
+	}
+	return rt
+}
+
+func convertPBCrash(c *model.Crash) *pb.Response_Crash {
+	if c == nil {
+		return nil
+	}
+	return &pb.Response_Crash{
+		Signal:     c.Signal,
+		Addr:       c.Addr,
+		Stack:      c.Stack,
+		CoreFileID: c.CoreFileID,
+	}
+}
+
 func convertPBFileError(fe []envexec.FileError) []*pb.Response_FileError {
 	rt := make([]*pb.Response_FileError, 0, len(fe))
 	for _, e := range fe {
@@ -201,16 +405,39 @@ func convertPBCmd(c *pb.Request_CmdType, srcPrefix []string) (cm worker.Cmd, err
 		CPULimit:          time.Duration(c.GetCpuTimeLimit()),
 		ClockLimit:        time.Duration(c.GetClockTimeLimit()),
 		MemoryLimit:       envexec.Size(c.GetMemoryLimit()),
+		MemoryHigh:        envexec.Size(c.GetMemoryHigh()),
 		StackLimit:        envexec.Size(c.GetStackLimit()),
 		ProcLimit:         c.GetProcLimit(),
 		CPURateLimit:      c.GetCpuRateLimit(),
 		CPUSetLimit:       c.GetCpuSetLimit(),
+		DiskLimit:         envexec.Size(c.GetDiskLimit()),
+		FileCountLimit:    c.GetFileCountLimit(),
+		IOReadBps:         envexec.Size(c.GetIoReadBps()),
+		IOWriteBps:        envexec.Size(c.GetIoWriteBps()),
+		IOReadIOPS:        c.GetIoReadIops(),
+		IOWriteIOPS:       c.GetIoWriteIops(),
 		DataSegmentLimit:  c.GetDataSegmentLimit(),
 		AddressSpaceLimit: c.GetAddressSpaceLimit(),
+		SeccompAudit:      c.GetSeccompAudit(),
+		SyscallTrace:      c.GetSyscallTrace(),
+		SyscallTraceMax:   c.GetSyscallTraceMax(),
+		CoreDump:          c.GetCoreDump(),
+		CoreDumpMax:       c.GetCoreDumpMax(),
+		TraceProcess:      c.GetTraceProcess(),
+		Perf:              c.GetPerf(),
+		Repeat:            int(c.GetRepeat()),
+		RepeatThreshold:   c.GetRepeatThreshold(),
+		ScaleTime:         c.GetScaleTime(),
+		Network:           envexec.NetworkMode(c.GetNetwork()),
+		Role:              worker.CmdRole(c.GetRole()),
+		ReadyPort:         int(c.GetReadyPort()),
+		ReadyFile:         c.GetReadyFile(),
+		ReadyTimeout:      time.Duration(c.GetReadyTimeout()),
 		CopyOut:           convertCopyOut(c.GetCopyOut()),
 		CopyOutCached:     convertCopyOut(c.GetCopyOutCached()),
 		CopyOutMax:        c.GetCopyOutMax(),
 		CopyOutDir:        c.GetCopyOutDir(),
+		CopyOutBundle:     c.GetCopyOutBundle(),
 		Symlinks:          c.GetSymlinks(),
 	}
 	for _, f := range c.GetFiles() {
@@ -227,6 +454,9 @@ func convertPBCmd(c *pb.Request_CmdType, srcPrefix []string) (cm worker.Cmd, err
 			if err != nil {
 				return cm, err
 			}
+			if f.GetExtract() {
+				cf = &worker.ArchiveFile{File: cf}
+			}
 			cm.CopyIn[k] = cf
 		}
 	}
@@ -264,6 +494,7 @@ func convertCopyOut(copyOut []*pb.Request_CmdCopyOutFile) []worker.CmdCopyOutFil
 		rt = append(rt, worker.CmdCopyOutFile{
 			Name:     n.GetName(),
 			Optional: n.GetOptional(),
+			Archive:  n.GetArchive(),
 		})
 	}
 	return rt
@@ -0,0 +1,147 @@
+package grpcexecutor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+
+	"github.com/criyle/go-judge/envexec"
+	"github.com/criyle/go-judge/filestore"
+	"github.com/criyle/go-judge/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fileChunkSize is the content size of each download chunk, under the default
+// gRPC message size of clients
+const fileChunkSize = 1 << 20
+
+func (e *execServer) FileUpload(s pb.Executor_FileUploadServer) error {
+	req, err := s.Recv()
+	if err != nil {
+		return err
+	}
+	header := req.GetHeader()
+	if header == nil {
+		return status.Error(codes.InvalidArgument, "upload: the first chunk must be header")
+	}
+
+	f, err := e.fs.New()
+	if err != nil {
+		return status.Error(fileStoreCode(err), err.Error())
+	}
+	defer f.Close()
+
+	if err := recvFileContent(s, f); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	var fid string
+	if header.GetBundle() {
+		// the received archive is extracted into a new bundle
+		defer os.Remove(f.Name())
+		fi, err := f.Stat()
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		if fid, err = filestore.AddBundle(e.fs, header.GetName(), f, fi.Size(), e.bundleLimit); err != nil {
+			return status.Error(bundleCode(err), err.Error())
+		}
+	} else if fid, err = e.fs.Add(header.GetName(), f.Name()); err != nil {
+		os.Remove(f.Name())
+		return status.Error(fileStoreCode(err), err.Error())
+	}
+	e.fs.SetLabels(fid, header.GetLabels())
+	if header.GetPin() {
+		filestore.Pin(e.fs, fid)
+	}
+	return s.SendAndClose(&pb.FileID{
+		FileID: fid,
+	})
+}
+
+// recvFileContent writes the content chunks into w until the trailer and
+// verifies the digest in the trailer
+func recvFileContent(s pb.Executor_FileUploadServer, w io.Writer) error {
+	h := sha256.New()
+	w = io.MultiWriter(w, h)
+	for {
+		req, err := s.Recv()
+		if errors.Is(err, io.EOF) {
+			return status.Error(codes.InvalidArgument, "upload: ended without trailer")
+		}
+		if err != nil {
+			return err
+		}
+		switch c := req.GetChunk().(type) {
+		case *pb.FileChunk_Content:
+			if _, err := w.Write(c.Content); err != nil {
+				return status.Error(codes.Internal, err.Error())
+			}
+		case *pb.FileChunk_Trailer_:
+			digest := hex.EncodeToString(h.Sum(nil))
+			if c.Trailer.GetSha256() != digest {
+				return status.Errorf(codes.DataLoss, "upload: sha256 mismatch: expected %q, got %q", c.Trailer.GetSha256(), digest)
+			}
+			return nil
+		default:
+			return status.Error(codes.InvalidArgument, "upload: unexpected chunk after header")
+		}
+	}
+}
+
+func (e *execServer) FileDownload(f *pb.FileID, s pb.Executor_FileDownloadServer) error {
+	name, file := e.fs.Get(f.GetFileID())
+	if file == nil {
+		return status.Errorf(codes.NotFound, "file not found: %q", f.GetFileID())
+	}
+	var (
+		r   io.ReadCloser
+		err error
+	)
+	header := &pb.FileChunk_Header{Name: name}
+	if dir, ok := e.bundleDir(f.GetFileID(), file); ok {
+		// bundle is sent as tar archive without size
+		pr, pw := io.Pipe()
+		go func() {
+			pw.CloseWithError(filestore.WriteBundle(pw, dir))
+		}()
+		r = pr
+		header.Bundle = true
+	} else if r, err = envexec.FileToReader(file); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	defer r.Close()
+
+	if of, ok := r.(*os.File); ok {
+		if fi, err := of.Stat(); err == nil {
+			header.Size = fi.Size()
+		}
+	}
+	if err := s.Send(&pb.FileChunk{Chunk: &pb.FileChunk_Header_{Header: header}}); err != nil {
+		return err
+	}
+
+	h := sha256.New()
+	buf := make([]byte, fileChunkSize)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			h.Write(buf[:n])
+			if err := s.Send(&pb.FileChunk{Chunk: &pb.FileChunk_Content{Content: buf[:n]}}); err != nil {
+				return err
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+	}
+	return s.Send(&pb.FileChunk{Chunk: &pb.FileChunk_Trailer_{Trailer: &pb.FileChunk_Trailer{
+		Sha256: hex.EncodeToString(h.Sum(nil)),
+	}}})
+}
@@ -3,6 +3,7 @@ package grpcexecutor
 import (
 	"errors"
 
+	"github.com/criyle/go-judge/cmd/go-judge/auth"
 	"github.com/criyle/go-judge/cmd/go-judge/model"
 	"github.com/criyle/go-judge/cmd/go-judge/stream"
 	"github.com/criyle/go-judge/pb"
@@ -125,7 +126,9 @@ func convertPBStreamCopyIn(cmd *pb.Request_CmdType) map[string]model.CmdFile {
 		if i.File == nil {
 			continue
 		}
-		rt[k] = convertPBStreamFile(i)
+		f := convertPBStreamFile(i)
+		f.Extract = i.Extract
+		rt[k] = f
 	}
 	for k, v := range cmd.Symlinks {
 		rt[k] = model.CmdFile{Symlink: &v}
@@ -156,6 +159,9 @@ func convertStreamCopyOut(copyOut []*pb.Request_CmdCopyOutFile) []string {
 	rt := make([]string, 0, len(copyOut))
 	for _, n := range copyOut {
 		name := n.Name
+		if n.Archive {
+			name += "/"
+		}
 		if n.Optional {
 			name += "?"
 		}
@@ -168,7 +174,7 @@ func (e *execServer) ExecStream(es pb.Executor_ExecStreamServer) error {
 	w := &streamWrapper{
 		es: es,
 	}
-	if err := stream.Start(es.Context(), w, e.worker, e.srcPrefix, e.logger); err != nil {
+	if err := stream.Start(es.Context(), w, e.worker, auth.SrcPrefix(es.Context(), e.srcPrefix), e.logger); err != nil {
 		return status.Error(codes.Internal, err.Error())
 	}
 	return nil
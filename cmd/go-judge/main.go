@@ -4,10 +4,12 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"flag"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"net/http/pprof"
 	"os"
@@ -15,12 +17,14 @@ import (
 	"path/filepath"
 	"runtime"
 	"runtime/debug"
-	"strings"
+	"syscall"
 	"time"
 
+	"github.com/criyle/go-judge/cmd/go-judge/auth"
 	"github.com/criyle/go-judge/cmd/go-judge/config"
 	grpcexecutor "github.com/criyle/go-judge/cmd/go-judge/grpc_executor"
 	restexecutor "github.com/criyle/go-judge/cmd/go-judge/rest_executor"
+	"github.com/criyle/go-judge/cmd/go-judge/tlsconfig"
 	"github.com/criyle/go-judge/cmd/go-judge/version"
 	wsexecutor "github.com/criyle/go-judge/cmd/go-judge/ws_executor"
 	"github.com/criyle/go-judge/env"
@@ -32,7 +36,6 @@ import (
 	ginzap "github.com/gin-contrib/zap"
 	"github.com/gin-gonic/gin"
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-middleware/providers/prometheus"
-	grpc_auth "github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/auth"
 	grpc_logging "github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/logging"
 	grpc_recovery "github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/recovery"
 	"github.com/prometheus/client_golang/prometheus"
@@ -43,9 +46,8 @@ import (
 	"go.uber.org/zap/zapgrpc"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/grpclog"
-	"google.golang.org/grpc/status"
 )
 
 var logger *zap.Logger
@@ -68,20 +70,27 @@ func main() {
 	b, builderParam := newEnvBuilder(conf)
 	envPool := newEnvPool(b, conf.EnableMetrics)
 	prefork(envPool, conf.PreFork)
-	work := newWorker(conf, envPool, fs)
+	speed := &speedCalibrator{reference: conf.SpeedReference}
+	work := newWorker(conf, envPool, fs, speed.Factor)
 	work.Start()
+	speed.work = work
 	logger.Info("Worker stated ",
 		zap.Int("parallelism", conf.Parallelism),
 		zap.String("dir", conf.Dir),
 		zap.Duration("timeLimitCheckInterval", conf.TimeLimitCheckerInterval))
+	if conf.Calibrate {
+		calibrate(speed)
+	}
 	initCgroupMetrics(conf, builderParam)
+	authenticator := newAuthenticator(conf)
+	tlsConf := newTLSConfig(conf)
 
 	servers := []initFunc{
 		cleanUpWorker(work),
-		cleanUpFs(fsCleanUp),
-		initHTTPServer(conf, work, fs, builderParam),
-		initMonitorHTTPServer(conf),
-		initGRPCServer(conf, work, fs),
+		cleanUpFs(fs, fsCleanUp),
+		initHTTPServer(conf, work, fs, speed, authenticator, tlsConf, builderParam),
+		initMonitorHTTPServer(conf, tlsConf),
+		initGRPCServer(conf, work, fs, authenticator, tlsConf),
 	}
 
 	// Gracefully shutdown, with signal / HTTP server / gRPC server / Monitor HTTP server
@@ -164,12 +173,14 @@ func cleanUpWorker(work worker.Worker) initFunc {
 	}
 }
 
-func cleanUpFs(fsCleanUp func() error) initFunc {
+func cleanUpFs(fs filestore.FileStore, fsCleanUp func() error) initFunc {
 	return func() (start func(), cleanUp stopFunc) {
-		if fsCleanUp == nil {
-			return nil, nil
-		}
 		return nil, func(ctx context.Context) error {
+			if fsCleanUp == nil {
+				err := filestore.Flush(fs)
+				logger.Info("FileStore flushed")
+				return err
+			}
 			err := fsCleanUp()
 			logger.Info("FileStore cleaned up")
 			return err
@@ -177,13 +188,14 @@ func cleanUpFs(fsCleanUp func() error) initFunc {
 	}
 }
 
-func initHTTPServer(conf *config.Config, work worker.Worker, fs filestore.FileStore, builderParam map[string]any) initFunc {
+func initHTTPServer(conf *config.Config, work worker.Worker, fs filestore.FileStore, speed *speedCalibrator, authenticator *auth.Authenticator, tlsConf *tls.Config, builderParam map[string]any) initFunc {
 	return func() (start func(), cleanUp stopFunc) {
 		// Init http handle
-		r := initHTTPMux(conf, work, fs, builderParam)
+		r := initHTTPMux(conf, work, fs, speed, authenticator, builderParam)
 		srv := http.Server{
-			Addr:    conf.HTTPAddr,
-			Handler: r,
+			Addr:      conf.HTTPAddr,
+			Handler:   r,
+			TLSConfig: tlsConf,
 		}
 
 		return func() {
@@ -192,8 +204,8 @@ func initHTTPServer(conf *config.Config, work worker.Worker, fs filestore.FileSt
 					logger.Error("Http server listen failed", zap.Error(err))
 					return
 				}
-				logger.Info("Starting http server", zap.String("addr", conf.HTTPAddr), zap.String("listener", printListener(lis)))
-				if err := srv.Serve(lis); errors.Is(err, http.ErrServerClosed) {
+				logger.Info("Starting http server", zap.String("addr", conf.HTTPAddr), zap.String("listener", printListener(lis)), zap.Bool("tls", tlsConf != nil))
+				if err := serveHTTP(&srv, lis); errors.Is(err, http.ErrServerClosed) {
 					logger.Info("Http server stopped", zap.Error(err))
 				} else {
 					logger.Error("Http server stopped", zap.Error(err))
@@ -205,7 +217,7 @@ func initHTTPServer(conf *config.Config, work worker.Worker, fs filestore.FileSt
 	}
 }
 
-func initMonitorHTTPServer(conf *config.Config) initFunc {
+func initMonitorHTTPServer(conf *config.Config, tlsConf *tls.Config) initFunc {
 	return func() (start func(), cleanUp stopFunc) {
 		// Init monitor HTTP server
 		mr := initMonitorHTTPMux(conf)
@@ -213,8 +225,9 @@ func initMonitorHTTPServer(conf *config.Config) initFunc {
 			return nil, nil
 		}
 		msrv := http.Server{
-			Addr:    conf.MonitorAddr,
-			Handler: mr,
+			Addr:      conf.MonitorAddr,
+			Handler:   mr,
+			TLSConfig: tlsConf,
 		}
 		return func() {
 				lis, err := newListener(conf.MonitorAddr)
@@ -222,8 +235,8 @@ func initMonitorHTTPServer(conf *config.Config) initFunc {
 					logger.Error("Monitoring http listen failed", zap.Error(err))
 					return
 				}
-				logger.Info("Starting monitoring http server", zap.String("addr", conf.MonitorAddr), zap.String("listener", printListener(lis)))
-				logger.Info("Monitoring http server stopped", zap.Error(msrv.Serve(lis)))
+				logger.Info("Starting monitoring http server", zap.String("addr", conf.MonitorAddr), zap.String("listener", printListener(lis)), zap.Bool("tls", tlsConf != nil))
+				logger.Info("Monitoring http server stopped", zap.Error(serveHTTP(&msrv, lis)))
 			}, func(ctx context.Context) error {
 				logger.Info("Monitoring http server shutdown")
 				return msrv.Shutdown(ctx)
@@ -231,14 +244,14 @@ func initMonitorHTTPServer(conf *config.Config) initFunc {
 	}
 }
 
-func initGRPCServer(conf *config.Config, work worker.Worker, fs filestore.FileStore) initFunc {
+func initGRPCServer(conf *config.Config, work worker.Worker, fs filestore.FileStore, authenticator *auth.Authenticator, tlsConf *tls.Config) initFunc {
 	return func() (start func(), cleanUp stopFunc) {
 		if !conf.EnableGRPC {
 			return nil, nil
 		}
 		// Init gRPC server
-		esServer := grpcexecutor.New(work, fs, conf.SrcPrefix, logger)
-		grpcServer := newGRPCServer(conf, esServer)
+		esServer := grpcexecutor.New(work, fs, conf.SrcPrefix, bundleLimit(conf), logger)
+		grpcServer := newGRPCServer(conf, esServer, authenticator, tlsConf)
 
 		return func() {
 				lis, err := newListener(conf.GRPCAddr)
@@ -246,7 +259,7 @@ func initGRPCServer(conf *config.Config, work worker.Worker, fs filestore.FileSt
 					logger.Error("gRPC listen failed: ", zap.Error(err))
 					return
 				}
-				logger.Info("Starting gRPC server", zap.String("addr", conf.GRPCAddr), zap.String("listener", printListener(lis)))
+				logger.Info("Starting gRPC server", zap.String("addr", conf.GRPCAddr), zap.String("listener", printListener(lis)), zap.Bool("tls", tlsConf != nil))
 				logger.Info("gRPC server stopped", zap.Error(grpcServer.Serve(lis)))
 			}, func(ctx context.Context) error {
 				grpcServer.GracefulStop()
@@ -296,13 +309,21 @@ func prefork(envPool worker.EnvironmentPool, prefork int) {
 	}
 }
 
-func initHTTPMux(conf *config.Config, work worker.Worker, fs filestore.FileStore, builderParam map[string]any) http.Handler {
+func initHTTPMux(conf *config.Config, work worker.Worker, fs filestore.FileStore, speed *speedCalibrator, authenticator *auth.Authenticator, builderParam map[string]any) http.Handler {
 	var r *gin.Engine
 	if conf.Release {
 		gin.SetMode(gin.ReleaseMode)
 	}
 	r = gin.New()
-	r.Use(ginzap.Ginzap(logger, "", false))
+	r.Use(ginzap.GinzapWithConfig(logger, &ginzap.Config{
+		DefaultLevel: zapcore.InfoLevel,
+		Context: func(c *gin.Context) []zapcore.Field {
+			if t := auth.FromContext(c.Request.Context()); t != nil {
+				return []zapcore.Field{zap.String("token", t.Name)}
+			}
+			return nil
+		},
+	}))
 	r.Use(ginzap.RecoveryWithZap(logger, true))
 
 	// Metrics Handle
@@ -314,20 +335,23 @@ func initHTTPMux(conf *config.Config, work worker.Worker, fs filestore.FileStore
 	r.GET("/version", generateHandleVersion(conf, builderParam))
 
 	// Config handle
-	r.GET("/config", generateHandleConfig(conf, builderParam))
+	r.GET("/config", generateHandleConfig(conf, speed, builderParam))
 
 	// Add auth token
-	if conf.AuthToken != "" {
-		r.Use(tokenAuth(conf.AuthToken))
-		logger.Info("Attach token auth", zap.String("token", conf.AuthToken))
+	if authenticator != nil {
+		r.Use(authenticator.GinMiddleware(httpScope))
+		logger.Info("Attach token auth", zap.Int("tokens", authenticator.Len()))
 	}
 
 	// Rest Handle
 	cmdHandle := restexecutor.NewCmdHandle(work, conf.SrcPrefix, logger)
 	cmdHandle.Register(r)
-	fileHandle := restexecutor.NewFileHandle(fs)
+	fileHandle := restexecutor.NewFileHandle(fs, bundleLimit(conf))
 	fileHandle.Register(r)
 
+	// Calibrate handle
+	r.POST("/calibrate", generateHandleCalibrate(speed))
+
 	// WebSocket Handle
 	wsHandle := wsexecutor.New(work, conf.SrcPrefix, logger)
 	wsHandle.Register(r)
@@ -394,29 +418,37 @@ func InterceptorLogger(l *zap.Logger) grpc_logging.Logger {
 	})
 }
 
-func newGRPCServer(conf *config.Config, esServer pb.ExecutorServer) *grpc.Server {
+func newGRPCServer(conf *config.Config, esServer pb.ExecutorServer, authenticator *auth.Authenticator, tlsConf *tls.Config) *grpc.Server {
 	prom := grpc_prometheus.NewServerMetrics(grpc_prometheus.WithServerHandlingTimeHistogram())
 	grpclog.SetLoggerV2(zapgrpc.NewLogger(logger))
 	streamMiddleware := []grpc.StreamServerInterceptor{
 		prom.StreamServerInterceptor(),
-		grpc_logging.StreamServerInterceptor(InterceptorLogger(logger)),
-		grpc_recovery.StreamServerInterceptor(),
 	}
 	unaryMiddleware := []grpc.UnaryServerInterceptor{
 		prom.UnaryServerInterceptor(),
-		grpc_logging.UnaryServerInterceptor(InterceptorLogger(logger)),
-		grpc_recovery.UnaryServerInterceptor(),
 	}
-	if conf.AuthToken != "" {
-		authFunc := grpcTokenAuth(conf.AuthToken)
-		streamMiddleware = append(streamMiddleware, grpc_auth.StreamServerInterceptor(authFunc))
-		unaryMiddleware = append(unaryMiddleware, grpc_auth.UnaryServerInterceptor(authFunc))
+	// auth before logging to log with the token name
+	if authenticator != nil {
+		streamMiddleware = append(streamMiddleware, authenticator.StreamServerInterceptor(grpcScope))
+		unaryMiddleware = append(unaryMiddleware, authenticator.UnaryServerInterceptor(grpcScope))
 	}
-	grpcServer := grpc.NewServer(
+	streamMiddleware = append(streamMiddleware,
+		grpc_logging.StreamServerInterceptor(InterceptorLogger(logger)),
+		grpc_recovery.StreamServerInterceptor(),
+	)
+	unaryMiddleware = append(unaryMiddleware,
+		grpc_logging.UnaryServerInterceptor(InterceptorLogger(logger)),
+		grpc_recovery.UnaryServerInterceptor(),
+	)
+	opts := []grpc.ServerOption{
 		grpc.ChainStreamInterceptor(streamMiddleware...),
 		grpc.ChainUnaryInterceptor(unaryMiddleware...),
 		grpc.MaxRecvMsgSize(int(conf.GRPCMsgSize.Byte())),
-	)
+	}
+	if tlsConf != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConf)))
+	}
+	grpcServer := grpc.NewServer(opts...)
 	pb.RegisterExecutorServer(grpcServer, esServer)
 	prometheus.MustRegister(prom)
 	return grpcServer
@@ -430,28 +462,100 @@ func initGinMetrics(r *gin.Engine) {
 	r.Use(p.HandlerFunc())
 }
 
-func tokenAuth(token string) gin.HandlerFunc {
-	const bearer = "Bearer "
-	return func(c *gin.Context) {
-		reqToken := c.GetHeader("Authorization")
-		if strings.HasPrefix(reqToken, bearer) && reqToken[len(bearer):] == token {
-			c.Next()
-			return
-		}
-		c.AbortWithStatus(http.StatusUnauthorized)
+// newAuthenticator creates the authenticator from the auth token and the
+// token file, it returns nil if neither is specified
+func newAuthenticator(conf *config.Config) *auth.Authenticator {
+	if conf.AuthToken == "" && conf.AuthTokenFile == "" {
+		return nil
+	}
+	var static []auth.Token
+	if conf.AuthToken != "" {
+		static = append(static, auth.Token{
+			Name:   "default",
+			Token:  conf.AuthToken,
+			Scopes: auth.AllScopes,
+		})
+	}
+	a, err := auth.New(conf.AuthTokenFile, static...)
+	if err != nil {
+		logger.Fatal("Load auth token failed", zap.Error(err))
+	}
+	if conf.EnableMetrics {
+		a.Observer = tokenObserve
 	}
+	reloadOnHangup("auth token", a.Reload)
+	return a
 }
 
-func grpcTokenAuth(token string) func(context.Context) (context.Context, error) {
-	return func(ctx context.Context) (context.Context, error) {
-		reqToken, err := grpc_auth.AuthFromMD(ctx, "bearer")
-		if err != nil {
-			return nil, err
+// newTLSConfig creates the TLS config for servers from the certificate files,
+// it returns nil to serve plaintext if no certificate is specified
+func newTLSConfig(conf *config.Config) *tls.Config {
+	if conf.TLSCert == "" && conf.TLSKey == "" {
+		return nil
+	}
+	s, err := tlsconfig.NewServer(conf.TLSCert, conf.TLSKey, conf.TLSClientCA, conf.TLSRequireClientCert)
+	if err != nil {
+		logger.Fatal("Load TLS certificate failed", zap.Error(err))
+	}
+	reloadOnHangup("TLS certificate", s.Reload)
+	return s.Config()
+}
+
+// reloadOnHangup calls reload when SIGHUP is received
+func reloadOnHangup(name string, reload func() error) {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGHUP)
+	go func() {
+		for range sig {
+			if err := reload(); err != nil {
+				logger.Error("Reload "+name+" failed", zap.Error(err))
+				continue
+			}
+			logger.Info("Reloaded " + name)
 		}
-		if reqToken != token {
-			return nil, status.Errorf(codes.Unauthenticated, "invalid auth token: %v", err)
+	}()
+}
+
+// serveHTTP serves over TLS if the TLS config is specified
+func serveHTTP(srv *http.Server, lis net.Listener) error {
+	if srv.TLSConfig != nil {
+		return srv.ServeTLS(lis, "", "")
+	}
+	return srv.Serve(lis)
+}
+
+// httpScope returns the scope required by the route
+func httpScope(c *gin.Context) auth.Scope {
+	switch c.FullPath() {
+	case "/run", "/ws":
+		return auth.ScopeRun
+	case "/stream":
+		return auth.ScopeStream
+	case "/file", "/file/:fid", "/file/:fid/meta":
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			return auth.ScopeFileRead
 		}
-		return ctx, nil
+		return auth.ScopeFileWrite
+	default:
+		return auth.ScopeAdmin
+	}
+}
+
+// grpcScope returns the scope required by the method
+func grpcScope(method string) auth.Scope {
+	switch method {
+	case pb.Executor_Exec_FullMethodName:
+		return auth.ScopeRun
+	case pb.Executor_ExecStream_FullMethodName:
+		return auth.ScopeStream
+	case pb.Executor_FileList_FullMethodName, pb.Executor_FileGet_FullMethodName,
+		pb.Executor_FileDownload_FullMethodName:
+		return auth.ScopeFileRead
+	case pb.Executor_FileAdd_FullMethodName, pb.Executor_FileUpload_FullMethodName,
+		pb.Executor_FileDelete_FullMethodName:
+		return auth.ScopeFileWrite
+	default:
+		return auth.ScopeAdmin
 	}
 }
 
@@ -477,16 +581,59 @@ func newFileStore(conf *config.Config) (filestore.FileStore, func() error) {
 		}
 	}
 	os.MkdirAll(conf.Dir, 0o755)
-	fs = filestore.NewFileLocalStore(conf.Dir)
+	switch conf.FileStore {
+	case "local":
+		fs = filestore.NewFileLocalStore(conf.Dir)
+	case "content":
+		var err error
+		fs, err = filestore.NewFileContentStore(conf.Dir)
+		if err != nil {
+			logger.Fatal("Failed to create content file store", zap.Error(err))
+		}
+	case "s3":
+		// eviction and expiry would remove the objects shared by other nodes,
+		// the lifecycle rules of the bucket should be used instead
+		if conf.FileStoreMaxSize.Byte() > 0 || conf.FileStoreMaxCount > 0 || conf.FileTimeout > 0 {
+			logger.Fatal("File store capacity limits and file timeout are not supported by s3 file store")
+		}
+		var err error
+		fs, err = filestore.NewFileS3Store(conf.Dir, filestore.S3Config{
+			Endpoint:     conf.FileStoreEndpoint,
+			Bucket:       conf.FileStoreBucket,
+			Prefix:       conf.FileStorePrefix,
+			Region:       conf.FileStoreRegion,
+			AccessKey:    conf.FileStoreAccessKey,
+			SecretKey:    conf.FileStoreSecretKey,
+			Insecure:     conf.FileStoreInsecure,
+			CacheMaxSize: int64(conf.FileStoreCacheSize.Byte()),
+			BundleLimit:  bundleLimit(conf),
+		})
+		if err != nil {
+			logger.Fatal("Failed to create s3 file store", zap.Error(err))
+		}
+	default:
+		logger.Fatal("Unknown file store type", zap.String("type", conf.FileStore))
+	}
 	if conf.EnableMetrics {
 		fs = newMetricsFileStore(fs)
 	}
+	if conf.FileStoreMaxSize.Byte() > 0 || conf.FileStoreMaxCount > 0 {
+		fs = filestore.NewLRU(fs, int64(conf.FileStoreMaxSize.Byte()), conf.FileStoreMaxCount)
+	}
 	if conf.FileTimeout > 0 {
 		fs = filestore.NewTimeout(fs, conf.FileTimeout, timeoutCheckInterval)
 	}
 	return fs, cleanUp
 }
 
+func bundleLimit(conf *config.Config) filestore.BundleLimit {
+	return filestore.BundleLimit{
+		MaxEntries:   conf.BundleMaxEntries,
+		MaxEntrySize: int64(conf.BundleMaxEntrySize.Byte()),
+		MaxTotalSize: int64(conf.BundleMaxTotalSize.Byte()),
+	}
+}
+
 func newEnvBuilder(conf *config.Config) (pool.EnvBuilder, map[string]any) {
 	b, param, err := env.NewBuilder(env.Config{
 		ContainerInitPath:  conf.ContainerInitPath,
@@ -499,6 +646,7 @@ func newEnvBuilder(conf *config.Config) (pool.EnvBuilder, map[string]any) {
 		EnableCPURate:      conf.EnableCPURate,
 		CPUCfsPeriod:       conf.CPUCfsPeriod,
 		SeccompConf:        conf.SeccompConf,
+		SeccompAudit:       conf.SeccompAudit,
 		NoFallback:         conf.NoFallback,
 	}, logger)
 	if err != nil {
@@ -518,7 +666,33 @@ func newEnvPool(b pool.EnvBuilder, enableMetrics bool) worker.EnvironmentPool {
 	return p
 }
 
-func newWorker(conf *config.Config, envPool worker.EnvironmentPool, fs filestore.FileStore) worker.Worker {
+func newWorker(conf *config.Config, envPool worker.EnvironmentPool, fs filestore.FileStore, speedFactor func() float64) worker.Worker {
+	defaultLimits := worker.Limits{
+		CPU:       conf.DefaultCPULimit,
+		Clock:     conf.DefaultClockLimit,
+		Memory:    *conf.DefaultMemoryLimit,
+		Stack:     *conf.DefaultStackLimit,
+		Proc:      uint64(conf.DefaultProcLimit),
+		Disk:      *conf.DefaultDiskLimit,
+		FileCount: uint64(conf.DefaultFileCountLimit),
+	}
+	maxLimits := worker.Limits{
+		CPU:         conf.MaxCPULimit,
+		Clock:       conf.MaxClockLimit,
+		Memory:      *conf.MaxMemoryLimit,
+		Stack:       *conf.MaxStackLimit,
+		Output:      *conf.MaxOutputLimit,
+		Proc:        uint64(conf.MaxProcLimit),
+		OpenFile:    uint64(conf.MaxOpenFileLimit),
+		CPURate:     uint64(conf.MaxCPURateLimit),
+		Disk:        *conf.MaxDiskLimit,
+		FileCount:   uint64(conf.MaxFileCountLimit),
+		CopyOut:     *conf.MaxCopyOut,
+		IOReadBps:   *conf.MaxIOReadBps,
+		IOWriteBps:  *conf.MaxIOWriteBps,
+		IOReadIOPS:  uint64(conf.MaxIOReadIOPS),
+		IOWriteIOPS: uint64(conf.MaxIOWriteIOPS),
+	}
 	w := worker.New(worker.Config{
 		FileStore:             fs,
 		EnvironmentPool:       envPool,
@@ -529,7 +703,20 @@ func newWorker(conf *config.Config, envPool worker.EnvironmentPool, fs filestore
 		OutputLimit:           *conf.OutputLimit,
 		CopyOutLimit:          *conf.CopyOutLimit,
 		OpenFileLimit:         uint64(conf.OpenFileLimit),
+		IOReadBps:             *conf.IOReadBps,
+		IOWriteBps:            *conf.IOWriteBps,
+		IOReadIOPS:            uint64(conf.IOReadIOPS),
+		IOWriteIOPS:           uint64(conf.IOWriteIOPS),
+		EnableSyscallTrace:    conf.EnableSyscallTrace,
+		DefaultLimits:         defaultLimits,
+		MaxLimits:             maxLimits,
+		RejectExceededLimits:  conf.RejectExceededLimit,
+		RepeatThreshold:       conf.RepeatThreshold,
+		RepeatMax:             conf.RepeatMax,
+		SpeedFactor:           speedFactor,
 		ExecObserver:          execObserve,
+		ArchiveLimit:          bundleLimit(conf),
+		NetShare:              conf.NetShare,
 	})
 	if conf.EnableMetrics {
 		w = newMetricsWorker(w)
@@ -568,11 +755,22 @@ func generateHandleVersion(_ *config.Config, _ map[string]any) func(*gin.Context
 			"addressSpaceLimit": true,
 			"stream":            true,
 			"procPeak":          true,
+			"seccompAudit":      true,
+			"syscallTrace":      true,
+			"network":           true,
+			"diskLimit":         true,
+			"ioLimit":           true,
+			"memoryHigh":        true,
+			"coreDump":          true,
+			"traceProcess":      true,
+			"perf":              true,
+			"repeat":            true,
+			"scaleTime":         true,
 		})
 	}
 }
 
-func generateHandleConfig(conf *config.Config, builderParam map[string]any) func(*gin.Context) {
+func generateHandleConfig(conf *config.Config, speed *speedCalibrator, builderParam map[string]any) func(*gin.Context) {
 	return func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
 			"copyOutOptional":   true,
@@ -581,8 +779,35 @@ func generateHandleConfig(conf *config.Config, builderParam map[string]any) func
 			"addressSpaceLimit": true,
 			"stream":            true,
 			"procPeak":          true,
+			"seccompAudit":      conf.SeccompAudit,
+			"syscallTrace":      conf.EnableSyscallTrace,
+			"network":           !conf.NetShare,
 			"fileStorePath":     conf.Dir,
+			"speedFactor":       speed.Factor(),
 			"runnerConfig":      builderParam,
 		})
 	}
 }
+
+func generateHandleCalibrate(speed *speedCalibrator) func(*gin.Context) {
+	return func(c *gin.Context) {
+		factor, t, err := speed.Calibrate(c.Request.Context())
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, err.Error())
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"speedFactor": factor,
+			"time":        t,
+		})
+	}
+}
+
+func calibrate(speed *speedCalibrator) {
+	factor, t, err := speed.Calibrate(context.TODO())
+	if err != nil {
+		logger.Error("Calibration failed", zap.Error(err))
+		return
+	}
+	logger.Info("Calibration finished", zap.Float64("speedFactor", factor), zap.Duration("time", t))
+}
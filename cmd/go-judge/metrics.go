@@ -1,9 +1,11 @@
 package main
 
 import (
+	"errors"
 	"os"
 	"sync"
 
+	"github.com/criyle/go-judge/cmd/go-judge/auth"
 	"github.com/criyle/go-judge/env/pool"
 	"github.com/criyle/go-judge/envexec"
 	"github.com/criyle/go-judge/filestore"
@@ -17,6 +19,7 @@ const (
 	filestoreSubsystem   = "file"
 	environmentSubsystem = "environment"
 	workerSubsystem      = "worker"
+	authSubsystem        = "auth"
 )
 
 var (
@@ -90,6 +93,13 @@ var (
 		Help:      "Total number of environment currently in use",
 	})
 
+	authRequestCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: authSubsystem,
+		Name:      "request_count",
+		Help:      "Number of authenticated requests by token, scope and result",
+	}, []string{"token", "scope", "result"})
+
 	workerQueue = prometheus.NewDesc(
 		prometheus.BuildFQName(metricsNamespace, workerSubsystem, "queue_count"),
 		"Number of requests waiting in worker queue", nil, nil,
@@ -107,6 +117,22 @@ func init() {
 	prometheus.MustRegister(execMemHist)
 	prometheus.MustRegister(fsSizeHist, fsCurrentTotalCount, fsCurrentTotalSize)
 	prometheus.MustRegister(envCreated, envInUse)
+	prometheus.MustRegister(authRequestCount)
+}
+
+func tokenObserve(name string, scope auth.Scope, err error) {
+	result := "ok"
+	switch {
+	case errors.Is(err, auth.ErrUnauthenticated):
+		result = "unauthenticated"
+	case errors.Is(err, auth.ErrForbidden):
+		result = "forbidden"
+	case errors.Is(err, auth.ErrRateLimited):
+		result = "rate_limited"
+	case errors.Is(err, auth.ErrTooManyRequests):
+		result = "too_many_requests"
+	}
+	authRequestCount.WithLabelValues(name, string(scope), result).Inc()
 }
 
 func execObserve(res worker.Response) {
@@ -138,39 +164,37 @@ func newMetricsFileStore(fs filestore.FileStore) filestore.FileStore {
 	}
 	fi := store.List()
 	for id := range fi {
-		_, file := store.Get(id)
-		if file == nil {
+		m, ok := store.Stat(id)
+		if !ok {
 			continue
 		}
-		if f, ok := file.(*envexec.FileInput); ok {
-			info, err := os.Stat(f.Path)
-			if err != nil {
-				continue
-			}
-			store.fileSize[id] = info.Size()
-			sf := float64(info.Size())
-			fsSizeHist.Observe(sf)
-			fsCurrentTotalSize.Add(sf)
-			fsCurrentTotalCount.Inc()
-		}
+		store.fileSize[id] = m.Size
+		sf := float64(m.Size)
+		fsSizeHist.Observe(sf)
+		fsCurrentTotalSize.Add(sf)
+		fsCurrentTotalCount.Inc()
 	}
 	return store
 }
 
 func (m *metricsFileStore) Add(name, path string) (string, error) {
+	// stat before add since the file may be moved by the file store
+	fi, statErr := os.Stat(path)
 	id, err := m.FileStore.Add(name, path)
 	if err != nil {
 		return "", err
 	}
-
-	fi, err := os.Stat(path)
-	if err != nil {
+	if statErr != nil {
 		return id, nil
 	}
 
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	// content-addressed file store stores identical content once
+	if _, ok := m.fileSize[id]; ok {
+		return id, nil
+	}
 	s := fi.Size()
 	m.fileSize[id] = s
 
@@ -182,6 +206,26 @@ func (m *metricsFileStore) Add(name, path string) (string, error) {
 	return id, nil
 }
 
+// AddRef implements filestore.RefFileStore if the underlying file store does
+func (m *metricsFileStore) AddRef(id string) (bool, error) {
+	return filestore.AddRef(m.FileStore, id)
+}
+
+// Lease implements filestore.Leaser by the underlying file store
+func (m *metricsFileStore) Lease(id string) bool {
+	return filestore.Lease(m.FileStore, id)
+}
+
+// Release implements filestore.Leaser by the underlying file store
+func (m *metricsFileStore) Release(id string) {
+	filestore.Release(m.FileStore, id)
+}
+
+// Flush implements filestore.Flusher by the underlying file store
+func (m *metricsFileStore) Flush() error {
+	return filestore.Flush(m.FileStore)
+}
+
 func (m *metricsFileStore) Remove(id string) bool {
 	success := m.FileStore.Remove(id)
 
@@ -192,6 +236,11 @@ func (m *metricsFileStore) Remove(id string) bool {
 	if !ok {
 		return success
 	}
+	// file is still referenced, checked by the metadata so that the access
+	// time is not updated and nothing is downloaded
+	if _, ok := m.FileStore.Stat(id); ok {
+		return success
+	}
 	delete(m.fileSize, id)
 
 	sf := float64(s)
@@ -216,6 +265,31 @@ func (b *metricsEnvBuilder) Build() (pool.Environment, error) {
 	return e, nil
 }
 
+func (b *metricsEnvBuilder) NewNetwork() (pool.NetworkBuilder, error) {
+	nb, ok := b.EnvBuilder.(pool.NetworkEnvBuilder)
+	if !ok {
+		return nil, errors.New("shared network is not supported by the environment")
+	}
+	n, err := nb.NewNetwork()
+	if err != nil {
+		return nil, err
+	}
+	return &metricsNetworkBuilder{n}, nil
+}
+
+type metricsNetworkBuilder struct {
+	pool.NetworkBuilder
+}
+
+func (b *metricsNetworkBuilder) Build() (pool.Environment, error) {
+	e, err := b.NetworkBuilder.Build()
+	if err != nil {
+		return nil, err
+	}
+	envCreated.Inc()
+	return e, nil
+}
+
 var _ worker.EnvironmentPool = &metricsEnvPool{}
 
 type metricsEnvPool struct {
@@ -236,6 +310,18 @@ func (p *metricsEnvPool) Put(env envexec.Environment) {
 	envInUse.Dec()
 }
 
+func (p *metricsEnvPool) NewNetwork() (worker.EnvironmentPool, error) {
+	np, ok := p.EnvironmentPool.(worker.NetworkEnvironmentPool)
+	if !ok {
+		return nil, errors.New("shared network is not supported")
+	}
+	n, err := np.NewNetwork()
+	if err != nil {
+		return nil, err
+	}
+	return &metricsEnvPool{n}, nil
+}
+
 var _ worker.Worker = &metricsWorker{}
 var _ prometheus.Collector = &metricsWorker{}
 
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/criyle/go-judge/envexec"
+	"github.com/criyle/go-judge/filestore"
 	"github.com/criyle/go-judge/worker"
 )
 
@@ -29,6 +30,7 @@ type CmdFile struct {
 	StreamIn  bool    `json:"streamIn"`
 	StreamOut bool    `json:"streamOut"`
 	Pipe      bool    `json:"pipe"`
+	Extract   bool    `json:"extract,omitempty"`
 }
 
 // Cmd defines command and limits to start a program using in envexec
@@ -41,22 +43,54 @@ type Cmd struct {
 	RealCPULimit uint64 `json:"realCpuLimit"`
 	ClockLimit   uint64 `json:"clockLimit"`
 	MemoryLimit  uint64 `json:"memoryLimit"`
+	MemoryHigh   uint64 `json:"memoryHigh,omitempty"`
 	StackLimit   uint64 `json:"stackLimit"`
 	ProcLimit    uint64 `json:"procLimit"`
 	CPURateLimit uint64 `json:"cpuRateLimit"`
 	CPUSetLimit  string `json:"cpuSetLimit"`
 
+	DiskLimit      uint64 `json:"diskLimit,omitempty"`
+	FileCountLimit uint64 `json:"fileCountLimit,omitempty"`
+
+	IOReadBps   uint64 `json:"ioReadBps,omitempty"`
+	IOWriteBps  uint64 `json:"ioWriteBps,omitempty"`
+	IOReadIOPS  uint64 `json:"ioReadIops,omitempty"`
+	IOWriteIOPS uint64 `json:"ioWriteIops,omitempty"`
+
 	CopyIn map[string]CmdFile `json:"copyIn"`
 
 	CopyOut       []string `json:"copyOut"`
 	CopyOutCached []string `json:"copyOutCached"`
 	CopyOutMax    uint64   `json:"copyOutMax"`
 	CopyOutDir    string   `json:"copyOutDir"`
+	CopyOutBundle string   `json:"copyOutBundle,omitempty"`
 
 	TTY               bool `json:"tty,omitempty"`
 	StrictMemoryLimit bool `json:"strictMemoryLimit"`
 	DataSegmentLimit  bool `json:"dataSegmentLimit"`
 	AddressSpaceLimit bool `json:"addressSpaceLimit"`
+	SeccompAudit      bool `json:"seccompAudit,omitempty"`
+
+	SyscallTrace    string `json:"syscallTrace,omitempty"`
+	SyscallTraceMax uint64 `json:"syscallTraceMax,omitempty"`
+
+	CoreDump    bool   `json:"coreDump,omitempty"`
+	CoreDumpMax uint64 `json:"coreDumpMax,omitempty"`
+
+	TraceProcess bool `json:"traceProcess,omitempty"`
+	Perf         bool `json:"perf,omitempty"`
+
+	Repeat          int     `json:"repeat,omitempty"`
+	RepeatThreshold float64 `json:"repeatThreshold,omitempty"`
+
+	ScaleTime bool `json:"scaleTime,omitempty"`
+
+	Network string `json:"network,omitempty"`
+
+	Role         string `json:"role,omitempty"`
+	ReadyPort    int    `json:"readyPort,omitempty"`
+	ReadyFile    string `json:"readyFile,omitempty"`
+	ReadyTimeout uint64 `json:"readyTimeout,omitempty"`
 }
 
 // PipeIndex defines indexing for a pipe fd
@@ -111,16 +145,28 @@ func (s *Status) UnmarshalJSON(b []byte) error {
 
 // Result defines single command result
 type Result struct {
-	Status     Status            `json:"status"`
-	ExitStatus int               `json:"exitStatus"`
-	Error      string            `json:"error,omitempty"`
-	Time       uint64            `json:"time"`
-	Memory     uint64            `json:"memory"`
-	RunTime    uint64            `json:"runTime"`
-	ProcPeak   uint64            `json:"procPeak,omitempty"`
-	Files      map[string]string `json:"files,omitempty"`
-	FileIDs    map[string]string `json:"fileIds,omitempty"`
-	FileError  []FileError       `json:"fileError,omitempty"`
+	Status           Status            `json:"status"`
+	ExitStatus       int               `json:"exitStatus"`
+	Error            string            `json:"error,omitempty"`
+	Time             uint64            `json:"time"`
+	Memory           uint64            `json:"memory"`
+	RunTime          uint64            `json:"runTime"`
+	ProcPeak         uint64            `json:"procPeak,omitempty"`
+	DiskUsage        uint64            `json:"diskUsage,omitempty"`
+	IORead           uint64            `json:"ioRead,omitempty"`
+	IOWrite          uint64            `json:"ioWrite,omitempty"`
+	MemoryHighEvents uint64            `json:"memoryHighEvents,omitempty"`
+	Files            map[string]string `json:"files,omitempty"`
+	FileIDs          map[string]string `json:"fileIds,omitempty"`
+	FileError        []FileError       `json:"fileError,omitempty"`
+	Syscalls         []string          `json:"syscalls,omitempty"`
+	Crash            *Crash            `json:"crash,omitempty"`
+	Processes        []ProcessInfo     `json:"processes,omitempty"`
+	Perf             *PerfStat         `json:"perf,omitempty"`
+	Attempts         []Attempt         `json:"attempts,omitempty"`
+	MinTime          uint64            `json:"minTime,omitempty"`
+	MedianTime       uint64            `json:"medianTime,omitempty"`
+	Limits           *Limits           `json:"limits,omitempty"`
 
 	files []string
 	Buffs map[string][]byte `json:"-"`
@@ -128,28 +174,52 @@ type Result struct {
 
 func (r Result) String() string {
 	type Result struct {
-		Status     Status
-		ExitStatus int
-		Error      string
-		Time       time.Duration
-		RunTime    time.Duration
-		ProcPeak   uint64
-		Memory     envexec.Size
-		Files      map[string]string
-		FileIDs    map[string]string
-		FileError  []FileError
+		Status           Status
+		ExitStatus       int
+		Error            string
+		Time             time.Duration
+		RunTime          time.Duration
+		ProcPeak         uint64
+		DiskUsage        envexec.Size
+		IORead           envexec.Size
+		IOWrite          envexec.Size
+		MemoryHighEvents uint64
+		Memory           envexec.Size
+		Files            map[string]string
+		FileIDs          map[string]string
+		FileError        []FileError
+		Syscalls         []string
+		Crash            *Crash
+		Processes        []ProcessInfo
+		Perf             *PerfStat
+		Attempts         []Attempt
+		MinTime          time.Duration
+		MedianTime       time.Duration
+		Limits           *Limits
 	}
 	d := Result{
-		Status:     r.Status,
-		ExitStatus: r.ExitStatus,
-		Error:      r.Error,
-		Time:       time.Duration(r.Time),
-		RunTime:    time.Duration(r.RunTime),
-		Memory:     envexec.Size(r.Memory),
-		ProcPeak:   r.ProcPeak,
-		Files:      make(map[string]string),
-		FileIDs:    r.FileIDs,
-		FileError:  r.FileError,
+		Status:           r.Status,
+		ExitStatus:       r.ExitStatus,
+		Error:            r.Error,
+		Time:             time.Duration(r.Time),
+		RunTime:          time.Duration(r.RunTime),
+		Memory:           envexec.Size(r.Memory),
+		ProcPeak:         r.ProcPeak,
+		DiskUsage:        envexec.Size(r.DiskUsage),
+		IORead:           envexec.Size(r.IORead),
+		IOWrite:          envexec.Size(r.IOWrite),
+		MemoryHighEvents: r.MemoryHighEvents,
+		Files:            make(map[string]string),
+		FileIDs:          r.FileIDs,
+		FileError:        r.FileError,
+		Syscalls:         r.Syscalls,
+		Crash:            r.Crash,
+		Processes:        r.Processes,
+		Perf:             r.Perf,
+		Attempts:         r.Attempts,
+		MinTime:          time.Duration(r.MinTime),
+		MedianTime:       time.Duration(r.MedianTime),
+		Limits:           r.Limits,
 	}
 	for k, v := range r.Files {
 		d.Files[k] = "len:" + strconv.Itoa(len(v))
@@ -157,6 +227,73 @@ func (r Result) String() string {
 	return fmt.Sprintf("%+v", d)
 }
 
+// Crash defines the crash details of a signalled command
+type Crash struct {
+	Signal     string   `json:"signal"`
+	Addr       uint64   `json:"addr,omitempty"`
+	Stack      []string `json:"stack,omitempty"`
+	CoreFileID string   `json:"coreFileId,omitempty"`
+}
+
+// ProcessInfo defines a process created during the run, time in ns
+type ProcessInfo struct {
+	Pid        int      `json:"pid"`
+	PPid       int      `json:"ppid"`
+	Args       []string `json:"args"`
+	Start      uint64   `json:"start"`
+	End        uint64   `json:"end"`
+	ExitStatus int      `json:"exitStatus"`
+	Signal     string   `json:"signal,omitempty"`
+}
+
+// PerfStat defines the perf counters of a command, task clock in ns
+type PerfStat struct {
+	TaskClock    uint64   `json:"taskClock"`
+	Instructions uint64   `json:"instructions"`
+	Cycles       uint64   `json:"cycles"`
+	Unsupported  []string `json:"unsupported,omitempty"`
+}
+
+// Attempt defines a single run of a repeated command
+type Attempt struct {
+	Status     Status `json:"status"`
+	ExitStatus int    `json:"exitStatus"`
+	Time       uint64 `json:"time"`
+	RunTime    uint64 `json:"runTime"`
+	Memory     uint64 `json:"memory"`
+}
+
+// Limits defines the effective limits of a command, time in ns
+type Limits struct {
+	CPULimit       uint64 `json:"cpuLimit,omitempty"`
+	ClockLimit     uint64 `json:"clockLimit,omitempty"`
+	MemoryLimit    uint64 `json:"memoryLimit,omitempty"`
+	StackLimit     uint64 `json:"stackLimit,omitempty"`
+	OutputLimit    uint64 `json:"outputLimit,omitempty"`
+	ProcLimit      uint64 `json:"procLimit,omitempty"`
+	OpenFileLimit  uint64 `json:"openFileLimit,omitempty"`
+	CPURateLimit   uint64 `json:"cpuRateLimit,omitempty"`
+	DiskLimit      uint64 `json:"diskLimit,omitempty"`
+	FileCountLimit uint64 `json:"fileCountLimit,omitempty"`
+	CopyOutMax     uint64 `json:"copyOutMax,omitempty"`
+	IOReadBps      uint64 `json:"ioReadBps,omitempty"`
+	IOWriteBps     uint64 `json:"ioWriteBps,omitempty"`
+	IOReadIOPS     uint64 `json:"ioReadIops,omitempty"`
+	IOWriteIOPS    uint64 `json:"ioWriteIops,omitempty"`
+}
+
+// FileMeta defines the metadata of a file in the file store
+type FileMeta struct {
+	Name     string            `json:"name"`
+	Size     int64             `json:"size"`
+	SHA256   string            `json:"sha256"`
+	Created  time.Time         `json:"created"`
+	Accessed time.Time         `json:"accessed"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Leases   int               `json:"leases,omitempty"`
+	Bundle   bool              `json:"bundle,omitempty"`
+}
+
 // Response defines worker response for single request
 type Response struct {
 	RequestID string   `json:"requestId"`
@@ -251,15 +388,68 @@ func ConvertRequest(r *Request, srcPrefix []string) (*worker.Request, error) {
 
 func convertResult(r worker.Result, mmap bool) (Result, error) {
 	res := Result{
-		Status:     Status(r.Status),
-		ExitStatus: r.ExitStatus,
-		Error:      r.Error,
-		Time:       uint64(r.Time),
-		RunTime:    uint64(r.RunTime),
-		Memory:     uint64(r.Memory),
-		ProcPeak:   r.ProcPeak,
-		FileIDs:    r.FileIDs,
-		FileError:  r.FileError,
+		Status:           Status(r.Status),
+		ExitStatus:       r.ExitStatus,
+		Error:            r.Error,
+		Time:             uint64(r.Time),
+		RunTime:          uint64(r.RunTime),
+		Memory:           uint64(r.Memory),
+		ProcPeak:         r.ProcPeak,
+		DiskUsage:        uint64(r.DiskUsage),
+		IORead:           uint64(r.IORead),
+		IOWrite:          uint64(r.IOWrite),
+		MemoryHighEvents: r.MemoryHighEvents,
+		FileIDs:          r.FileIDs,
+		FileError:        r.FileError,
+		Syscalls:         r.Syscalls,
+	}
+	if r.Crash != nil {
+		res.Crash = &Crash{
+			Signal:     r.Crash.Signal,
+			Addr:       r.Crash.Addr,
+			Stack:      r.Crash.Stack,
+			CoreFileID: r.Crash.CoreFileID,
+		}
+	}
+	if r.Processes != nil {
+		res.Processes = make([]ProcessInfo, 0, len(r.Processes))
+		for _, p := range r.Processes {
+			res.Processes = append(res.Processes, ProcessInfo{
+				Pid:        p.Pid,
+				PPid:       p.PPid,
+				Args:       p.Args,
+				Start:      uint64(p.Start),
+				End:        uint64(p.End),
+				ExitStatus: p.ExitStatus,
+				Signal:     p.Signal,
+			})
+		}
+	}
+	if r.Perf != nil {
+		res.Perf = &PerfStat{
+			TaskClock:    uint64(r.Perf.TaskClock),
+			Instructions: r.Perf.Instructions,
+			Cycles:       r.Perf.Cycles,
+			Unsupported:  r.Perf.Unsupported,
+		}
+	}
+	if r.Limits != nil {
+		l := convertLimits(*r.Limits)
+		res.Limits = &l
+	}
+	if r.Attempts != nil {
+		res.Attempts = make([]Attempt, 0, len(r.Attempts))
+		for _, a := range r.Attempts {
+			res.Attempts = append(res.Attempts, Attempt{
+				Status:     Status(a.Status),
+				ExitStatus: a.ExitStatus,
+				Time:       uint64(a.Time),
+				RunTime:    uint64(a.RunTime),
+				Memory:     uint64(a.Memory),
+			})
+		}
+		res.MinTime = uint64(r.MinTime)
+		res.MedianTime = uint64(r.MedianTime)
 	}
 	if r.Files != nil {
 		res.Files = make(map[string]string)
@@ -307,16 +497,34 @@ func convertCmd(c Cmd, srcPrefix []string) (worker.Cmd, error) {
 		CPULimit:          time.Duration(c.CPULimit),
 		ClockLimit:        time.Duration(clockLimit),
 		MemoryLimit:       envexec.Size(c.MemoryLimit),
+		MemoryHigh:        envexec.Size(c.MemoryHigh),
 		StackLimit:        envexec.Size(c.StackLimit),
 		ProcLimit:         c.ProcLimit,
 		CPURateLimit:      c.CPURateLimit,
 		CPUSetLimit:       c.CPUSetLimit,
+		DiskLimit:         envexec.Size(c.DiskLimit),
+		FileCountLimit:    c.FileCountLimit,
+		IOReadBps:         envexec.Size(c.IOReadBps),
+		IOWriteBps:        envexec.Size(c.IOWriteBps),
+		IOReadIOPS:        c.IOReadIOPS,
+		IOWriteIOPS:       c.IOWriteIOPS,
 		DataSegmentLimit:  c.DataSegmentLimit || c.StrictMemoryLimit,
 		AddressSpaceLimit: c.AddressSpaceLimit,
+		SeccompAudit:      c.SeccompAudit,
+		SyscallTrace:      c.SyscallTrace,
+		SyscallTraceMax:   c.SyscallTraceMax,
+		CoreDump:          c.CoreDump,
+		CoreDumpMax:       c.CoreDumpMax,
+		TraceProcess:      c.TraceProcess,
+		Perf:              c.Perf,
+		Repeat:            c.Repeat,
+		RepeatThreshold:   c.RepeatThreshold,
+		ScaleTime:         c.ScaleTime,
 		CopyOut:           convertCopyOut(c.CopyOut),
 		CopyOutCached:     convertCopyOut(c.CopyOutCached),
 		CopyOutMax:        c.CopyOutMax,
 		CopyOutDir:        c.CopyOutDir,
+		CopyOutBundle:     c.CopyOutBundle,
 	}
 	for _, f := range c.Files {
 		cf, err := convertCmdFile(f, srcPrefix)
@@ -325,6 +533,21 @@ func convertCmd(c Cmd, srcPrefix []string) (worker.Cmd, error) {
 		}
 		w.Files = append(w.Files, cf)
 	}
+	network, err := envexec.StringToNetworkMode(c.Network)
+	if err != nil {
+		return w, err
+	}
+	w.Network = network
+	switch c.Role {
+	case "":
+	case "service":
+		w.Role = worker.RoleService
+		w.ReadyPort = c.ReadyPort
+		w.ReadyFile = c.ReadyFile
+		w.ReadyTimeout = time.Duration(c.ReadyTimeout)
+	default:
+		return w, fmt.Errorf("invalid role: %s", c.Role)
+	}
 	if c.CopyIn != nil {
 		w.CopyIn = make(map[string]worker.CmdFile)
 		w.Symlinks = make(map[string]string)
@@ -337,6 +560,9 @@ func convertCmd(c Cmd, srcPrefix []string) (worker.Cmd, error) {
 			if err != nil {
 				return w, err
 			}
+			if f.Extract {
+				cf = &worker.ArchiveFile{File: cf}
+			}
 			w.CopyIn[k] = cf
 		}
 	}
@@ -394,21 +620,79 @@ func checkPathPrefix(path, prefix string) (bool, error) {
 	return strings.HasPrefix(filepath.Join(wd, path), prefix), nil
 }
 
-const optionalSuffix = "?"
+const (
+	optionalSuffix = "?"
+	archiveSuffix  = "/" // copy out the directory as an archive
+)
 
 func convertCopyOut(copyOut []string) []worker.CmdCopyOutFile {
 	rt := make([]worker.CmdCopyOutFile, 0, len(copyOut))
 	for _, n := range copyOut {
-		if strings.HasSuffix(n, optionalSuffix) {
-			rt = append(rt, worker.CmdCopyOutFile{
-				Name:     strings.TrimSuffix(n, optionalSuffix),
-				Optional: true,
-			})
-			continue
+		f := worker.CmdCopyOutFile{Name: n}
+		if strings.HasSuffix(f.Name, optionalSuffix) {
+			f.Name = strings.TrimSuffix(f.Name, optionalSuffix)
+			f.Optional = true
+		}
+		if strings.HasSuffix(f.Name, archiveSuffix) {
+			f.Name = strings.TrimSuffix(f.Name, archiveSuffix)
+			f.Archive = true
 		}
-		rt = append(rt, worker.CmdCopyOutFile{
-			Name: n,
-		})
+		rt = append(rt, f)
 	}
 	return rt
 }
+
+func convertLimits(l worker.Limits) Limits {
+	return Limits{
+		CPULimit:       uint64(l.CPU),
+		ClockLimit:     uint64(l.Clock),
+		MemoryLimit:    uint64(l.Memory),
+		StackLimit:     uint64(l.Stack),
+		OutputLimit:    uint64(l.Output),
+		ProcLimit:      l.Proc,
+		OpenFileLimit:  l.OpenFile,
+		CPURateLimit:   l.CPURate,
+		DiskLimit:      uint64(l.Disk),
+		FileCountLimit: l.FileCount,
+		CopyOutMax:     uint64(l.CopyOut),
+		IOReadBps:      uint64(l.IOReadBps),
+		IOWriteBps:     uint64(l.IOWriteBps),
+		IOReadIOPS:     l.IOReadIOPS,
+		IOWriteIOPS:    l.IOWriteIOPS,
+	}
+}
+
+// ConvertLimits converts json limits into worker limits
+func ConvertLimits(l Limits) worker.Limits {
+	return worker.Limits{
+		CPU:         time.Duration(l.CPULimit),
+		Clock:       time.Duration(l.ClockLimit),
+		Memory:      envexec.Size(l.MemoryLimit),
+		Stack:       envexec.Size(l.StackLimit),
+		Output:      envexec.Size(l.OutputLimit),
+		Proc:        l.ProcLimit,
+		OpenFile:    l.OpenFileLimit,
+		CPURate:     l.CPURateLimit,
+		Disk:        envexec.Size(l.DiskLimit),
+		FileCount:   l.FileCountLimit,
+		CopyOut:     envexec.Size(l.CopyOutMax),
+		IOReadBps:   envexec.Size(l.IOReadBps),
+		IOWriteBps:  envexec.Size(l.IOWriteBps),
+		IOReadIOPS:  l.IOReadIOPS,
+		IOWriteIOPS: l.IOWriteIOPS,
+	}
+}
+
+// ConvertFileMeta converts file store metadata into json metadata
+func ConvertFileMeta(m filestore.FileMeta) FileMeta {
+	return FileMeta{
+		Name:     m.Name,
+		Size:     m.Size,
+		SHA256:   m.SHA256,
+		Created:  m.Created,
+		Accessed: m.Accessed,
+		Labels:   m.Labels,
+		Leases:   m.Leases,
+		Bundle:   m.Bundle,
+	}
+}
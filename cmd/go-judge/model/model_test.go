@@ -37,10 +37,10 @@ func TestStatus_UnmarshalJSON_Invalid(t *testing.T) {
 }
 
 func TestConvertCopyOut(t *testing.T) {
-	in := []string{"foo.txt", "bar.txt?"}
+	in := []string{"foo.txt", "bar.txt?", "out/", "log/?"}
 	out := convertCopyOut(in)
-	if len(out) != 2 {
-		t.Fatalf("expected 2, got %d", len(out))
+	if len(out) != 4 {
+		t.Fatalf("expected 4, got %d", len(out))
 	}
 	if out[0].Name != "foo.txt" || out[0].Optional {
 		t.Errorf("unexpected: %+v", out[0])
@@ -48,6 +48,12 @@ func TestConvertCopyOut(t *testing.T) {
 	if out[1].Name != "bar.txt" || !out[1].Optional {
 		t.Errorf("unexpected: %+v", out[1])
 	}
+	if out[2].Name != "out" || out[2].Optional || !out[2].Archive {
+		t.Errorf("unexpected: %+v", out[2])
+	}
+	if out[3].Name != "log" || !out[3].Optional || !out[3].Archive {
+		t.Errorf("unexpected: %+v", out[3])
+	}
 }
 
 func TestCheckPathPrefixes(t *testing.T) {
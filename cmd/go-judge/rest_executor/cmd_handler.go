@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/criyle/go-judge/cmd/go-judge/auth"
 	"github.com/criyle/go-judge/cmd/go-judge/model"
 	"github.com/criyle/go-judge/worker"
 	"github.com/gin-gonic/gin"
@@ -43,7 +44,7 @@ func (c *cmdHandle) handleRun(ctx *gin.Context) {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, "no cmd provided")
 		return
 	}
-	r, err := model.ConvertRequest(&req, c.srcPrefix)
+	r, err := model.ConvertRequest(&req, auth.SrcPrefix(ctx.Request.Context(), c.srcPrefix))
 	if err != nil {
 		ctx.Error(err)
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, err.Error())
@@ -0,0 +1,69 @@
+package restexecutor
+
+import (
+	"compress/gzip"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// acceptEncoding returns the preferred supported content encoding by the
+// Accept-Encoding header, zstd is preferred over gzip with equal quality.
+// Empty is returned for identity.
+func acceptEncoding(header string) string {
+	var (
+		best  string
+		bestQ float64
+	)
+	for part := range strings.SplitSeq(header, ",") {
+		enc, params, _ := strings.Cut(part, ";")
+		enc = strings.ToLower(strings.TrimSpace(enc))
+		if enc != "zstd" && enc != "gzip" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			var err error
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		if q > bestQ || (q == bestQ && enc == "zstd") {
+			best, bestQ = enc, q
+		}
+	}
+	return best
+}
+
+// matchETag returns whether the If-None-Match header matches the etag
+func matchETag(header, etag string) bool {
+	for part := range strings.SplitSeq(header, ",") {
+		part = strings.TrimPrefix(strings.TrimSpace(part), "W/")
+		if part == "*" || part == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// writeEncoded writes the content from r to w compressed by the encoding
+func writeEncoded(w io.Writer, r io.Reader, enc string) error {
+	var ew io.WriteCloser
+	switch enc {
+	case "zstd":
+		zw, err := zstd.NewWriter(w)
+		if err != nil {
+			return err
+		}
+		ew = zw
+	default:
+		ew = gzip.NewWriter(w)
+	}
+	if _, err := io.Copy(ew, r); err != nil {
+		ew.Close()
+		return err
+	}
+	return ew.Close()
+}
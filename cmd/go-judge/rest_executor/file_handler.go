@@ -1,25 +1,35 @@
 package restexecutor
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"mime"
 	"net/http"
+	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/criyle/go-judge/cmd/go-judge/model"
 	"github.com/criyle/go-judge/envexec"
 	"github.com/criyle/go-judge/filestore"
 	"github.com/gin-gonic/gin"
 )
 
 type fileHandle struct {
-	fs filestore.FileStore
+	fs          filestore.FileStore
+	bundleLimit filestore.BundleLimit
 }
 
-// NewFileHandle creates a new file handle
-func NewFileHandle(fs filestore.FileStore) Register {
+// NewFileHandle creates a new file handle, uploaded bundle archives are
+// extracted within the bundle limit
+func NewFileHandle(fs filestore.FileStore, bundleLimit filestore.BundleLimit) Register {
 	return &fileHandle{
-		fs: fs,
+		fs:          fs,
+		bundleLimit: bundleLimit,
 	}
 }
 
@@ -28,15 +38,99 @@ func (f *fileHandle) Register(r *gin.Engine) {
 	r.GET("/file", f.fileGet)
 	r.POST("/file", f.filePost)
 	r.GET("/file/:fid", f.fileIDGet)
+	r.HEAD("/file/:fid", f.fileIDHead)
+	r.GET("/file/:fid/meta", f.fileIDMeta)
 	r.DELETE("/file/:fid", f.fileIDDelete)
 }
 
 func (f *fileHandle) fileGet(c *gin.Context) {
-	ids := f.fs.List()
+	var query struct {
+		Meta      bool          `form:"meta"`
+		Label     []string      `form:"label"`
+		OlderThan time.Duration `form:"olderThan"`
+		NewerThan time.Duration `form:"newerThan"`
+	}
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+	labels, err := parseLabels(query.Label)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+	if !query.Meta && len(labels) == 0 && query.OlderThan == 0 && query.NewerThan == 0 {
+		c.JSON(http.StatusOK, f.fs.List())
+		return
+	}
+
+	files := filestore.ListMeta(f.fs, &filestore.Filter{
+		Labels:    labels,
+		OlderThan: query.OlderThan,
+		NewerThan: query.NewerThan,
+	})
+	if query.Meta {
+		ret := make(map[string]model.FileMeta, len(files))
+		for id, m := range files {
+			ret[id] = model.ConvertFileMeta(m)
+		}
+		c.JSON(http.StatusOK, ret)
+		return
+	}
+	ids := make(map[string]string, len(files))
+	for id, m := range files {
+		ids[id] = m.Name
+	}
 	c.JSON(http.StatusOK, ids)
 }
 
+// parseLabels parses labels in the format of key=value
+func parseLabels(s []string) (map[string]string, error) {
+	if len(s) == 0 {
+		return nil, nil
+	}
+	labels := make(map[string]string, len(s))
+	for _, l := range s {
+		k, v, ok := strings.Cut(l, "=")
+		if !ok || k == "" {
+			return nil, fmt.Errorf("invalid label %q, expected key=value", l)
+		}
+		labels[k] = v
+	}
+	return labels, nil
+}
+
 func (f *fileHandle) filePost(c *gin.Context) {
+	labels, err := parseLabels(c.PostFormArray("label"))
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+	pin := c.PostForm("pin") == "true"
+
+	// skip upload if the file with the digest exists in content-addressed store
+	if digest := c.Query("sha256"); digest != "" {
+		ok, err := filestore.AddRef(f.fs, digest)
+		if errors.Is(err, filestore.ErrNotContentAddressed) {
+			c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+		if !ok {
+			c.AbortWithStatus(http.StatusNotFound)
+			return
+		}
+		f.fs.SetLabels(digest, labels)
+		if pin {
+			filestore.Pin(f.fs, digest)
+		}
+		c.JSON(http.StatusOK, digest)
+		return
+	}
+
 	fh, err := c.FormFile("file")
 	if err != nil {
 		c.AbortWithError(http.StatusBadRequest, err)
@@ -48,9 +142,25 @@ func (f *fileHandle) filePost(c *gin.Context) {
 		c.AbortWithError(http.StatusInternalServerError, err)
 		return
 	}
+	defer fi.Close()
+
+	if c.PostForm("bundle") == "true" {
+		id, err := filestore.AddBundle(f.fs, fh.Filename, fi, fh.Size, f.bundleLimit)
+		if err != nil {
+			c.AbortWithError(bundleStatus(err), err)
+			return
+		}
+		f.fs.SetLabels(id, labels)
+		if pin {
+			filestore.Pin(f.fs, id)
+		}
+		c.JSON(http.StatusOK, id)
+		return
+	}
+
 	sf, err := f.fs.New()
 	if err != nil {
-		c.AbortWithError(http.StatusInternalServerError, err)
+		c.AbortWithError(fileStoreStatus(err), err)
 		return
 	}
 	defer sf.Close()
@@ -61,12 +171,30 @@ func (f *fileHandle) filePost(c *gin.Context) {
 	}
 	id, err := f.fs.Add(fh.Filename, sf.Name())
 	if err != nil {
-		c.AbortWithError(http.StatusInternalServerError, err)
+		c.AbortWithError(fileStoreStatus(err), err)
 		return
 	}
+	f.fs.SetLabels(id, labels)
+	if pin {
+		filestore.Pin(f.fs, id)
+	}
 	c.JSON(http.StatusOK, id)
 }
 
+func fileStoreStatus(err error) int {
+	if errors.Is(err, filestore.ErrStoreFull) {
+		return http.StatusInsufficientStorage
+	}
+	return http.StatusInternalServerError
+}
+
+func bundleStatus(err error) int {
+	if errors.Is(err, filestore.ErrInvalidBundle) {
+		return http.StatusBadRequest
+	}
+	return fileStoreStatus(err)
+}
+
 func (f *fileHandle) fileIDGet(c *gin.Context) {
 	type fileURI struct {
 		FileID string `uri:"fid"`
@@ -82,29 +210,150 @@ func (f *fileHandle) fileIDGet(c *gin.Context) {
 		c.AbortWithStatus(http.StatusNotFound)
 		return
 	}
-	typ := mime.TypeByExtension(filepath.Ext(name))
-	c.Header("Content-Type", typ)
 
-	fi, ok := file.(*envexec.FileInput) // fast path
-	if ok {
-		c.FileAttachment(fi.Path, name)
+	// bundle is downloaded as tar archive of the directory
+	m, hasMeta := f.fs.Stat(uri.FileID)
+	if fi, isInput := file.(*envexec.FileInput); hasMeta && m.Bundle && isInput {
+		bundleHeaders(c, name)
+		c.Status(http.StatusOK)
+		if err := filestore.WriteBundle(c.Writer, fi.Path); err != nil {
+			c.Error(err)
+		}
 		return
 	}
 
-	r, err := envexec.FileToReader(file)
+	var r io.ReadCloser
+	var err error
+	if fi, ok := file.(*envexec.FileInput); ok { // fast path
+		r, err = os.Open(fi.Path)
+	} else {
+		r, err = envexec.FileToReader(file)
+	}
 	if err != nil {
 		c.AbortWithError(http.StatusInternalServerError, err)
 		return
 	}
 	defer r.Close()
 
-	content, err := io.ReadAll(r)
-	if err != nil {
-		c.AbortWithError(http.StatusInternalServerError, err)
+	rs, ok := r.(io.ReadSeeker)
+	if !ok {
+		content, err := io.ReadAll(r)
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+		rs = bytes.NewReader(content)
+	}
+
+	downloadHeaders(c, name)
+
+	var modTime time.Time
+	if hasMeta {
+		modTime = m.Created
+		c.Header("ETag", `"`+m.SHA256+`"`)
+	}
+
+	// ranges are served on the identity encoding
+	if enc := acceptEncoding(c.GetHeader("Accept-Encoding")); enc != "" && c.GetHeader("Range") == "" {
+		if hasMeta {
+			etag := `"` + m.SHA256 + "-" + enc + `"`
+			c.Header("ETag", etag)
+			if matchETag(c.GetHeader("If-None-Match"), etag) {
+				c.Status(http.StatusNotModified)
+				return
+			}
+		}
+		c.Header("Content-Encoding", enc)
+		c.Status(http.StatusOK)
+		if err := writeEncoded(c.Writer, rs, enc); err != nil {
+			c.Error(err)
+		}
+		return
+	}
+	http.ServeContent(c.Writer, c.Request, name, modTime, rs)
+}
+
+// fileIDHead sends the headers of fileIDGet from the metadata without reading
+// the file, so that the file is not downloaded from the remote file store nor
+// marked as accessed
+func (f *fileHandle) fileIDHead(c *gin.Context) {
+	type fileURI struct {
+		FileID string `uri:"fid"`
+	}
+	var uri fileURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	m, ok := f.fs.Stat(uri.FileID)
+	if !ok {
+		c.AbortWithStatus(http.StatusNotFound)
+		return
+	}
+	if m.Bundle {
+		bundleHeaders(c, m.Name)
+		c.Status(http.StatusOK)
+		return
+	}
+	downloadHeaders(c, m.Name)
+
+	etag := `"` + m.SHA256 + `"`
+	enc := acceptEncoding(c.GetHeader("Accept-Encoding"))
+	if enc != "" && c.GetHeader("Range") == "" {
+		etag = `"` + m.SHA256 + "-" + enc + `"`
+	}
+	c.Header("ETag", etag)
+	if matchETag(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	if enc != "" && c.GetHeader("Range") == "" {
+		c.Header("Content-Encoding", enc)
+		c.Status(http.StatusOK)
+		return
+	}
+	if !m.Created.IsZero() {
+		c.Header("Last-Modified", m.Created.UTC().Format(http.TimeFormat))
+	}
+	c.Header("Accept-Ranges", "bytes")
+	c.Header("Content-Length", strconv.FormatInt(m.Size, 10))
+	c.Status(http.StatusOK)
+}
+
+// bundleHeaders sets the headers to download the bundle as tar archive
+func bundleHeaders(c *gin.Context, name string) {
+	c.Header("Content-Type", "application/x-tar")
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": name + ".tar"}))
+}
+
+// downloadHeaders sets the headers to download the file by its name
+func downloadHeaders(c *gin.Context, name string) {
+	typ := mime.TypeByExtension(filepath.Ext(name))
+	if typ == "" {
+		typ = "application/octet-stream"
+	}
+	c.Header("Content-Type", typ)
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": name}))
+	c.Header("Vary", "Accept-Encoding")
+}
+
+func (f *fileHandle) fileIDMeta(c *gin.Context) {
+	type fileURI struct {
+		FileID string `uri:"fid"`
+	}
+	var uri fileURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	m, ok := f.fs.Stat(uri.FileID)
+	if !ok {
+		c.AbortWithStatus(http.StatusNotFound)
 		return
 	}
-	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", name))
-	c.Data(http.StatusOK, typ, content)
+	c.JSON(http.StatusOK, model.ConvertFileMeta(m))
 }
 
 func (f *fileHandle) fileIDDelete(c *gin.Context) {
@@ -1,7 +1,12 @@
 package restexecutor
 
 import (
+	"archive/tar"
 	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
@@ -10,6 +15,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/criyle/go-judge/envexec"
 	"github.com/criyle/go-judge/filestore"
 	"github.com/gin-gonic/gin"
 )
@@ -195,6 +201,179 @@ func TestFileIDGet(t *testing.T) {
 	}
 }
 
+// TestFileIDGetRange tests range, etag and compression of file download
+func TestFileIDGetRange(t *testing.T) {
+	// Create a temporary directory for the file store
+	tempDir := t.TempDir()
+
+	// Initialize the file store
+	router := gin.Default()
+	f := &fileHandle{fs: filestore.NewFileLocalStore(tempDir)}
+	router.GET("/file/:fid", f.fileIDGet)
+
+	content := "print(58 - 7 * 3)"
+	testFilePath := filepath.Join(tempDir, "test.py")
+	if err := CreateFileWithContent(testFilePath, content); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	fileID, err := f.fs.Add("test.py", testFilePath)
+	if err != nil {
+		t.Fatalf("Failed to add file to storage: %v", err)
+	}
+
+	// Range request returns the partial content
+	req := httptest.NewRequest("GET", "/file/"+fileID, nil)
+	req.Header.Set("Range", "bytes=0-4")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusPartialContent {
+		t.Fatalf("Expected status %d, got %d", http.StatusPartialContent, w.Code)
+	}
+	if w.Body.String() != content[:5] {
+		t.Fatalf("Expected response body %s, got %s", content[:5], w.Body.String())
+	}
+
+	// ETag is the SHA-256 digest of the content
+	sum := sha256.Sum256([]byte(content))
+	etag := `"` + hex.EncodeToString(sum[:]) + `"`
+	if got := w.Header().Get("ETag"); got != etag {
+		t.Fatalf("Expected etag %s, got %s", etag, got)
+	}
+	req = httptest.NewRequest("GET", "/file/"+fileID, nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotModified {
+		t.Fatalf("Expected status %d, got %d", http.StatusNotModified, w.Code)
+	}
+
+	// gzip encoding is negotiated by Accept-Encoding
+	req = httptest.NewRequest("GET", "/file/"+fileID, nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("Expected gzip encoding, got %q", w.Header().Get("Content-Encoding"))
+	}
+	gr, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatalf("Failed to read gzip body: %v", err)
+	}
+	body, err := io.ReadAll(gr)
+	if err != nil {
+		t.Fatalf("Failed to read gzip body: %v", err)
+	}
+	if string(body) != content {
+		t.Fatalf("Expected response body %s, got %s", content, body)
+	}
+}
+
+// noMetaStore is a file store without metadata
+type noMetaStore struct {
+	filestore.FileStore
+}
+
+func (noMetaStore) Stat(string) (filestore.FileMeta, bool) {
+	return filestore.FileMeta{}, false
+}
+
+// TestFileIDGetNoMeta tests no ETag is sent when the metadata is unavailable
+func TestFileIDGetNoMeta(t *testing.T) {
+	fs := noMetaStore{filestore.NewFileLocalStore(t.TempDir())}
+	router := gin.Default()
+	f := &fileHandle{fs: fs}
+	router.GET("/file/:fid", f.fileIDGet)
+
+	sf, err := fs.New()
+	if err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	sf.WriteString("content")
+	sf.Close()
+	fileID, err := fs.Add("test.txt", sf.Name())
+	if err != nil {
+		t.Fatalf("Failed to add file to storage: %v", err)
+	}
+
+	for _, enc := range []string{"", "gzip"} {
+		req := httptest.NewRequest("GET", "/file/"+fileID, nil)
+		req.Header.Set("Accept-Encoding", enc)
+		req.Header.Set("If-None-Match", `"-gzip"`)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%q: expected status %d, got %d", enc, http.StatusOK, w.Code)
+		}
+		if got := w.Header().Get("ETag"); got != "" {
+			t.Fatalf("%q: expected no etag, got %s", enc, got)
+		}
+	}
+}
+
+// noGetStore is a file store that fails the test on Get
+type noGetStore struct {
+	filestore.FileStore
+	t *testing.T
+}
+
+func (s noGetStore) Get(id string) (string, envexec.File) {
+	s.t.Fatalf("Get %s: expected metadata only", id)
+	return "", nil
+}
+
+// TestFileIDHead tests HEAD sends the headers of GET without reading the file
+func TestFileIDHead(t *testing.T) {
+	fs := filestore.NewFileLocalStore(t.TempDir())
+	router := gin.Default()
+	get := &fileHandle{fs: fs}
+	head := &fileHandle{fs: noGetStore{fs, t}}
+	router.GET("/file/:fid", get.fileIDGet)
+	router.HEAD("/file/:fid", head.fileIDHead)
+
+	sf, err := fs.New()
+	if err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	sf.WriteString("print(58 - 7 * 3)")
+	sf.Close()
+	fileID, err := fs.Add("test.py", sf.Name())
+	if err != nil {
+		t.Fatalf("Failed to add file to storage: %v", err)
+	}
+
+	headers := []string{"Content-Type", "Content-Disposition", "Content-Length", "Content-Encoding", "ETag", "Last-Modified", "Accept-Ranges", "Vary"}
+	for _, enc := range []string{"", "gzip"} {
+		resp := make(map[string]*httptest.ResponseRecorder)
+		for _, method := range []string{"GET", "HEAD"} {
+			req := httptest.NewRequest(method, "/file/"+fileID, nil)
+			req.Header.Set("Accept-Encoding", enc)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if w.Code != http.StatusOK {
+				t.Fatalf("%s %q: expected status %d, got %d", method, enc, http.StatusOK, w.Code)
+			}
+			resp[method] = w
+		}
+		for _, h := range headers {
+			if want, got := resp["GET"].Header().Get(h), resp["HEAD"].Header().Get(h); want != got {
+				t.Fatalf("%q: expected HEAD %s %q, got %q", enc, h, want, got)
+			}
+		}
+		if resp["HEAD"].Body.Len() != 0 {
+			t.Fatalf("%q: expected no body for HEAD", enc)
+		}
+
+		req := httptest.NewRequest("HEAD", "/file/"+fileID, nil)
+		req.Header.Set("Accept-Encoding", enc)
+		req.Header.Set("If-None-Match", resp["GET"].Header().Get("ETag"))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusNotModified {
+			t.Fatalf("%q: expected status %d, got %d", enc, http.StatusNotModified, w.Code)
+		}
+	}
+}
+
 // TestFileIDDelete tests the file deletion functionality
 func TestFileIDDelete(t *testing.T) {
 	// Create a temporary directory for the file store
@@ -236,3 +415,153 @@ func TestFileIDDelete(t *testing.T) {
 		t.Fatalf("Expected file to be deleted, but it still exists")
 	}
 }
+
+// TestFilePostContentStore tests the deduplication with content-addressed file store
+func TestFilePostContentStore(t *testing.T) {
+	// Create a temporary directory for the file store
+	tempDir := t.TempDir()
+
+	// Initialize the file store
+	fs, err := filestore.NewFileContentStore(tempDir)
+	if err != nil {
+		t.Fatalf("Failed to create file store: %v", err)
+	}
+	router := gin.Default()
+	f := &fileHandle{fs: fs}
+	router.POST("/file", f.filePost)
+	router.HEAD("/file/:fid", f.fileIDHead)
+
+	contentToWrite := "print(58 - 7 * 3)"
+	sum := sha256.Sum256([]byte(contentToWrite))
+	expectedID := hex.EncodeToString(sum[:])
+
+	// Upload the same content twice
+	for range 2 {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		fileWriter, err := writer.CreateFormFile("file", "test.py")
+		if err != nil {
+			t.Fatalf("Failed to create form file: %v", err)
+		}
+		fileWriter.Write([]byte(contentToWrite))
+		writer.Close()
+
+		req := httptest.NewRequest("POST", "/file", body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+		}
+		if w.Body.String() != `"`+expectedID+`"` {
+			t.Fatalf("Expected file ID %s, got %s", expectedID, w.Body.String())
+		}
+	}
+
+	// Check the file exists and skip the upload with digest
+	tests := []struct {
+		method, url string
+		code        int
+	}{
+		{"HEAD", "/file/" + expectedID, http.StatusOK},
+		{"POST", "/file?sha256=" + expectedID, http.StatusOK},
+		{"HEAD", "/file/" + strings.Repeat("0", 64), http.StatusNotFound},
+		{"POST", "/file?sha256=" + strings.Repeat("0", 64), http.StatusNotFound},
+	}
+	for _, tc := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(tc.method, tc.url, nil))
+		if w.Code != tc.code {
+			t.Fatalf("%s %s: expected status %d, got %d", tc.method, tc.url, tc.code, w.Code)
+		}
+	}
+
+	// Identical content is stored once with 3 references
+	for i := range 3 {
+		if _, file := fs.Get(expectedID); file == nil {
+			t.Fatalf("File should exist after %d removes", i)
+		}
+		fs.Remove(expectedID)
+	}
+	if _, file := fs.Get(expectedID); file != nil {
+		t.Fatalf("File should be removed after all references removed")
+	}
+}
+
+// TestFilePostDigestNotContentAddressed tests the digest upload is rejected
+// when the wrapped file store is not content addressed
+func TestFilePostDigestNotContentAddressed(t *testing.T) {
+	stores := map[string]filestore.FileStore{
+		"local": filestore.NewFileLocalStore(t.TempDir()),
+		"lru":   filestore.NewLRU(filestore.NewFileLocalStore(t.TempDir()), 0, 0),
+	}
+	for name, fs := range stores {
+		router := gin.Default()
+		f := &fileHandle{fs: fs}
+		router.POST("/file", f.filePost)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/file?sha256="+strings.Repeat("0", 64), nil))
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected status %d, got %d", name, http.StatusBadRequest, w.Code)
+		}
+	}
+}
+
+// TestFilePostBundle tests archive upload as bundle and rejection of entries
+// outside the bundle
+func TestFilePostBundle(t *testing.T) {
+	tempDir := t.TempDir()
+
+	router := gin.Default()
+	f := &fileHandle{fs: filestore.NewFileLocalStore(tempDir)}
+	router.POST("/file", f.filePost)
+
+	post := func(files map[string]string) *httptest.ResponseRecorder {
+		archive := &bytes.Buffer{}
+		tw := tar.NewWriter(archive)
+		for name, content := range files {
+			tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+			tw.Write([]byte(content))
+		}
+		tw.Close()
+
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		writer.WriteField("bundle", "true")
+		fileWriter, err := writer.CreateFormFile("file", "data.tar")
+		if err != nil {
+			t.Fatalf("Failed to create form file: %v", err)
+		}
+		fileWriter.Write(archive.Bytes())
+		writer.Close()
+
+		req := httptest.NewRequest("POST", "/file", body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post(map[string]string{"a/1.in": "1 2", "a/1.ans": "3"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	fileID := strings.Trim(w.Body.String(), `"`)
+	content, err := os.ReadFile(filepath.Join(tempDir, fileID, "a", "1.ans"))
+	if err != nil {
+		t.Fatalf("Failed to read bundle file: %v", err)
+	}
+	if string(content) != "3" {
+		t.Fatalf("Bundle file content does not match: expected 3, got %s", content)
+	}
+
+	w = post(map[string]string{"../evil": "x"})
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(tempDir), "evil")); err == nil {
+		t.Fatalf("File outside the bundle should not be created")
+	}
+}
@@ -0,0 +1,116 @@
+// Package tlsconfig loads TLS certificates for the go judge servers and clients
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync/atomic"
+)
+
+// Server holds the server certificate and the client CA which can be reloaded
+// without restarting the servers
+type Server struct {
+	certFile, keyFile, clientCAFile string
+	requireClientCert               bool
+
+	config atomic.Pointer[tls.Config]
+}
+
+// NewServer loads the certificate, key and the optional client CA. If
+// requireClientCert, connections without a certificate signed by the client
+// CA are rejected, otherwise client certificates are verified if given.
+func NewServer(certFile, keyFile, clientCAFile string, requireClientCert bool) (*Server, error) {
+	if certFile == "" || keyFile == "" {
+		return nil, errors.New("tls: both certificate and key are required")
+	}
+	if requireClientCert && clientCAFile == "" {
+		return nil, errors.New("tls: client CA is required to verify client certificates")
+	}
+	s := &Server{
+		certFile:          certFile,
+		keyFile:           keyFile,
+		clientCAFile:      clientCAFile,
+		requireClientCert: requireClientCert,
+	}
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Reload reloads the certificate, key and client CA, new connections use the
+// reloaded files while the existing connections are not affected
+func (s *Server) Reload() error {
+	cert, err := tls.LoadX509KeyPair(s.certFile, s.keyFile)
+	if err != nil {
+		return fmt.Errorf("tls: %w", err)
+	}
+	c := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+		NextProtos:   []string{"h2", "http/1.1"},
+	}
+	if s.clientCAFile != "" {
+		c.ClientCAs, err = loadCertPool(s.clientCAFile)
+		if err != nil {
+			return err
+		}
+		c.ClientAuth = tls.VerifyClientCertIfGiven
+		if s.requireClientCert {
+			c.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	}
+	s.config.Store(c)
+	return nil
+}
+
+// Config returns the TLS config for servers which always uses the latest
+// loaded certificates
+func (s *Server) Config() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		NextProtos: []string{"h2", "http/1.1"},
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return s.config.Load(), nil
+		},
+	}
+}
+
+// Client creates the TLS config for clients. The server certificate is
+// verified with caFile (system roots if empty), and the client certificate
+// is presented if both certFile and keyFile are specified.
+func Client(caFile, certFile, keyFile string) (*tls.Config, error) {
+	c := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+	if caFile != "" {
+		pool, err := loadCertPool(caFile)
+		if err != nil {
+			return nil, err
+		}
+		c.RootCAs = pool
+	}
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("tls: %w", err)
+		}
+		c.Certificates = []tls.Certificate{cert}
+	}
+	return c, nil
+}
+
+func loadCertPool(path string) (*x509.CertPool, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("tls: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(b) {
+		return nil, fmt.Errorf("tls: no certificate found in %s", path)
+	}
+	return pool, nil
+}
@@ -7,6 +7,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/criyle/go-judge/cmd/go-judge/auth"
 	"github.com/criyle/go-judge/cmd/go-judge/model"
 	"github.com/criyle/go-judge/cmd/go-judge/stream"
 	"github.com/criyle/go-judge/worker"
@@ -68,6 +69,7 @@ func (h *wsHandle) handleWS(c *gin.Context) {
 	}
 	resultCh := make(chan model.Response, 128)
 	cm := newContextMap()
+	srcPrefix := auth.SrcPrefix(c.Request.Context(), h.srcPrefix)
 
 	handleRequest := func(baseCtx context.Context, req *wsRequest) error {
 		if req.CancelRequestID != "" {
@@ -75,7 +77,7 @@ func (h *wsHandle) handleWS(c *gin.Context) {
 			cm.Remove(req.CancelRequestID)
 			return nil
 		}
-		r, err := model.ConvertRequest(&req.Request, h.srcPrefix)
+		r, err := model.ConvertRequest(&req.Request, srcPrefix)
 		if err != nil {
 			return fmt.Errorf("ws convert error: %w", err)
 		}
@@ -201,7 +203,7 @@ func (h *wsHandle) handleStream(c *gin.Context) {
 
 	w := &streamWrapper{ctx: ctx, conn: conn, sendCh: make(chan stream.Response)}
 	go w.sendLoop()
-	if err := stream.Start(ctx, w, h.worker, h.srcPrefix, h.logger); err != nil {
+	if err := stream.Start(ctx, w, h.worker, auth.SrcPrefix(c.Request.Context(), h.srcPrefix), h.logger); err != nil {
 		h.logger.Debug("stream start", zap.Error(err))
 		c.Error(err)
 	}
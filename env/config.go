@@ -9,6 +9,7 @@ type Config struct {
 	NetShare           bool
 	MountConf          string
 	SeccompConf        string
+	SeccompAudit       bool
 	CgroupPrefix       string
 	Cpuset             string
 	ContainerCredStart int
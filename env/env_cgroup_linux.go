@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"slices"
+	"strings"
 
 	"github.com/coreos/go-systemd/v22/dbus"
 	"github.com/criyle/go-judge/env/linuxcontainer"
@@ -142,9 +144,41 @@ func createAndNestCgroup(prefix string, ct *cgroup.Controllers, noFallback bool,
 	if ct != nil && !ct.Pids {
 		logger.Warn("pid cgroup is not enabled, proc limit does not have effect")
 	}
+	if cgroup.DetectedCgroupType == cgroup.TypeV2 && !enableIOController(cgb, cg) {
+		logger.Warn("io cgroup is not enabled, io limit does not have effect")
+	}
 	return cg, ct, nil
 }
 
+// enableIOController enables io controller for the containers cgroup and its
+// children since go-sandbox only manages cpu, cpuset, memory and pids controllers
+func enableIOController(cgs ...cgroup.Cgroup) bool {
+	for _, cg := range cgs {
+		v2, ok := cg.(*cgroup.V2)
+		if !ok {
+			return false
+		}
+		b, err := v2.ReadFile("cgroup.controllers")
+		if err != nil || !slices.Contains(strings.Fields(string(b)), "io") {
+			return false
+		}
+		if err := v2.WriteFile("cgroup.subtree_control", []byte("+io")); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// hasIOController checks whether io controller is enabled for the children
+func hasIOController(cg cgroup.Cgroup) bool {
+	v2, ok := cg.(*cgroup.V2)
+	if !ok {
+		return false
+	}
+	b, err := v2.ReadFile("cgroup.subtree_control")
+	return err == nil && slices.Contains(strings.Fields(string(b)), "io")
+}
+
 func prepareCgroupPool(cgb cgroup.Cgroup, c Config) linuxcontainer.CgroupPool {
 	if cgb != nil {
 		return linuxcontainer.NewFakeCgroupPool(cgb, c.CPUCfsPeriod)
@@ -161,6 +195,9 @@ func getCgroupInfo(cgb cgroup.Cgroup, ct *cgroup.Controllers) (int, []string) {
 	if ct != nil {
 		cgroupControllers = ct.Names()
 	}
+	if cgb != nil && hasIOController(cgb) {
+		cgroupControllers = append(cgroupControllers, "io")
+	}
 	return cgroupType, cgroupControllers
 }
 
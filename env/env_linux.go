@@ -39,6 +39,10 @@ func NewBuilder(c Config, logger *zap.Logger) (pool.EnvBuilder, map[string]any,
 	if err != nil {
 		return nil, nil, err
 	}
+	seccompAudit, auditor, err := prepareSeccompAudit(c, logger)
+	if err != nil {
+		return nil, nil, err
+	}
 	unshareFlags, unshareCgroup := prepareUnshareFlags(c, logger)
 	credGen := prepareCredGen(c)
 	hostName, domainName, workDir, cUID, cGID, initCmd, err := prepareContainerMeta(mountsConfig, logger)
@@ -83,21 +87,32 @@ func NewBuilder(c Config, logger *zap.Logger) (pool.EnvBuilder, map[string]any,
 		"uid":               cUID,
 		"gid":               cGID,
 		"cgroupControllers": cgroupControllers,
+		"seccompAudit":      auditor != nil,
+		"network":           !c.NetShare,
 	}
 
-	if tryClone3Builder := tryClone3(c, b, cgb, cgroupType, cgroupPool, workDir, seccomp, logger); tryClone3Builder != nil {
+	lc := linuxcontainer.Config{
+		Builder:      b,
+		CgroupPool:   cgroupPool,
+		WorkDir:      workDir,
+		Cpuset:       c.Cpuset,
+		CPURate:      c.EnableCPURate,
+		Seccomp:      seccomp,
+		SeccompAudit: seccompAudit,
+		Auditor:      auditor,
+	}
+	if !c.NetShare {
+		// containers built inside the shared network namespace of the thread
+		nb := *b
+		nb.CloneFlags &^= syscall.CLONE_NEWNET
+		lc.NetBuilder = &nb
+	}
+	if tryClone3Builder := tryClone3(lc, cgb, cgroupType, logger); tryClone3Builder != nil {
 		conf["clone3"] = true
 		return tryClone3Builder, conf, nil
 	}
 
-	return linuxcontainer.NewEnvBuilder(linuxcontainer.Config{
-		Builder:    b,
-		CgroupPool: cgroupPool,
-		WorkDir:    workDir,
-		Cpuset:     c.Cpuset,
-		CPURate:    c.EnableCPURate,
-		Seccomp:    seccomp,
-	}), conf, nil
+	return linuxcontainer.NewEnvBuilder(lc), conf, nil
 }
 
 func prepareMountAndPaths(c Config, logger *zap.Logger) (*Mounts, *mount.Builder, []container.SymbolicLink, []string, error) {
@@ -144,6 +159,24 @@ func prepareSeccomp(c Config, logger *zap.Logger) ([]syscall.SockFilter, error)
 	return seccomp, nil
 }
 
+func prepareSeccompAudit(c Config, logger *zap.Logger) ([]syscall.SockFilter, linuxcontainer.SyscallAuditor, error) {
+	if !c.SeccompAudit {
+		return nil, nil, nil
+	}
+	filter, err := readSeccompAuditConf(c.SeccompConf)
+	if err != nil {
+		logger.Error("failed to load seccomp audit config", zap.String("path", c.SeccompConf), zap.Error(err))
+		return nil, nil, fmt.Errorf("failed to load seccomp audit config: %w", err)
+	}
+	auditor, err := linuxcontainer.NewKmsgAuditor()
+	if err != nil {
+		logger.Warn("failed to open kernel log, seccomp audit is disabled", zap.Error(err))
+		return nil, nil, nil
+	}
+	logger.Info("seccomp audit enabled, syscalls are logged instead of denied for audit requests")
+	return filter, auditor, nil
+}
+
 func prepareUnshareFlags(c Config, logger *zap.Logger) (uintptr, bool) {
 	unshareFlags := uintptr(forkexec.UnshareFlags)
 	if c.NetShare {
@@ -207,13 +240,9 @@ func prepareContainerMeta(mc *Mounts, logger *zap.Logger) (hostName, domainName,
 }
 
 func tryClone3(
-	c Config,
-	envBuilder linuxcontainer.EnvironmentBuilder,
+	lc linuxcontainer.Config,
 	cgb cgroup.Cgroup,
 	cgroupType int,
-	cgroupPool linuxcontainer.CgroupPool,
-	workDir string,
-	seccomp []syscall.SockFilter,
 	logger *zap.Logger,
 ) pool.EnvBuilder {
 	major, minor := kernelVersion()
@@ -223,15 +252,8 @@ func tryClone3(
 	logger.Info("running kernel >= 5.7 with cgroup V2, trying faster clone3(CLONE_INTO_CGROUP)",
 		zap.Int("major", major), zap.Int("minor", minor))
 
-	b := linuxcontainer.NewEnvBuilder(linuxcontainer.Config{
-		Builder:    envBuilder,
-		CgroupPool: cgroupPool,
-		WorkDir:    workDir,
-		Cpuset:     c.Cpuset,
-		CPURate:    c.EnableCPURate,
-		Seccomp:    seccomp,
-		CgroupFd:   true,
-	})
+	lc.CgroupFd = true
+	b := linuxcontainer.NewEnvBuilder(lc)
 	e, err := b.Build()
 	if err != nil {
 		logger.Info("environment build failed", zap.Error(err))
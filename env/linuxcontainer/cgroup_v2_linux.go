@@ -0,0 +1,126 @@
+package linuxcontainer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/criyle/go-judge/envexec"
+	"github.com/criyle/go-sandbox/pkg/cgroup"
+	"golang.org/x/sys/unix"
+)
+
+const sysBlockPath = "/sys/block"
+
+// blockDevices lists the "major:minor" of all block devices, io.max only
+// accepts whole disks rather than partitions
+var blockDevices = sync.OnceValues(func() ([]string, error) {
+	entries, err := os.ReadDir(sysBlockPath)
+	if err != nil {
+		return nil, err
+	}
+	var devs []string
+	for _, e := range entries {
+		b, err := os.ReadFile(filepath.Join(sysBlockPath, e.Name(), "dev"))
+		if err != nil {
+			continue
+		}
+		devs = append(devs, strings.TrimSpace(string(b)))
+	}
+	return devs, nil
+})
+
+// SetIOLimit writes io.max for every block device, zero keeps the value unlimited.
+// It is only available with cgroup v2 and io controller enabled.
+func (c *wCgroup) SetIOLimit(readBps, writeBps envexec.Size, readIOPS, writeIOPS uint64) error {
+	v2, ok := c.cg.(*cgroup.V2)
+	if !ok {
+		return cgroup.ErrNotInitialized
+	}
+	devs, err := blockDevices()
+	if err != nil {
+		return err
+	}
+	limit := ioMaxValue("rbps", uint64(readBps)) + ioMaxValue(" wbps", uint64(writeBps)) +
+		ioMaxValue(" riops", readIOPS) + ioMaxValue(" wiops", writeIOPS)
+	for _, d := range devs {
+		// devices without request queue (e.g. some virtual devices) are not supported
+		if err := v2.WriteFile("io.max", []byte(d+" "+limit)); err != nil && !errors.Is(err, unix.ENODEV) {
+			return fmt.Errorf("io.max %s: %w", d, err)
+		}
+	}
+	return nil
+}
+
+// IOUsage sums rbytes and wbytes of all devices in io.stat
+func (c *wCgroup) IOUsage() (read, write envexec.Size, err error) {
+	v2, ok := c.cg.(*cgroup.V2)
+	if !ok {
+		return 0, 0, cgroup.ErrNotInitialized
+	}
+	b, err := v2.ReadFile("io.stat")
+	if err != nil {
+		return 0, 0, err
+	}
+	// 8:0 rbytes=1 wbytes=2 rios=3 wios=4 dbytes=5 dios=6
+	for _, l := range bytes.Split(b, []byte{'\n'}) {
+		for _, f := range bytes.Fields(l) {
+			k, v, ok := bytes.Cut(f, []byte{'='})
+			if !ok {
+				continue
+			}
+			n, err := strconv.ParseUint(string(v), 10, 64)
+			if err != nil {
+				continue
+			}
+			switch string(k) {
+			case "rbytes":
+				read += envexec.Size(n)
+			case "wbytes":
+				write += envexec.Size(n)
+			}
+		}
+	}
+	return read, write, nil
+}
+
+func ioMaxValue(key string, v uint64) string {
+	if v == 0 {
+		return key + "=max"
+	}
+	return key + "=" + strconv.FormatUint(v, 10)
+}
+
+// SetMemoryHigh writes memory.high, above which the processes are throttled
+// and reclaimed instead of killed. It is only available with cgroup v2.
+func (c *wCgroup) SetMemoryHigh(s envexec.Size) error {
+	v2, ok := c.cg.(*cgroup.V2)
+	if !ok {
+		return cgroup.ErrNotInitialized
+	}
+	return v2.WriteUint("memory.high", uint64(s))
+}
+
+// MemoryHighEvents reads the high count in memory.events
+func (c *wCgroup) MemoryHighEvents() (uint64, error) {
+	v2, ok := c.cg.(*cgroup.V2)
+	if !ok {
+		return 0, cgroup.ErrNotInitialized
+	}
+	b, err := v2.ReadFile("memory.events")
+	if err != nil {
+		return 0, err
+	}
+	// low 0\nhigh 1\nmax 2\noom 0\noom_kill 0
+	for _, l := range bytes.Split(b, []byte{'\n'}) {
+		if v, ok := bytes.CutPrefix(l, []byte("high ")); ok {
+			return strconv.ParseUint(string(bytes.TrimSpace(v)), 10, 64)
+		}
+	}
+	return 0, nil
+}
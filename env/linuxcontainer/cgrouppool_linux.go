@@ -12,13 +12,17 @@ import (
 type Cgroup interface {
 	SetCpuset(string) error
 	SetMemoryLimit(envexec.Size) error
+	SetMemoryHigh(envexec.Size) error
 	SetProcLimit(uint64) error
 	SetCPURate(uint64) error // 1000 as 1
+	SetIOLimit(readBps, writeBps envexec.Size, readIOPS, writeIOPS uint64) error
 
 	CPUUsage() (time.Duration, error)
 	CurrentMemory() (envexec.Size, error)
 	MaxMemory() (envexec.Size, error)
+	MemoryHighEvents() (uint64, error)
 	ProcPeak() (uint64, error)
+	IOUsage() (read, write envexec.Size, err error)
 
 	AddProc(int) error
 	Reset() error
@@ -0,0 +1,94 @@
+package linuxcontainer
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/criyle/go-judge/envexec"
+	"golang.org/x/sys/unix"
+)
+
+var _ envexec.DiskLimiter = &environ{}
+
+// tmpfsLimit stores size and inode limit of the work dir tmpfs
+type tmpfsLimit struct {
+	size   uint64
+	inodes uint64
+}
+
+// SetDiskLimit reconfigures the work dir tmpfs with the size and inode limit,
+// zero keeps the original limit. It is restored when the environment reset.
+func (c *environ) SetDiskLimit(size envexec.Size, files uint64) error {
+	if c.diskDefault == nil {
+		var st unix.Statfs_t
+		if err := unix.Fstatfs(int(c.wd.Fd()), &st); err != nil {
+			return fmt.Errorf("disk limit: statfs: %w", err)
+		}
+		if st.Type != unix.TMPFS_MAGIC {
+			return fmt.Errorf("disk limit: work dir is not tmpfs")
+		}
+		c.diskDefault = &tmpfsLimit{
+			size:   st.Blocks * uint64(st.Bsize),
+			inodes: st.Files,
+		}
+	}
+	l := *c.diskDefault
+	if size > 0 {
+		l.size = uint64(size)
+	}
+	if files > 0 {
+		l.inodes = files
+	}
+	c.diskChanged = true
+	if err := c.reconfigureTmpfs(l); err != nil {
+		return fmt.Errorf("disk limit: %w", err)
+	}
+	return nil
+}
+
+// DiskUsage returns the usage of work dir file system
+func (c *environ) DiskUsage() (envexec.DiskUsage, error) {
+	var st unix.Statfs_t
+	if err := unix.Fstatfs(int(c.wd.Fd()), &st); err != nil {
+		return envexec.DiskUsage{}, err
+	}
+	// tmpfs reports zero total for unlimited size / inodes
+	return envexec.DiskUsage{
+		Size:  envexec.Size((st.Blocks - st.Bfree) * uint64(st.Bsize)),
+		Files: st.Files - st.Ffree,
+		Full:  (st.Blocks > 0 && st.Bavail == 0) || (st.Files > 0 && st.Ffree == 0),
+	}, nil
+}
+
+// restoreDiskLimit restores the work dir tmpfs limit after reset
+func (c *environ) restoreDiskLimit() error {
+	if !c.diskChanged {
+		return nil
+	}
+	if err := c.reconfigureTmpfs(*c.diskDefault); err != nil {
+		return fmt.Errorf("disk limit: restore: %w", err)
+	}
+	c.diskChanged = false
+	return nil
+}
+
+// reconfigureTmpfs reconfigures the tmpfs mounted at work dir through the
+// opened work dir, which also works from outside of the mount namespace
+func (c *environ) reconfigureTmpfs(l tmpfsLimit) error {
+	fd, err := unix.Fspick(int(c.wd.Fd()), "", unix.FSPICK_EMPTY_PATH|unix.FSPICK_CLOEXEC)
+	if err != nil {
+		return fmt.Errorf("fspick: %w", err)
+	}
+	defer unix.Close(fd)
+
+	if err := unix.FsconfigSetString(fd, "size", strconv.FormatUint(l.size, 10)); err != nil {
+		return fmt.Errorf("fsconfig size: %w", err)
+	}
+	if err := unix.FsconfigSetString(fd, "nr_inodes", strconv.FormatUint(l.inodes, 10)); err != nil {
+		return fmt.Errorf("fsconfig nr_inodes: %w", err)
+	}
+	if err := unix.FsconfigReconfigure(fd); err != nil {
+		return fmt.Errorf("fsconfig reconfigure: %w", err)
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package linuxcontainer
 
 import (
 	"fmt"
+	"os"
 	"syscall"
 
 	"github.com/criyle/go-judge/env/pool"
@@ -17,6 +18,15 @@ type Config struct {
 	Cpuset     string
 	CPURate    bool
 	CgroupFd   bool // whether to enable cgroup fd with clone3, kernel >= 5.7
+
+	// SeccompAudit is the filter used when execve requests audit mode and
+	// Auditor collects the syscalls it logs
+	SeccompAudit []syscall.SockFilter
+	Auditor      SyscallAuditor
+
+	// NetBuilder builds container inside the network namespace of the calling
+	// thread, nil disables private network namespace
+	NetBuilder EnvironmentBuilder
 }
 
 type environmentBuilder struct {
@@ -27,6 +37,10 @@ type environmentBuilder struct {
 	cpuset  string
 	cpuRate bool
 	cgFd    bool
+
+	seccompAudit []syscall.SockFilter
+	auditor      SyscallAuditor
+	netBuilder   EnvironmentBuilder
 }
 
 // NewEnvBuilder creates builder for linux container pools
@@ -39,6 +53,10 @@ func NewEnvBuilder(c Config) pool.EnvBuilder {
 		cpuset:  c.Cpuset,
 		cpuRate: c.CPURate,
 		cgFd:    c.CgroupFd,
+
+		seccompAudit: c.SeccompAudit,
+		auditor:      c.Auditor,
+		netBuilder:   c.NetBuilder,
 	}
 }
 
@@ -48,6 +66,10 @@ func (b *environmentBuilder) Build() (pool.Environment, error) {
 	if err != nil {
 		return nil, err
 	}
+	return b.newEnviron(m, nil)
+}
+
+func (b *environmentBuilder) newEnviron(m container.Environment, netns *os.File) (pool.Environment, error) {
 	wd, err := m.Open([]container.OpenCmd{{
 		Path: b.workDir,
 		Flag: syscall.O_CLOEXEC | syscall.O_DIRECTORY,
@@ -65,5 +87,9 @@ func (b *environmentBuilder) Build() (pool.Environment, error) {
 		cpuRate:     b.cpuRate,
 		seccomp:     b.seccomp,
 		cgFd:        b.cgFd,
+
+		seccompAudit: b.seccompAudit,
+		auditor:      b.auditor,
+		netns:        netns,
 	}, nil
 }
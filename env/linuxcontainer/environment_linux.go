@@ -29,6 +29,13 @@ type environ struct {
 	seccomp []syscall.SockFilter
 	cpuRate bool
 	cgFd    bool
+
+	seccompAudit []syscall.SockFilter
+	auditor      SyscallAuditor
+	netns        *os.File // private network namespace with loopback, nil if not
+
+	diskDefault *tmpfsLimit // original work dir limit
+	diskChanged bool        // work dir limit changed by SetDiskLimit
 }
 
 // Destroy destroys the environment
@@ -37,7 +44,10 @@ func (c *environ) Destroy() error {
 }
 
 func (c *environ) Reset() error {
-	return c.Environment.Reset()
+	if err := c.Environment.Reset(); err != nil {
+		return err
+	}
+	return c.restoreDiskLimit()
 }
 
 // Execve execute process inside the environment
@@ -49,6 +59,10 @@ func (c *environ) Execve(ctx context.Context, param envexec.ExecveParam) (envexe
 		cgFd     uintptr
 	)
 
+	if err := c.checkNetwork(param.Network); err != nil {
+		return nil, err
+	}
+
 	limit := param.Limit
 	if c.cgPool != nil {
 		cg, err = c.cgPool.Get()
@@ -75,7 +89,7 @@ func (c *environ) Execve(ctx context.Context, param envexec.ExecveParam) (envexe
 		FileSize:    limit.Output.Byte(),
 		Stack:       limit.Stack.Byte(),
 		OpenFile:    limit.OpenFile,
-		DisableCore: true,
+		DisableCore: param.CoreLimit == 0,
 	}
 
 	if limit.DataSegment || c.cgPool == nil {
@@ -85,6 +99,22 @@ func (c *environ) Execve(ctx context.Context, param envexec.ExecveParam) (envexe
 		rLimits.AddressSpace = limit.Memory.Byte()
 	}
 
+	seccomp := c.seccomp
+	m := &monitor{
+		traceFile:    param.SyscallTrace,
+		traceLimit:   param.SyscallTraceLimit,
+		traceProcess: param.TraceProcess,
+		perfEnabled:  param.Perf,
+		core:         param.CoreLimit > 0,
+	}
+	if param.SeccompAudit {
+		if c.seccompAudit == nil || c.auditor == nil {
+			return nil, fmt.Errorf("execve: seccomp audit is not enabled")
+		}
+		seccomp = c.seccompAudit
+		m.audit = &auditCollector{auditor: c.auditor}
+	}
+
 	// wait for sync or error before turn (avoid file close before pass to child process)
 	syncDone := make(chan struct{})
 
@@ -94,21 +124,24 @@ func (c *environ) Execve(ctx context.Context, param envexec.ExecveParam) (envexe
 		Files:    param.Files,
 		CTTY:     param.TTY,
 		ExecFile: param.ExecFile,
-		RLimits:  rLimits.PrepareRLimit(),
-		Seccomp:  c.seccomp,
+		RLimits:  prepareRLimit(rLimits, param.CoreLimit),
+		Seccomp:  seccomp,
 		SyncFunc: func(pid int) error {
 			defer close(syncDone)
+			if err := m.sync(pid); err != nil {
+				return err
+			}
 			if syncFunc != nil {
 				return syncFunc(pid)
 			}
 			return nil
 		},
-		SyncAfterExec: syncFunc == nil,
+		SyncAfterExec: syncFunc == nil && !m.syncBeforeExec(),
 		CgroupFD:      cgFd,
 	}
 	proc := newProcess(func() runner.Result {
 		return c.Environment.Execve(ctx, p)
-	}, cg, c.cgPool)
+	}, cg, c.cgPool, m)
 
 	select {
 	case <-proc.done:
@@ -291,12 +324,34 @@ func (c *environ) setCgroupLimit(cg Cgroup, limit envexec.Limit) error {
 	if err := cg.SetMemoryLimit(limit.Memory); isCgroupSetHasError(err) {
 		return fmt.Errorf("execve: cgroup: failed to set memory limit: %w", err)
 	}
+	if limit.MemoryHigh > 0 {
+		if err := cg.SetMemoryHigh(limit.MemoryHigh); isCgroupSetHasError(err) {
+			return fmt.Errorf("execve: cgroup: failed to set memory high: %w", err)
+		}
+	}
 	if err := cg.SetProcLimit(limit.Proc); isCgroupSetHasError(err) {
 		return fmt.Errorf("execve: cgroup: failed to set process limit: %w", err)
 	}
+	if limit.IOReadBps > 0 || limit.IOWriteBps > 0 || limit.IOReadIOPS > 0 || limit.IOWriteIOPS > 0 {
+		if err := cg.SetIOLimit(limit.IOReadBps, limit.IOWriteBps, limit.IOReadIOPS, limit.IOWriteIOPS); isCgroupSetHasError(err) {
+			return fmt.Errorf("execve: cgroup: failed to set io limit: %w", err)
+		}
+	}
 	return nil
 }
 
+// prepareRLimit appends the core limit which is not covered by rlimit.RLimits
+func prepareRLimit(r rlimit.RLimits, core envexec.Size) []rlimit.RLimit {
+	rt := r.PrepareRLimit()
+	if core > 0 {
+		rt = append(rt, rlimit.RLimit{
+			Res:  syscall.RLIMIT_CORE,
+			Rlim: syscall.Rlimit{Cur: core.Byte(), Max: core.Byte()},
+		})
+	}
+	return rt
+}
+
 func isCgroupSetHasError(err error) bool {
 	return err != nil && !errors.Is(err, cgroup.ErrNotInitialized) && !errors.Is(err, os.ErrNotExist)
 }
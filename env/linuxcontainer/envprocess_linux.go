@@ -1,25 +1,37 @@
 package linuxcontainer
 
 import (
+	"os"
 	"time"
 
 	"github.com/criyle/go-judge/envexec"
 	"github.com/criyle/go-sandbox/runner"
 )
 
-var _ envexec.Process = &process{}
+var (
+	_ envexec.AuditProcess = &process{}
+	_ envexec.StatProcess  = &process{}
+	_ envexec.TraceProcess = &process{}
+	_ envexec.PidProcess   = &process{}
+)
 
 // process defines the running process
 type process struct {
 	rt   runner.Result
 	done chan struct{}
 	cg   Cgroup
+
+	monitor   *monitor
+	syscalls  []string
+	stat      envexec.ProcessStat
+	processes []envexec.ProcessInfo
 }
 
-func newProcess(run func() runner.Result, cg Cgroup, cgPool CgroupPool) *process {
+func newProcess(run func() runner.Result, cg Cgroup, cgPool CgroupPool, m *monitor) *process {
 	p := &process{
-		done: make(chan struct{}),
-		cg:   cg,
+		done:    make(chan struct{}),
+		cg:      cg,
+		monitor: m,
 	}
 	go func() {
 		defer close(p.done)
@@ -28,6 +40,7 @@ func newProcess(run func() runner.Result, cg Cgroup, cgPool CgroupPool) *process
 		}
 		p.rt = run()
 		p.collectUsage()
+		p.monitor.finish(p)
 	}()
 	return p
 }
@@ -45,6 +58,13 @@ func (p *process) collectUsage() {
 	if pp, err := p.cg.ProcPeak(); err == nil && pp > 0 {
 		p.rt.ProcPeak = pp
 	}
+	if r, w, err := p.cg.IOUsage(); err == nil {
+		p.stat.IORead = r
+		p.stat.IOWrite = w
+	}
+	if n, err := p.cg.MemoryHighEvents(); err == nil {
+		p.stat.MemoryHighEvents = n
+	}
 }
 
 func (p *process) Done() <-chan struct{} {
@@ -56,6 +76,26 @@ func (p *process) Result() envexec.RunnerResult {
 	return p.rt
 }
 
+func (p *process) Syscalls() []string {
+	<-p.done
+	return p.syscalls
+}
+
+func (p *process) Processes() []envexec.ProcessInfo {
+	<-p.done
+	return p.processes
+}
+
+func (p *process) Pid() int {
+	<-p.done
+	return p.monitor.pid
+}
+
+func (p *process) Stat() envexec.ProcessStat {
+	<-p.done
+	return p.stat
+}
+
 func (p *process) Usage() envexec.Usage {
 	var (
 		t time.Duration
@@ -70,3 +110,60 @@ func (p *process) Usage() envexec.Usage {
 		Memory: m,
 	}
 }
+
+// monitor attaches additional collectors to the process when it is synced and
+// finishes them after the process exits
+type monitor struct {
+	audit *auditCollector
+
+	core bool
+	pid  int // pid in the container pid namespace for the core file name
+
+	traceFile    *os.File
+	traceLimit   envexec.Size
+	traceProcess bool
+	tracer       *syscallTracer
+
+	perfEnabled bool
+	perf        *perfCollector
+}
+
+// syncBeforeExec returns whether the collectors need the sync before execve
+func (m *monitor) syncBeforeExec() bool {
+	return m.traceFile != nil || m.traceProcess || m.perfEnabled
+}
+
+func (m *monitor) sync(pid int) error {
+	if m.core {
+		_, m.pid = readProcStatus(pid)
+	}
+	if m.perfEnabled {
+		m.perf = openPerf(pid)
+	}
+	if m.audit != nil {
+		if err := m.audit.watch(pid); err != nil {
+			return err
+		}
+	}
+	if m.traceFile != nil || m.traceProcess {
+		t, err := startSyscallTrace(pid, m.traceFile, m.traceLimit, m.traceProcess)
+		if err != nil {
+			return err
+		}
+		m.tracer = t
+	}
+	return nil
+}
+
+func (m *monitor) finish(p *process) {
+	if m.audit != nil {
+		p.syscalls = m.audit.collect()
+	}
+	if m.tracer != nil {
+		m.tracer.wait()
+		p.processes = m.tracer.processes()
+	}
+	if m.perf != nil {
+		p.stat.Perf = m.perf.collect()
+	}
+}
@@ -14,3 +14,8 @@ type EnvironmentBuilder interface {
 type CgroupBuilder interface {
 	Random(string) (cg cgroup.Cgroup, err error)
 }
+
+// SyscallAuditor collects syscalls logged by the seccomp audit filter
+type SyscallAuditor interface {
+	Watch(pid int) (stop func() []string, err error)
+}
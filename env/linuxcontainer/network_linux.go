@@ -0,0 +1,176 @@
+package linuxcontainer
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/criyle/go-judge/env/pool"
+	"github.com/criyle/go-judge/envexec"
+	"github.com/criyle/go-sandbox/container"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	threadNetnsPath = "/proc/thread-self/ns/net"
+
+	// tcpListen is the TCP_LISTEN state in /proc/net/tcp
+	tcpListen = "0A"
+)
+
+var (
+	_ pool.NetworkBuilder = &networkBuilder{}
+	_ envexec.PortProber  = &environ{}
+)
+
+// networkBuilder builds environments inside the same private network namespace
+type networkBuilder struct {
+	*environmentBuilder
+	netns *os.File
+}
+
+// NewNetwork creates a private network namespace with loopback interface up
+// and returns the builder for environments sharing it
+func (b *environmentBuilder) NewNetwork() (pool.NetworkBuilder, error) {
+	if b.netBuilder == nil {
+		return nil, fmt.Errorf("network: private network namespace is not enabled")
+	}
+	var netns *os.File
+	err := inNetns(func() error {
+		return unix.Unshare(unix.CLONE_NEWNET)
+	}, func() (err error) {
+		if err = setLoopbackUp(); err != nil {
+			return err
+		}
+		netns, err = os.Open(threadNetnsPath)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("network: failed to create network namespace: %w", err)
+	}
+	return &networkBuilder{environmentBuilder: b, netns: netns}, nil
+}
+
+// Build creates linux container inside the network namespace
+func (b *networkBuilder) Build() (pool.Environment, error) {
+	var m container.Environment
+	err := inNetns(func() error {
+		return unix.Setns(int(b.netns.Fd()), unix.CLONE_NEWNET)
+	}, func() (err error) {
+		// the container init inherits the network namespace of the thread
+		m, err = b.netBuilder.Build()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return b.newEnviron(m, b.netns)
+}
+
+// Destroy releases the network namespace, it is removed once all environments
+// inside it are destroyed
+func (b *networkBuilder) Destroy() error {
+	return b.netns.Close()
+}
+
+// checkNetwork checks whether the network mode is available in the environment
+func (c *environ) checkNetwork(mode envexec.NetworkMode) error {
+	if mode != envexec.NetworkNone && c.netns == nil {
+		return fmt.Errorf("execve: network mode %v requires environment with private network", mode)
+	}
+	return nil
+}
+
+// ProbePort checks whether the TCP port is listened inside the private network
+// namespace without connecting to it
+func (c *environ) ProbePort(port int) (bool, error) {
+	if c.netns == nil {
+		return false, fmt.Errorf("probe port: environment without private network")
+	}
+	var listened bool
+	err := inNetns(func() error {
+		return unix.Setns(int(c.netns.Fd()), unix.CLONE_NEWNET)
+	}, func() error {
+		for _, p := range []string{"/proc/thread-self/net/tcp", "/proc/thread-self/net/tcp6"} {
+			b, err := os.ReadFile(p)
+			if err != nil {
+				continue
+			}
+			if tcpListened(b, port) {
+				listened = true
+				return nil
+			}
+		}
+		return nil
+	})
+	return listened, err
+}
+
+// tcpListened parses /proc/net/tcp format and checks whether port is listened
+func tcpListened(b []byte, port int) bool {
+	suffix := fmt.Sprintf(":%04X", port)
+	for _, l := range bytes.Split(b, []byte{'\n'}) {
+		// sl local_address rem_address st ...
+		f := bytes.Fields(l)
+		if len(f) < 4 || !bytes.HasSuffix(f[1], []byte(suffix)) {
+			continue
+		}
+		if string(f[3]) == tcpListen {
+			return true
+		}
+	}
+	return false
+}
+
+// inNetns runs f on a dedicated locked thread switched into a network
+// namespace by enter and switches back after f returns. If it failed to switch
+// back, the goroutine exits with the thread locked so that the runtime
+// terminates the thread instead of reusing it in the wrong namespace.
+func inNetns(enter func() error, f func() error) error {
+	errCh := make(chan error, 1)
+	go func() {
+		runtime.LockOSThread()
+		orig, err := os.Open(threadNetnsPath)
+		if err != nil {
+			runtime.UnlockOSThread()
+			errCh <- err
+			return
+		}
+		defer orig.Close()
+
+		if err := enter(); err != nil {
+			runtime.UnlockOSThread()
+			errCh <- err
+			return
+		}
+		err = f()
+		if err := unix.Setns(int(orig.Fd()), unix.CLONE_NEWNET); err != nil {
+			// exit without unlock to discard the thread
+			errCh <- fmt.Errorf("restore network namespace: %w", err)
+			return
+		}
+		runtime.UnlockOSThread()
+		errCh <- err
+	}()
+	return <-errCh
+}
+
+// setLoopbackUp sets the loopback interface of the current network namespace up
+func setLoopbackUp() error {
+	fd, err := unix.Socket(unix.AF_INET, unix.SOCK_DGRAM|unix.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return err
+	}
+	defer unix.Close(fd)
+
+	ifr, err := unix.NewIfreq("lo")
+	if err != nil {
+		return err
+	}
+	if err := unix.IoctlIfreq(fd, unix.SIOCGIFFLAGS, ifr); err != nil {
+		return err
+	}
+	ifr.SetUint16(ifr.Uint16() | unix.IFF_UP)
+	return unix.IoctlIfreq(fd, unix.SIOCSIFFLAGS, ifr)
+}
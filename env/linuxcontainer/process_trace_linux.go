@@ -0,0 +1,145 @@
+package linuxcontainer
+
+import (
+	"bytes"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/criyle/go-judge/envexec"
+	"golang.org/x/sys/unix"
+)
+
+// maxTraceProcesses limits the number of recorded processes
+const maxTraceProcesses = 1024
+
+// processRecorder records processes created by the tracees through ptrace events
+type processRecorder struct {
+	start   time.Time
+	procs   map[int]*envexec.ProcessInfo // host pid to record
+	threads map[int]int                  // host tid to thread group leader
+	order   []*envexec.ProcessInfo
+}
+
+func newProcessRecorder(start time.Time) *processRecorder {
+	return &processRecorder{
+		start:   start,
+		procs:   make(map[int]*envexec.ProcessInfo),
+		threads: make(map[int]int),
+	}
+}
+
+// get returns the record of process pid and creates it on the first sight.
+// Events of a new child may arrive before the fork event of its parent, so
+// the parent is filled by fork. Returns nil for threads.
+func (r *processRecorder) get(pid int) *envexec.ProcessInfo {
+	if p, ok := r.procs[pid]; ok {
+		return p
+	}
+	if _, ok := r.threads[pid]; ok {
+		return nil
+	}
+	tgid, nspid := readProcStatus(pid)
+	if tgid != pid {
+		r.threads[pid] = tgid
+		return nil
+	}
+	if len(r.order) >= maxTraceProcesses {
+		return nil
+	}
+	p := &envexec.ProcessInfo{
+		Pid:   nspid,
+		Start: time.Since(r.start),
+	}
+	r.procs[pid] = p
+	r.order = append(r.order, p)
+	return p
+}
+
+// event handles fork / vfork / clone / exec ptrace event stop of pid
+func (r *processRecorder) event(pid, event int) {
+	switch event {
+	case unix.PTRACE_EVENT_FORK, unix.PTRACE_EVENT_VFORK, unix.PTRACE_EVENT_CLONE:
+		msg, err := unix.PtraceGetEventMsg(pid)
+		if err != nil {
+			return
+		}
+		c := r.get(int(msg))
+		if c == nil {
+			return
+		}
+		if l, ok := r.threads[pid]; ok {
+			pid = l
+		}
+		if p := r.get(pid); p != nil {
+			c.PPid = p.Pid
+			// the child runs the same program until execve
+			if c.Args == nil {
+				c.Args = p.Args
+			}
+		}
+
+	case unix.PTRACE_EVENT_EXEC:
+		if p := r.get(pid); p != nil {
+			p.Args = readCmdline(pid)
+		}
+	}
+}
+
+// exit records exit status of pid
+func (r *processRecorder) exit(pid int, ws unix.WaitStatus) {
+	p := r.get(pid)
+	if p == nil {
+		return
+	}
+	p.End = time.Since(r.start)
+	if ws.Signaled() {
+		p.Signal = unix.SignalName(ws.Signal())
+		p.ExitStatus = int(ws.Signal())
+	} else {
+		p.ExitStatus = ws.ExitStatus()
+	}
+}
+
+// processes returns the records in creation order
+func (r *processRecorder) processes() []envexec.ProcessInfo {
+	rt := make([]envexec.ProcessInfo, 0, len(r.order))
+	for _, p := range r.order {
+		rt = append(rt, *p)
+	}
+	return rt
+}
+
+func readCmdline(pid int) []string {
+	b, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/cmdline")
+	if err != nil || len(b) == 0 {
+		return nil
+	}
+	return strings.Split(string(bytes.TrimSuffix(b, []byte{0})), "\x00")
+}
+
+// readProcStatus returns the thread group id and the pid inside the container
+// pid namespace (last of NSpid) of pid
+func readProcStatus(pid int) (tgid, nspid int) {
+	tgid, nspid = pid, pid
+	b, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/status")
+	if err != nil {
+		return
+	}
+	for _, l := range bytes.Split(b, []byte{'\n'}) {
+		if v, ok := bytes.CutPrefix(l, []byte("Tgid:")); ok {
+			if n, err := strconv.Atoi(string(bytes.TrimSpace(v))); err == nil {
+				tgid = n
+			}
+		}
+		if v, ok := bytes.CutPrefix(l, []byte("NSpid:")); ok {
+			if f := bytes.Fields(v); len(f) > 0 {
+				if n, err := strconv.Atoi(string(f[len(f)-1])); err == nil {
+					nspid = n
+				}
+			}
+		}
+	}
+	return
+}
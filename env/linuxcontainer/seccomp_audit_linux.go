@@ -0,0 +1,210 @@
+package linuxcontainer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/elastic/go-seccomp-bpf/arch"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	kmsgPath          = "/dev/kmsg"
+	kmsgDrainInterval = 100 * time.Millisecond
+
+	// auditTypeSeccomp is the audit record type emitted by SECCOMP_RET_LOG
+	auditTypeSeccomp = "type=1326"
+)
+
+var _ SyscallAuditor = &KmsgAuditor{}
+
+// KmsgAuditor collects syscalls logged by the seccomp audit filter from the
+// kernel log. Records are attributed to a run by the pid namespace of the
+// logging process, so it is best-effort for processes that exit before their
+// records were read.
+type KmsgAuditor struct {
+	mu       sync.Mutex
+	fd       int
+	buf      []byte
+	watchers map[string]*auditWatch // pid namespace -> watch
+	pids     map[int]*auditWatch    // cached attribution of pid
+}
+
+type auditWatch struct {
+	ns       string
+	syscalls map[string]struct{}
+}
+
+// NewKmsgAuditor opens the kernel log and starts to collect seccomp records
+func NewKmsgAuditor() (*KmsgAuditor, error) {
+	fd, err := unix.Open(kmsgPath, unix.O_RDONLY|unix.O_NONBLOCK|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, &os.PathError{Op: "open", Path: kmsgPath, Err: err}
+	}
+	// skip records produced before start
+	if _, err := unix.Seek(fd, 0, unix.SEEK_END); err != nil {
+		unix.Close(fd)
+		return nil, &os.PathError{Op: "seek", Path: kmsgPath, Err: err}
+	}
+	a := &KmsgAuditor{
+		fd:       fd,
+		buf:      make([]byte, 8192),
+		watchers: make(map[string]*auditWatch),
+		pids:     make(map[int]*auditWatch),
+	}
+	go a.loop()
+	return a, nil
+}
+
+// Watch starts to collect syscalls for all processes within the pid namespace
+// of pid. The returned function stops the collection and returns the syscall
+// names in sorted order.
+func (a *KmsgAuditor) Watch(pid int) (func() []string, error) {
+	ns, err := pidNamespace(pid)
+	if err != nil {
+		return nil, fmt.Errorf("seccomp audit: %w", err)
+	}
+	w := &auditWatch{ns: ns, syscalls: make(map[string]struct{})}
+
+	a.mu.Lock()
+	a.watchers[ns] = w
+	a.pids[pid] = w
+	a.mu.Unlock()
+
+	return func() []string {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+
+		a.drain()
+		delete(a.watchers, ns)
+		for p, pw := range a.pids {
+			if pw == w {
+				delete(a.pids, p)
+			}
+		}
+		rt := make([]string, 0, len(w.syscalls))
+		for s := range w.syscalls {
+			rt = append(rt, s)
+		}
+		slices.Sort(rt)
+		return rt
+	}, nil
+}
+
+func (a *KmsgAuditor) loop() {
+	ticker := time.NewTicker(kmsgDrainInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		a.mu.Lock()
+		a.drain()
+		a.mu.Unlock()
+	}
+}
+
+// drain reads all available records, must be called with lock held
+func (a *KmsgAuditor) drain() {
+	for {
+		n, err := unix.Read(a.fd, a.buf)
+		switch {
+		case errors.Is(err, unix.EAGAIN):
+			return
+		case errors.Is(err, unix.EPIPE):
+			// records overwritten before read, continue with the next one
+			continue
+		case err != nil || n <= 0:
+			return
+		}
+		if len(a.watchers) == 0 {
+			continue
+		}
+		a.handleRecord(a.buf[:n])
+	}
+}
+
+func (a *KmsgAuditor) handleRecord(r []byte) {
+	// format: <prefix>;audit: type=1326 audit(...): ... pid=1 comm="a" ... arch=c000003e syscall=0 ...
+	if _, msg, ok := bytes.Cut(r, []byte{';'}); ok {
+		r = msg
+	}
+	if !bytes.Contains(r, []byte(auditTypeSeccomp)) {
+		return
+	}
+	pid, err := strconv.Atoi(auditField(r, "pid"))
+	if err != nil {
+		return
+	}
+	nr, err := strconv.Atoi(auditField(r, "syscall"))
+	if err != nil {
+		return
+	}
+	w, ok := a.pids[pid]
+	if !ok {
+		ns, err := pidNamespace(pid)
+		if err != nil {
+			return
+		}
+		if w, ok = a.watchers[ns]; !ok {
+			return
+		}
+		a.pids[pid] = w
+	}
+	w.syscalls[syscallName(auditField(r, "arch"), nr)] = struct{}{}
+}
+
+func auditField(r []byte, key string) string {
+	for _, f := range bytes.Fields(r) {
+		if k, v, ok := bytes.Cut(f, []byte{'='}); ok && string(k) == key {
+			return string(v)
+		}
+	}
+	return ""
+}
+
+var auditArches = []*arch.Info{arch.X86_64, arch.I386, arch.AARCH64, arch.ARM}
+
+func syscallName(auditArch string, nr int) string {
+	id, err := strconv.ParseUint(auditArch, 16, 32)
+	if err == nil {
+		for _, a := range auditArches {
+			if uint64(a.ID) != id {
+				continue
+			}
+			if n, ok := a.SyscallNumbers[nr]; ok {
+				return n
+			}
+		}
+	}
+	return strconv.Itoa(nr)
+}
+
+func pidNamespace(pid int) (string, error) {
+	return os.Readlink("/proc/" + strconv.Itoa(pid) + "/ns/pid")
+}
+
+// auditCollector holds the seccomp audit watch of a single execve
+type auditCollector struct {
+	auditor SyscallAuditor
+	stop    func() []string
+}
+
+func (a *auditCollector) watch(pid int) error {
+	stop, err := a.auditor.Watch(pid)
+	if err != nil {
+		return err
+	}
+	a.stop = stop
+	return nil
+}
+
+func (a *auditCollector) collect() []string {
+	if a.stop == nil {
+		return nil
+	}
+	return a.stop()
+}
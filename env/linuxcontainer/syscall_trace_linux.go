@@ -0,0 +1,351 @@
+package linuxcontainer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/criyle/go-judge/envexec"
+	"github.com/elastic/go-seccomp-bpf/arch"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	traceOptions = unix.PTRACE_O_TRACESYSGOOD | unix.PTRACE_O_TRACEFORK | unix.PTRACE_O_TRACEVFORK |
+		unix.PTRACE_O_TRACECLONE | unix.PTRACE_O_TRACEEXEC | unix.PTRACE_O_EXITKILL
+
+	// traceWaitTimeout is the maximum time to wait for the remaining tracees
+	// after the process finishes before they are killed
+	traceWaitTimeout = time.Second
+
+	traceStrMax = 64
+)
+
+// syscallTracer traces syscalls of the process and its descendants through
+// ptrace and writes a strace-like log. Without log, it only stops at process
+// events to record the process tree.
+type syscallTracer struct {
+	out   *bufio.Writer
+	procs *processRecorder
+	start time.Time
+	names map[int]string
+	done  chan struct{}
+
+	// tracees is written by the tracer thread, the lock allows to kill the
+	// remaining tracees from wait
+	mu      sync.Mutex
+	tracees map[int]*traceeState
+}
+
+type traceeState struct {
+	inSyscall bool
+	nr        int
+	args      [6]uint64
+	start     time.Time
+}
+
+// startSyscallTrace attaches the tracer to pid, it returns after the attach
+// finished and the tracer runs until all tracees exited. f is nil if only
+// processes are recorded.
+func startSyscallTrace(pid int, f *os.File, limit envexec.Size, procs bool) (*syscallTracer, error) {
+	t := &syscallTracer{
+		start:   time.Now(),
+		tracees: make(map[int]*traceeState),
+		done:    make(chan struct{}),
+	}
+	if f != nil && limit > 0 {
+		t.out = bufio.NewWriter(&limitedWriter{w: f, n: int64(limit)})
+	} else if f != nil {
+		t.out = bufio.NewWriter(f)
+	}
+	if procs {
+		t.procs = newProcessRecorder(t.start)
+	}
+	if info, err := arch.GetInfo(""); err == nil {
+		t.names = info.SyscallNumbers
+	}
+	attached := make(chan error, 1)
+	go func() {
+		defer close(t.done)
+
+		// ptrace requests must come from the same thread and the thread is
+		// discarded after the goroutine exits
+		runtime.LockOSThread()
+		if err := t.attach(pid); err != nil {
+			attached <- err
+			return
+		}
+		attached <- nil
+		t.loop()
+		if t.out != nil {
+			t.out.Flush()
+		}
+	}()
+	if err := <-attached; err != nil {
+		return nil, fmt.Errorf("syscall trace: %w", err)
+	}
+	return t, nil
+}
+
+// wait waits for the tracer to finish, the tracees still running after the
+// timeout are killed so that the log is complete once it returns
+func (t *syscallTracer) wait() {
+	select {
+	case <-t.done:
+		return
+	case <-time.After(traceWaitTimeout):
+	}
+	t.mu.Lock()
+	// tracees are not reaped before the tracer sees their exit, so the pids
+	// are not reused
+	for pid := range t.tracees {
+		unix.Kill(pid, unix.SIGKILL)
+	}
+	t.mu.Unlock()
+	<-t.done
+}
+
+func (t *syscallTracer) attach(pid int) error {
+	if err := unix.PtraceSeize(pid); err != nil {
+		return fmt.Errorf("seize %d: %w", pid, err)
+	}
+	if err := unix.PtraceInterrupt(pid); err != nil {
+		return fmt.Errorf("interrupt %d: %w", pid, err)
+	}
+	var ws unix.WaitStatus
+	if _, err := unix.Wait4(pid, &ws, unix.WALL, nil); err != nil {
+		return fmt.Errorf("wait %d: %w", pid, err)
+	}
+	if err := unix.PtraceSetOptions(pid, traceOptions); err != nil {
+		return fmt.Errorf("set options %d: %w", pid, err)
+	}
+	t.mu.Lock()
+	t.tracees[pid] = &traceeState{}
+	t.mu.Unlock()
+	if t.procs != nil {
+		t.procs.get(pid)
+	}
+	return t.resume(pid, 0)
+}
+
+// resume continues the tracee to the next syscall stop or only to the next
+// event stop if syscalls are not logged
+func (t *syscallTracer) resume(pid, sig int) error {
+	if t.out == nil {
+		return unix.PtraceCont(pid, sig)
+	}
+	return unix.PtraceSyscall(pid, sig)
+}
+
+// processes returns the recorded processes after the tracer finished
+func (t *syscallTracer) processes() []envexec.ProcessInfo {
+	if t.procs == nil {
+		return nil
+	}
+	select {
+	case <-t.done:
+		return t.procs.processes()
+	default:
+		return nil
+	}
+}
+
+func (t *syscallTracer) loop() {
+	for len(t.tracees) > 0 {
+		var ws unix.WaitStatus
+		// WNOTHREAD limits the wait to the tracees of this thread
+		pid, err := unix.Wait4(-1, &ws, unix.WALL|unix.WNOTHREAD, nil)
+		if err == unix.EINTR {
+			continue
+		}
+		if err != nil {
+			return
+		}
+		t.handle(pid, ws)
+	}
+}
+
+func (t *syscallTracer) handle(pid int, ws unix.WaitStatus) {
+	s, ok := t.tracees[pid]
+	if !ok {
+		// new tracee attached by fork / clone
+		s = &traceeState{}
+		t.mu.Lock()
+		t.tracees[pid] = s
+		t.mu.Unlock()
+		if t.procs != nil {
+			t.procs.get(pid)
+		}
+	}
+	switch {
+	case ws.Exited():
+		t.printf(pid, "+++ exited with %d +++\n", ws.ExitStatus())
+		t.exited(pid, ws)
+		return
+
+	case ws.Signaled():
+		t.printf(pid, "+++ killed by %s +++\n", unix.SignalName(ws.Signal()))
+		t.exited(pid, ws)
+		return
+
+	case !ws.Stopped():
+		return
+	}
+
+	var sig int
+	switch stopSig := ws.StopSignal(); {
+	case stopSig == unix.SIGTRAP|0x80:
+		t.handleSyscall(pid, s)
+
+	case int(ws)>>16 != 0:
+		// ptrace event stop (fork / clone / exec / group stop)
+		if t.procs != nil {
+			t.procs.event(pid, int(ws)>>16)
+		}
+
+	default:
+		t.printf(pid, "--- %s ---\n", unix.SignalName(stopSig))
+		sig = int(stopSig)
+	}
+	t.resume(pid, sig)
+}
+
+func (t *syscallTracer) exited(pid int, ws unix.WaitStatus) {
+	t.mu.Lock()
+	delete(t.tracees, pid)
+	t.mu.Unlock()
+	if t.procs != nil {
+		t.procs.exit(pid, ws)
+	}
+}
+
+func (t *syscallTracer) handleSyscall(pid int, s *traceeState) {
+	regs, err := getTraceRegs(pid)
+	if err != nil {
+		return
+	}
+	if !s.inSyscall {
+		s.inSyscall = true
+		s.nr = regs.nr()
+		s.args = regs.args()
+		s.start = time.Now()
+		return
+	}
+	s.inSyscall = false
+
+	var b strings.Builder
+	b.WriteString(t.syscallName(s.nr))
+	b.WriteByte('(')
+	for i, a := range s.args {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		if traceStringArg(t.syscallName(s.nr), i) {
+			b.WriteString(strconv.Quote(readTraceeString(pid, uintptr(a))))
+		} else {
+			b.WriteString("0x" + strconv.FormatUint(a, 16))
+		}
+	}
+	b.WriteString(") = ")
+	if ret := regs.ret(); ret < 0 && ret > -4096 {
+		b.WriteString("-1 " + unix.ErrnoName(unix.Errno(-ret)))
+	} else {
+		b.WriteString(strconv.FormatInt(ret, 10))
+	}
+	fmt.Fprintf(&b, " <%.6f>\n", time.Since(s.start).Seconds())
+	t.printf(pid, "%s", b.String())
+}
+
+func (t *syscallTracer) printf(pid int, format string, v ...any) {
+	if t.out == nil {
+		return
+	}
+	fmt.Fprintf(t.out, "[pid %d] %.6f ", pid, time.Since(t.start).Seconds())
+	fmt.Fprintf(t.out, format, v...)
+}
+
+func (t *syscallTracer) syscallName(nr int) string {
+	if n, ok := t.names[nr]; ok {
+		return n
+	}
+	return "syscall_" + strconv.Itoa(nr)
+}
+
+// traceStringArgs lists the position of string arguments for common syscalls
+var traceStringArgs = map[string][]int{
+	"open":       {0},
+	"openat":     {1},
+	"creat":      {0},
+	"execve":     {0},
+	"execveat":   {1},
+	"access":     {0},
+	"faccessat":  {1},
+	"faccessat2": {1},
+	"stat":       {0},
+	"lstat":      {0},
+	"newfstatat": {1},
+	"statx":      {1},
+	"readlink":   {0},
+	"readlinkat": {1},
+	"unlink":     {0},
+	"unlinkat":   {1},
+	"mkdir":      {0},
+	"mkdirat":    {1},
+	"chdir":      {0},
+	"rename":     {0, 1},
+	"renameat":   {1, 3},
+	"renameat2":  {1, 3},
+}
+
+func traceStringArg(name string, i int) bool {
+	for _, p := range traceStringArgs[name] {
+		if p == i {
+			return true
+		}
+	}
+	return false
+}
+
+func readTraceeString(pid int, addr uintptr) string {
+	if addr == 0 {
+		return ""
+	}
+	buf := make([]byte, traceStrMax)
+	local := unix.Iovec{Base: &buf[0]}
+	local.SetLen(len(buf))
+	n, err := unix.ProcessVMReadv(pid, []unix.Iovec{local}, []unix.RemoteIovec{{Base: addr, Len: len(buf)}}, 0)
+	if err != nil || n <= 0 {
+		return ""
+	}
+	buf = buf[:n]
+	if i := strings.IndexByte(string(buf), 0); i >= 0 {
+		return string(buf[:i])
+	}
+	return string(buf) + "..."
+}
+
+// limitedWriter discards writes after n bytes with a truncation mark
+type limitedWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	if l.n <= 0 {
+		return len(p), nil
+	}
+	if int64(len(p)) > l.n {
+		l.w.Write(p[:l.n])
+		l.n = 0
+		l.w.Write([]byte("\n... trace truncated\n"))
+		return len(p), nil
+	}
+	l.n -= int64(len(p))
+	return l.w.Write(p)
+}
@@ -0,0 +1,25 @@
+package linuxcontainer
+
+import "golang.org/x/sys/unix"
+
+type traceRegs unix.PtraceRegs
+
+func getTraceRegs(pid int) (*traceRegs, error) {
+	var regs unix.PtraceRegs
+	if err := unix.PtraceGetRegs(pid, &regs); err != nil {
+		return nil, err
+	}
+	return (*traceRegs)(&regs), nil
+}
+
+func (r *traceRegs) nr() int {
+	return int(r.Orig_rax)
+}
+
+func (r *traceRegs) args() [6]uint64 {
+	return [6]uint64{r.Rdi, r.Rsi, r.Rdx, r.R10, r.R8, r.R9}
+}
+
+func (r *traceRegs) ret() int64 {
+	return int64(r.Rax)
+}
@@ -0,0 +1,28 @@
+package linuxcontainer
+
+import "golang.org/x/sys/unix"
+
+// ntPRStatus is the regset of general purpose registers
+const ntPRStatus = 1
+
+type traceRegs unix.PtraceRegsArm64
+
+func getTraceRegs(pid int) (*traceRegs, error) {
+	var regs unix.PtraceRegsArm64
+	if err := unix.PtraceGetRegSetArm64(pid, ntPRStatus, &regs); err != nil {
+		return nil, err
+	}
+	return (*traceRegs)(&regs), nil
+}
+
+func (r *traceRegs) nr() int {
+	return int(r.Regs[8])
+}
+
+func (r *traceRegs) args() [6]uint64 {
+	return [6]uint64{r.Regs[0], r.Regs[1], r.Regs[2], r.Regs[3], r.Regs[4], r.Regs[5]}
+}
+
+func (r *traceRegs) ret() int64 {
+	return int64(r.Regs[0])
+}
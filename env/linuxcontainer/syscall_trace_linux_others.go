@@ -0,0 +1,23 @@
+//go:build !amd64 && !arm64
+
+package linuxcontainer
+
+import "errors"
+
+type traceRegs struct{}
+
+func getTraceRegs(pid int) (*traceRegs, error) {
+	return nil, errors.New("syscall trace is not supported on this architecture")
+}
+
+func (r *traceRegs) nr() int {
+	return 0
+}
+
+func (r *traceRegs) args() [6]uint64 {
+	return [6]uint64{}
+}
+
+func (r *traceRegs) ret() int64 {
+	return 0
+}
@@ -2,9 +2,23 @@
 
 package env
 
-import "syscall"
+import (
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
 
 func readSeccompConf(name string) ([]syscall.SockFilter, error) {
 	_ = name
 	return nil, nil
 }
+
+// readSeccompAuditConf logs every syscall since the seccomp config is not
+// supported without seccomp build tag
+func readSeccompAuditConf(name string) ([]syscall.SockFilter, error) {
+	_ = name
+	return []syscall.SockFilter{{
+		Code: unix.BPF_RET | unix.BPF_K,
+		K:    unix.SECCOMP_RET_LOG,
+	}}, nil
+}
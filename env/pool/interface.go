@@ -1,6 +1,7 @@
 package pool
 
 import (
+	"errors"
 	"sync"
 
 	"github.com/criyle/go-judge/envexec"
@@ -19,6 +20,19 @@ type EnvBuilder interface {
 	Build() (Environment, error)
 }
 
+// NetworkEnvBuilder is implemented by EnvBuilder that is able to build
+// environments sharing a private network namespace
+type NetworkEnvBuilder interface {
+	EnvBuilder
+	NewNetwork() (NetworkBuilder, error)
+}
+
+// NetworkBuilder builds environments inside the same network namespace
+type NetworkBuilder interface {
+	EnvBuilder
+	Destroy() error
+}
+
 type pool struct {
 	builder EnvBuilder
 
@@ -70,3 +84,37 @@ func (p *pool) Destroy() {
 		e.Destroy()
 	}
 }
+
+// NewNetwork returns a pool of environments sharing a new private network
+// namespace. Environments put back are destroyed instead of reused.
+func (p *pool) NewNetwork() (worker.EnvironmentPool, error) {
+	nb, ok := p.builder.(NetworkEnvBuilder)
+	if !ok {
+		return nil, errors.New("shared network is not supported by the environment")
+	}
+	b, err := nb.NewNetwork()
+	if err != nil {
+		return nil, err
+	}
+	return &networkPool{builder: b}, nil
+}
+
+type networkPool struct {
+	builder NetworkBuilder
+}
+
+func (p *networkPool) Get() (envexec.Environment, error) {
+	return p.builder.Build()
+}
+
+func (p *networkPool) Put(env envexec.Environment) {
+	e, ok := env.(Environment)
+	if !ok {
+		panic("invalid environment put")
+	}
+	e.Destroy()
+}
+
+func (p *networkPool) Destroy() {
+	p.builder.Destroy()
+}
@@ -12,6 +12,33 @@ import (
 )
 
 func readSeccompConf(name string) ([]syscall.SockFilter, error) {
+	policy, err := readSeccompPolicy(name)
+	if err != nil || policy == nil {
+		return nil, err
+	}
+	return assembleSeccompPolicy(policy)
+}
+
+// readSeccompAuditConf creates the audit filter from the seccomp config that
+// logs every syscall not allowed by the config instead of denying it
+func readSeccompAuditConf(name string) ([]syscall.SockFilter, error) {
+	policy, err := readSeccompPolicy(name)
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil {
+		policy = &seccomp.Policy{}
+	}
+	policy.DefaultAction = seccomp.ActionLog
+	for i := range policy.Syscalls {
+		if policy.Syscalls[i].Action != seccomp.ActionAllow {
+			policy.Syscalls[i].Action = seccomp.ActionLog
+		}
+	}
+	return assembleSeccompPolicy(policy)
+}
+
+func readSeccompPolicy(name string) (*seccomp.Policy, error) {
 	conf, err := yaml.NewConfigWithFile(name)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -24,6 +51,10 @@ func readSeccompConf(name string) ([]syscall.SockFilter, error) {
 	if err := conf.Unpack(&policy); err != nil {
 		return nil, err
 	}
+	return &policy, nil
+}
+
+func assembleSeccompPolicy(policy *seccomp.Policy) ([]syscall.SockFilter, error) {
 	inst, err := policy.Assemble()
 	if err != nil {
 		return nil, err
@@ -0,0 +1,187 @@
+package envexec
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// ArchiveLimit defines the limits to extract an archive
+type ArchiveLimit struct {
+	MaxEntries   int   // max number of entries, 0 for unlimited
+	MaxEntrySize int64 // max size of each file, 0 for unlimited
+	MaxTotalSize int64 // max total size of files, 0 for unlimited
+}
+
+// ArchiveTarget creates the extracted entries by the path relative to its
+// root, Environment extracts into the container
+type ArchiveTarget interface {
+	Open(path string, flags int, perm os.FileMode) (*os.File, error)
+	MkdirAll(path string, perm os.FileMode) error
+}
+
+// ExtractArchive extracts the tar (optionally gzip compressed) or zip archive
+// into dir of the target. Only regular files and directories are extracted,
+// entries outside dir, links and special files are rejected.
+func ExtractArchive(t ArchiveTarget, dir string, r io.ReaderAt, size int64, l ArchiveLimit) error {
+	x := &archiveExtractor{target: t, dir: dir, limit: l}
+	if err := t.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	var magic [2]byte
+	n, _ := r.ReadAt(magic[:], 0)
+	switch {
+	case n == 2 && magic == [2]byte{'P', 'K'}:
+		zr, err := zip.NewReader(r, size)
+		if err != nil && !errors.Is(err, zip.ErrInsecurePath) {
+			return err
+		}
+		return x.zip(zr)
+
+	case n == 2 && magic == [2]byte{0x1f, 0x8b}:
+		gr, err := gzip.NewReader(io.NewSectionReader(r, 0, size))
+		if err != nil {
+			return err
+		}
+		defer gr.Close()
+		return x.tar(gr)
+
+	default:
+		return x.tar(io.NewSectionReader(r, 0, size))
+	}
+}
+
+type archiveExtractor struct {
+	target ArchiveTarget
+	dir    string
+	limit  ArchiveLimit
+	total  int64 // total size of files extracted
+}
+
+func (x *archiveExtractor) tar(r io.Reader) error {
+	tr := tar.NewReader(r)
+	for count := 0; ; count++ {
+		h, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil && !errors.Is(err, tar.ErrInsecurePath) {
+			return err
+		}
+		if x.limit.MaxEntries > 0 && count >= x.limit.MaxEntries {
+			return fmt.Errorf("number of entries exceeds limit (%d)", x.limit.MaxEntries)
+		}
+		switch h.Typeflag {
+		case tar.TypeDir:
+			err = x.mkdir(h.Name)
+		case tar.TypeReg:
+			err = x.create(h.Name, h.FileInfo().Mode(), h.Size, tr)
+		default:
+			err = fmt.Errorf("%q: unsupported entry type %q", h.Name, h.Typeflag)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (x *archiveExtractor) zip(zr *zip.Reader) error {
+	if x.limit.MaxEntries > 0 && len(zr.File) > x.limit.MaxEntries {
+		return fmt.Errorf("number of entries exceeds limit (%d)", x.limit.MaxEntries)
+	}
+	for _, f := range zr.File {
+		mode := f.Mode()
+		switch {
+		case mode.IsDir():
+			if err := x.mkdir(f.Name); err != nil {
+				return err
+			}
+		case mode.IsRegular():
+			r, err := f.Open()
+			if err != nil {
+				return err
+			}
+			err = x.create(f.Name, mode, int64(f.UncompressedSize64), r)
+			r.Close()
+			if err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("%q: unsupported entry type %v", f.Name, mode.Type())
+		}
+	}
+	return nil
+}
+
+// path returns the path of the archive entry in dir, entries outside dir are
+// rejected
+func (x *archiveExtractor) path(name string) (string, error) {
+	name = filepath.FromSlash(name)
+	if !filepath.IsLocal(name) {
+		return "", fmt.Errorf("%q: path is outside the extract dir", name)
+	}
+	return filepath.Join(x.dir, name), nil
+}
+
+func (x *archiveExtractor) mkdir(name string) error {
+	p, err := x.path(name)
+	if err != nil {
+		return err
+	}
+	return x.target.MkdirAll(p, 0o755)
+}
+
+func (x *archiveExtractor) create(name string, mode os.FileMode, size int64, r io.Reader) error {
+	p, err := x.path(name)
+	if err != nil {
+		return err
+	}
+	limit := x.limit.MaxEntrySize
+	if limit <= 0 {
+		limit = size
+	}
+	if size > limit {
+		return fmt.Errorf("%q: size (%d) exceeds limit (%d)", name, size, limit)
+	}
+	// read at most the remaining total size to detect the excess
+	read := limit
+	maxTotal := x.limit.MaxTotalSize
+	if maxTotal > 0 {
+		if x.total+size > maxTotal {
+			return fmt.Errorf("total size exceeds limit (%d)", maxTotal)
+		}
+		read = min(read, maxTotal-x.total)
+	}
+	if err := x.target.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+	perm := os.FileMode(0o644)
+	if mode&0o111 != 0 {
+		perm = 0o755
+	}
+	f, err := x.target.Open(p, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// the size in header is not trusted
+	n, err := io.Copy(f, io.LimitReader(r, read+1))
+	x.total += n
+	if err != nil {
+		return fmt.Errorf("%q: %w", name, err)
+	}
+	if n > limit {
+		return fmt.Errorf("%q: size exceeds limit (%d)", name, limit)
+	}
+	if maxTotal > 0 && x.total > maxTotal {
+		return fmt.Errorf("total size exceeds limit (%d)", maxTotal)
+	}
+	return nil
+}
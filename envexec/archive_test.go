@@ -0,0 +1,156 @@
+package envexec
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// dirTarget extracts into the host directory
+type dirTarget string
+
+func (d dirTarget) Open(path string, flags int, perm os.FileMode) (*os.File, error) {
+	return os.OpenFile(filepath.Join(string(d), path), flags, perm)
+}
+
+func (d dirTarget) MkdirAll(path string, perm os.FileMode) error {
+	return os.MkdirAll(filepath.Join(string(d), path), perm)
+}
+
+type tarEntry struct {
+	name     string
+	typ      byte
+	content  string
+	linkname string
+}
+
+func tarArchive(t *testing.T, entries []tarEntry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, e := range entries {
+		h := &tar.Header{Name: e.name, Typeflag: e.typ, Mode: 0o644, Linkname: e.linkname}
+		if e.typ == tar.TypeReg {
+			h.Size = int64(len(e.content))
+		}
+		if err := tw.WriteHeader(h); err != nil {
+			t.Fatalf("WriteHeader: %v", err)
+		}
+		tw.Write([]byte(e.content))
+	}
+	tw.Flush()
+	return buf.Bytes()
+}
+
+func gzipArchive(t *testing.T, b []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	gw.Write(b)
+	gw.Close()
+	return buf.Bytes()
+}
+
+type zipEntry struct {
+	name    string
+	content string
+	size    uint64 // uncompressed size in header if not zero
+}
+
+func zipArchive(t *testing.T, entries []zipEntry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, e := range entries {
+		size := e.size
+		if size == 0 {
+			size = uint64(len(e.content))
+		}
+		var compressed bytes.Buffer
+		fw, _ := flate.NewWriter(&compressed, flate.DefaultCompression)
+		fw.Write([]byte(e.content))
+		fw.Close()
+		w, err := zw.CreateRaw(&zip.FileHeader{
+			Name:               e.name,
+			Method:             zip.Deflate,
+			CompressedSize64:   uint64(compressed.Len()),
+			UncompressedSize64: size,
+		})
+		if err != nil {
+			t.Fatalf("CreateRaw: %v", err)
+		}
+		w.Write(compressed.Bytes())
+	}
+	zw.Close()
+	return buf.Bytes()
+}
+
+func TestExtractArchive(t *testing.T) {
+	valid := []tarEntry{
+		{name: "d/", typ: tar.TypeDir},
+		{name: "d/a.txt", typ: tar.TypeReg, content: "aaa"},
+		{name: "b.txt", typ: tar.TypeReg, content: "bb"},
+	}
+	tests := []struct {
+		name    string
+		archive []byte
+		limit   ArchiveLimit
+		err     string // expected error substring, empty for success
+	}{
+		{"tar", tarArchive(t, valid), ArchiveLimit{}, ""},
+		{"tar.gz", gzipArchive(t, tarArchive(t, valid)), ArchiveLimit{}, ""},
+		{"zip", zipArchive(t, []zipEntry{{name: "d/a.txt", content: "aaa"}, {name: "b.txt", content: "bb"}}), ArchiveLimit{}, ""},
+		{"within limits", tarArchive(t, valid), ArchiveLimit{MaxEntries: 3, MaxEntrySize: 3, MaxTotalSize: 5}, ""},
+
+		{"tar parent", tarArchive(t, []tarEntry{{name: "../a.txt", typ: tar.TypeReg, content: "a"}}), ArchiveLimit{}, "outside"},
+		{"tar nested parent", tarArchive(t, []tarEntry{{name: "d/../../a.txt", typ: tar.TypeReg, content: "a"}}), ArchiveLimit{}, "outside"},
+		{"tar absolute", tarArchive(t, []tarEntry{{name: "/tmp/a.txt", typ: tar.TypeReg, content: "a"}}), ArchiveLimit{}, "outside"},
+		{"zip parent", zipArchive(t, []zipEntry{{name: "../a.txt", content: "a"}}), ArchiveLimit{}, "outside"},
+		{"tar symlink", tarArchive(t, []tarEntry{{name: "l", typ: tar.TypeSymlink, linkname: "/etc/passwd"}}), ArchiveLimit{}, "unsupported"},
+		{"tar hard link", tarArchive(t, []tarEntry{{name: "l", typ: tar.TypeLink, linkname: "b.txt"}}), ArchiveLimit{}, "unsupported"},
+		{"tar char device", tarArchive(t, []tarEntry{{name: "null", typ: tar.TypeChar}}), ArchiveLimit{}, "unsupported"},
+		{"tar fifo", tarArchive(t, []tarEntry{{name: "fifo", typ: tar.TypeFifo}}), ArchiveLimit{}, "unsupported"},
+
+		{"tar entries", tarArchive(t, valid), ArchiveLimit{MaxEntries: 2}, "number of entries"},
+		{"zip entries", zipArchive(t, []zipEntry{{name: "a", content: "a"}, {name: "b", content: "b"}}), ArchiveLimit{MaxEntries: 1}, "number of entries"},
+		{"tar entry size", tarArchive(t, valid), ArchiveLimit{MaxEntrySize: 2}, "exceeds limit"},
+		{"tar total size", tarArchive(t, valid), ArchiveLimit{MaxTotalSize: 4}, "total size"},
+		{"zip total size", zipArchive(t, []zipEntry{{name: "a", content: "aaa"}, {name: "b", content: "bbb"}}), ArchiveLimit{MaxTotalSize: 5}, "total size"},
+
+		// sizes in the headers are not trusted
+		{"zip size lies", zipArchive(t, []zipEntry{{name: "a", content: "aaaaaaaaaa", size: 1}}), ArchiveLimit{}, "not a valid zip"},
+		{"zip size lies within entry limit", zipArchive(t, []zipEntry{{name: "a", content: "aaaaaaaaaa", size: 5}}), ArchiveLimit{MaxEntrySize: 5}, "not a valid zip"},
+		{"zip size lies within total limit", zipArchive(t, []zipEntry{{name: "a", content: "aaaaaaaaaa", size: 5}}), ArchiveLimit{MaxTotalSize: 5}, "not a valid zip"},
+		{"tar truncated", tarArchive(t, valid)[:1024+2], ArchiveLimit{}, "EOF"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			err := ExtractArchive(dirTarget(dir), "x", bytes.NewReader(tc.archive), int64(len(tc.archive)), tc.limit)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("expected error containing %q, got %v", tc.err, err)
+				}
+				if _, err := os.Stat(filepath.Join(filepath.Dir(dir), "a.txt")); err == nil {
+					t.Fatalf("expected no file outside the extract dir")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExtractArchive: %v", err)
+			}
+			for name, content := range map[string]string{"d/a.txt": "aaa", "b.txt": "bb"} {
+				b, err := os.ReadFile(filepath.Join(dir, "x", name))
+				if err != nil || string(b) != content {
+					t.Fatalf("%s: expected %q, got %q, %v", name, content, b, err)
+				}
+			}
+		})
+	}
+}
@@ -36,6 +36,7 @@ type Cmd struct {
 	// resource limits
 	TimeLimit        time.Duration
 	MemoryLimit      Size
+	MemoryHigh       Size // throttle and reclaim above it rather than kill
 	StackLimit       Size
 	ExtraMemoryLimit Size
 	OutputLimit      Size
@@ -43,6 +44,12 @@ type Cmd struct {
 	OpenFileLimit    uint64
 	CPURateLimit     uint64
 	CPUSetLimit      string
+	DiskLimit        Size   // work dir size limit
+	FileCountLimit   uint64 // work dir file count limit
+	IOReadBps        Size   // block device read bandwidth limit
+	IOWriteBps       Size   // block device write bandwidth limit
+	IOReadIOPS       uint64 // block device read IOPS limit
+	IOWriteIOPS      uint64 // block device write IOPS limit
 
 	// Waiter is called after cmd starts and it should return
 	// once time limit exceeded.
@@ -56,15 +63,55 @@ type Cmd struct {
 	// CopyOutDir specifies a dir to dump all /w content
 	CopyOutDir string
 
+	// CopyOutBundle specifies a dir in /w to copy out as a directory created
+	// by the store file, the total size of files is limited by CopyOutMax
+	CopyOutBundle string
+
+	// CopyOutMaxEntries limits the number of entries copied out by
+	// CopyOutBundle, a glob pattern or a directory archive, 0 for unlimited
+	CopyOutMaxEntries int
+
 	// additional memory option
 	AddressSpaceLimit bool
 	DataSegmentLimit  bool
+
+	// SeccompAudit logs syscalls instead of killing and collects them
+	SeccompAudit bool
+
+	// Network specifies the network access
+	Network NetworkMode
+
+	// SyscallTrace specifies the collected file name of the ptrace syscall log
+	SyscallTrace    string
+	SyscallTraceMax Size // syscall log size limit
+
+	// TraceProcess records the processes created during the run
+	TraceProcess bool
+
+	// Perf counts perf events (task-clock, instructions, cycles)
+	Perf bool
+
+	// CoreDump collects the core file and crash details if the process is signalled
+	CoreDump    bool
+	CoreDumpMax Size // core file size limit
+
+	// Service marks the cmd as a service in group, nil for normal cmd
+	Service *Service
+}
+
+// Service defines the readiness probe of a service cmd. The service starts
+// before other cmds in the group and is killed after all of them finished
+type Service struct {
+	Port    int           // Port is the TCP port to be listened inside the network
+	File    string        // File is the file to be created in the work dir
+	Timeout time.Duration // Timeout is the maximum time to wait until ready
 }
 
 // CmdCopyOutFile defines the file to be copy out after cmd execution
 type CmdCopyOutFile struct {
-	Name     string // Name is the file out to copyOut
+	Name     string // Name is the file out to copyOut, or glob pattern matching files
 	Optional bool   // Optional ignores the file if not exists
+	Archive  bool   // Archive copies out the directory as a tar archive
 }
 
 // Result defines the running result for single Cmd
@@ -80,6 +127,23 @@ type Result struct {
 	Memory   Size   // byte
 	ProcPeak uint64 // maximum processes ever running
 
+	DiskUsage Size // work dir disk usage after exec
+	IORead    Size // bytes read from block devices
+	IOWrite   Size // bytes written to block devices
+
+	MemoryHighEvents uint64 // times throttled by memory high
+
+	Perf *PerfStat // perf counters if enabled
+
+	// Syscalls stores syscalls used in seccomp audit mode
+	Syscalls []string
+
+	// Crash stores the crash details in core dump mode
+	Crash *Crash
+
+	// Processes stores the processes created in trace process mode
+	Processes []ProcessInfo
+
 	// Files stores copy out files
 	Files map[string]*os.File
 
@@ -1,5 +1,10 @@
 package envexec
 
+import "time"
+
 const (
 	defaultExtraMemoryLimit = Size(16 << 10) // 16k more memory
+
+	defaultServiceTimeout = 10 * time.Second      // service readiness timeout
+	serviceProbeInterval  = 10 * time.Millisecond // service readiness probe interval
 )
@@ -0,0 +1,113 @@
+package envexec
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+const (
+	// coreAnalyzeTimeout is the time budget to symbolize the stack, frames
+	// after the budget are reported without symbols
+	coreAnalyzeTimeout = time.Second
+
+	// maxCoreBinaries is the max number of mapped binaries to symbolize
+	maxCoreBinaries = 8
+)
+
+// Crash defines the details of a process killed by signal
+type Crash struct {
+	Signal string   // Signal is the signal name (e.g. SIGSEGV)
+	Addr   uint64   // Addr is the faulting address from siginfo
+	Stack  []string // Stack is the best-effort symbolized stack of the crashing thread
+	Core   *os.File // Core is the core file collected from the work dir
+}
+
+// collectCrash collects the core file (core.<pid>) dumped into the work dir
+// after start and analyzes it. Failures to analyze the core are ignored since
+// the stack is best-effort. The core file is written by the program, so the
+// content is not trusted.
+func collectCrash(m Environment, pid, sig int, start time.Time, limit Size, newStoreFile NewStoreFile) (*Crash, error) {
+	crash := &Crash{Signal: signalName(sig)}
+	if pid <= 0 {
+		return crash, nil
+	}
+
+	name := "core." + strconv.Itoa(pid)
+	cf, err := m.Open(name, coreOpenFlags, 0)
+	if os.IsNotExist(err) {
+		return crash, nil
+	}
+	if err != nil {
+		return crash, fmt.Errorf("core dump: open %q: %w", name, err)
+	}
+	defer cf.Close()
+
+	stat, err := cf.Stat()
+	if err != nil {
+		return crash, fmt.Errorf("core dump: stat %q: %w", name, err)
+	}
+	if !stat.Mode().IsRegular() || stat.ModTime().Before(start) {
+		return crash, nil
+	}
+	if limit > 0 && stat.Size() > int64(limit) {
+		return crash, fmt.Errorf("core dump: %q size (%d) exceeds limit (%d)", name, stat.Size(), limit)
+	}
+
+	core, err := newStoreFile()
+	if err != nil {
+		return crash, fmt.Errorf("core dump: create store file: %w", err)
+	}
+	// Ensure not copy over file size
+	if _, err := core.ReadFrom(io.LimitReader(cf, stat.Size())); err != nil {
+		core.Close()
+		return crash, fmt.Errorf("core dump: copy %q: %w", name, err)
+	}
+	crash.Core = core
+
+	crash.Addr, crash.Stack = analyzeCore(core, func(p string) (*os.File, error) {
+		return openCoreBinary(m, p, limit)
+	})
+	return crash, nil
+}
+
+// analyzeCore returns the faulting address and stack of the core file, it
+// recovers from panics of the parsers on malformed input
+func analyzeCore(core io.ReaderAt, open func(string) (*os.File, error)) (addr uint64, stack []string) {
+	defer func() {
+		if recover() != nil {
+			addr, stack = 0, nil
+		}
+	}()
+	info, err := parseCore(core)
+	if err != nil {
+		return 0, nil
+	}
+	return info.addr, info.symbolize(open, time.Now().Add(coreAnalyzeTimeout))
+}
+
+// openCoreBinary opens the mapped binary by its name in the top level of the
+// work dir for symbolization since the mapped path is not trusted
+func openCoreBinary(m Environment, p string, limit Size) (*os.File, error) {
+	name := filepath.Base(p)
+	if !filepath.IsLocal(name) {
+		return nil, fmt.Errorf("core dump: invalid mapped file %q", p)
+	}
+	f, err := m.Open(name, coreOpenFlags, 0)
+	if err != nil {
+		return nil, err
+	}
+	stat, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if !stat.Mode().IsRegular() || (limit > 0 && stat.Size() > int64(limit)) {
+		f.Close()
+		return nil, fmt.Errorf("core dump: %q is not a regular file within limit", p)
+	}
+	return f, nil
+}
@@ -0,0 +1,304 @@
+package envexec
+
+import (
+	"bytes"
+	"debug/dwarf"
+	"debug/elf"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+)
+
+const (
+	// note types in ELF core file
+	ntPrstatus = 1
+	ntSiginfo  = 0x53494749
+	ntFile     = 0x46494c45
+
+	// offset of pr_reg in 64-bit elf_prstatus
+	prstatusRegOffset = 112
+
+	maxStackDepth = 64
+)
+
+// coreInfo stores the details of the crashing thread parsed from core file
+type coreInfo struct {
+	f     *elf.File
+	addr  uint64 // si_addr
+	pc    uint64
+	fp    uint64
+	files []coreMapping
+}
+
+// coreMapping is the file backed mapping from NT_FILE note
+type coreMapping struct {
+	start, end, offset uint64
+	path               string
+}
+
+// parseCore parses the siginfo, registers of the crashing thread (the first
+// NT_PRSTATUS) and the file mappings of a 64-bit core file
+func parseCore(r io.ReaderAt) (*coreInfo, error) {
+	f, err := elf.NewFile(r)
+	if err != nil {
+		return nil, err
+	}
+	if f.Type != elf.ET_CORE || f.Class != elf.ELFCLASS64 {
+		return nil, fmt.Errorf("core dump: not a 64-bit core file")
+	}
+	info := &coreInfo{f: f}
+	var prstatus []byte
+	for _, p := range f.Progs {
+		if p.Type != elf.PT_NOTE {
+			continue
+		}
+		b, err := io.ReadAll(p.Open())
+		if err != nil {
+			return nil, err
+		}
+		for len(b) >= 12 {
+			namesz := align4(f.ByteOrder.Uint32(b[0:]))
+			descsz := f.ByteOrder.Uint32(b[4:])
+			typ := f.ByteOrder.Uint32(b[8:])
+			b = b[12:]
+			if uint64(len(b)) < namesz+align4(descsz) {
+				break
+			}
+			desc := b[namesz : namesz+uint64(descsz)]
+			b = b[namesz+align4(descsz):]
+
+			switch typ {
+			case ntPrstatus:
+				if prstatus == nil {
+					prstatus = desc
+				}
+			case ntSiginfo:
+				// si_signo, si_errno, si_code, padding, si_addr
+				if len(desc) >= 24 {
+					info.addr = f.ByteOrder.Uint64(desc[16:])
+				}
+			case ntFile:
+				info.files = parseNtFile(desc, f.ByteOrder)
+			}
+		}
+	}
+	if len(prstatus) <= prstatusRegOffset {
+		return nil, fmt.Errorf("core dump: missing prstatus")
+	}
+	regs := prstatus[prstatusRegOffset:]
+	reg := func(i int) uint64 {
+		if len(regs) < (i+1)*8 {
+			return 0
+		}
+		return f.ByteOrder.Uint64(regs[i*8:])
+	}
+	switch f.Machine {
+	case elf.EM_X86_64:
+		// user_regs_struct: r15 r14 r13 r12 rbp rbx r11 r10 r9 r8 rax rcx rdx rsi rdi orig_rax rip
+		info.pc, info.fp = reg(16), reg(4)
+	case elf.EM_AARCH64:
+		// user_pt_regs: x0 - x30, sp, pc
+		info.pc, info.fp = reg(32), reg(29)
+	default:
+		return nil, fmt.Errorf("core dump: unsupported machine %v", f.Machine)
+	}
+	return info, nil
+}
+
+// parseNtFile parses NT_FILE: count, page size, count * (start, end, page
+// offset) and count null terminated file names
+func parseNtFile(b []byte, bo binary.ByteOrder) []coreMapping {
+	if len(b) < 16 {
+		return nil
+	}
+	count, pageSize := bo.Uint64(b), bo.Uint64(b[8:])
+	b = b[16:]
+	if count > uint64(len(b))/24 {
+		return nil
+	}
+	m := make([]coreMapping, count)
+	for i := range m {
+		m[i] = coreMapping{
+			start:  bo.Uint64(b[i*24:]),
+			end:    bo.Uint64(b[i*24+8:]),
+			offset: bo.Uint64(b[i*24+16:]) * pageSize,
+		}
+	}
+	names := b[count*24:]
+	for i := range m {
+		n, rest, _ := bytes.Cut(names, []byte{0})
+		m[i].path, names = string(n), rest
+	}
+	return m
+}
+
+// readUint64 reads memory of the crashed process from core file
+func (c *coreInfo) readUint64(addr uint64) (uint64, bool) {
+	for _, p := range c.f.Progs {
+		if p.Type != elf.PT_LOAD || addr < p.Vaddr || addr+8 > p.Vaddr+p.Filesz {
+			continue
+		}
+		var b [8]byte
+		if _, err := p.ReadAt(b[:], int64(addr-p.Vaddr)); err != nil {
+			return 0, false
+		}
+		return c.f.ByteOrder.Uint64(b[:]), true
+	}
+	return 0, false
+}
+
+// stack walks the frame pointer chain from the crashing pc, it only works for
+// programs compiled with frame pointers
+func (c *coreInfo) stack() []uint64 {
+	pcs := []uint64{c.pc}
+	fp := c.fp
+	for len(pcs) < maxStackDepth && fp != 0 && fp%8 == 0 {
+		next, ok1 := c.readUint64(fp)
+		ret, ok2 := c.readUint64(fp + 8)
+		if !ok1 || !ok2 || ret == 0 {
+			break
+		}
+		pcs = append(pcs, ret)
+		if next <= fp {
+			break
+		}
+		fp = next
+	}
+	return pcs
+}
+
+// symbolize returns the symbolized frames of the crashing thread with the
+// mapped binaries opened by open, frames after deadline or in binaries more
+// than maxCoreBinaries are not symbolized
+func (c *coreInfo) symbolize(open func(string) (*os.File, error), deadline time.Time) []string {
+	bins := make(map[string]*symbolTable)
+	defer func() {
+		for _, b := range bins {
+			if b != nil {
+				b.close()
+			}
+		}
+	}()
+
+	var frames []string
+	for i, pc := range c.stack() {
+		// return address points to the instruction after call
+		lookup := pc
+		if i > 0 {
+			lookup--
+		}
+		m := c.mapping(lookup)
+		if m == nil || time.Now().After(deadline) {
+			frames = append(frames, fmt.Sprintf("#%d 0x%016x in ??", i, pc))
+			continue
+		}
+		b, ok := bins[m.path]
+		if !ok && len(bins) < maxCoreBinaries {
+			b = newSymbolTable(open, m.path)
+			bins[m.path] = b
+		}
+		frames = append(frames, fmt.Sprintf("#%d 0x%016x in %s", i, pc, b.describe(m, lookup)))
+	}
+	return frames
+}
+
+func (c *coreInfo) mapping(pc uint64) *coreMapping {
+	for i := range c.files {
+		if c.files[i].start <= pc && pc < c.files[i].end {
+			return &c.files[i]
+		}
+	}
+	return nil
+}
+
+// symbolTable resolves address to symbol and source line of a binary
+type symbolTable struct {
+	file  *os.File
+	f     *elf.File
+	syms  []elf.Symbol
+	dwarf *dwarf.Data
+}
+
+func newSymbolTable(open func(string) (*os.File, error), path string) *symbolTable {
+	file, err := open(path)
+	if err != nil {
+		return nil
+	}
+	f, err := elf.NewFile(file)
+	if err != nil {
+		file.Close()
+		return nil
+	}
+	syms, err := f.Symbols()
+	if err != nil {
+		syms, _ = f.DynamicSymbols()
+	}
+	funcs := syms[:0]
+	for _, s := range syms {
+		if elf.ST_TYPE(s.Info) == elf.STT_FUNC && s.Value != 0 {
+			funcs = append(funcs, s)
+		}
+	}
+	sort.Slice(funcs, func(i, j int) bool { return funcs[i].Value < funcs[j].Value })
+	d, _ := f.DWARF()
+	return &symbolTable{file: file, f: f, syms: funcs, dwarf: d}
+}
+
+func (s *symbolTable) close() {
+	s.file.Close()
+}
+
+// describe returns function and source line of pc in mapping m
+func (s *symbolTable) describe(m *coreMapping, pc uint64) string {
+	if s == nil {
+		return "?? (" + m.path + ")"
+	}
+	// convert to the virtual address in binary through file offset
+	off := pc - m.start + m.offset
+	addr, ok := uint64(0), false
+	for _, p := range s.f.Progs {
+		if p.Type == elf.PT_LOAD && p.Off <= off && off < p.Off+p.Filesz {
+			addr, ok = off-p.Off+p.Vaddr, true
+			break
+		}
+	}
+	if !ok {
+		return "?? (" + m.path + ")"
+	}
+
+	fn := "??"
+	i := sort.Search(len(s.syms), func(i int) bool { return s.syms[i].Value > addr }) - 1
+	if i >= 0 && (s.syms[i].Size == 0 || addr < s.syms[i].Value+s.syms[i].Size) {
+		fn = fmt.Sprintf("%s+0x%x", s.syms[i].Name, addr-s.syms[i].Value)
+	}
+	if file, line, ok := s.line(addr); ok {
+		return fmt.Sprintf("%s at %s:%d", fn, file, line)
+	}
+	return fn + " (" + m.path + ")"
+}
+
+func (s *symbolTable) line(addr uint64) (string, int, bool) {
+	if s.dwarf == nil {
+		return "", 0, false
+	}
+	cu, err := s.dwarf.Reader().SeekPC(addr)
+	if err != nil {
+		return "", 0, false
+	}
+	lr, err := s.dwarf.LineReader(cu)
+	if err != nil || lr == nil {
+		return "", 0, false
+	}
+	var e dwarf.LineEntry
+	if err := lr.SeekPC(addr, &e); err != nil || e.File == nil {
+		return "", 0, false
+	}
+	return e.File.Name, e.Line, true
+}
+
+func align4(n uint32) uint64 {
+	return (uint64(n) + 3) &^ 3
+}
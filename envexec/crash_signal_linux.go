@@ -0,0 +1,20 @@
+package envexec
+
+import (
+	"os"
+	"strconv"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// coreOpenFlags avoids to follow symbolic links or block on FIFOs created by
+// the program
+const coreOpenFlags = os.O_RDONLY | syscall.O_NOFOLLOW | syscall.O_NONBLOCK
+
+func signalName(sig int) string {
+	if n := unix.SignalName(syscall.Signal(sig)); n != "" {
+		return n
+	}
+	return "SIG" + strconv.Itoa(sig)
+}
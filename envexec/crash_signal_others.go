@@ -0,0 +1,14 @@
+//go:build !linux
+
+package envexec
+
+import (
+	"os"
+	"strconv"
+)
+
+const coreOpenFlags = os.O_RDONLY
+
+func signalName(sig int) string {
+	return "SIG" + strconv.Itoa(sig)
+}
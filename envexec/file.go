@@ -17,6 +17,7 @@ var (
 	_ File = &FileCollector{}
 	_ File = &FileWriter{}
 	_ File = &FileOpened{}
+	_ File = &FileArchive{}
 )
 
 // File defines interface of envexec files
@@ -193,6 +194,20 @@ func NewFileInput(p string) File {
 	return &FileInput{Path: p}
 }
 
+// FileArchive represent archive (tar, tar.gz or zip) input which will be
+// extracted into the copy in directory, only valid for copy in
+type FileArchive struct {
+	File  File
+	Limit ArchiveLimit
+}
+
+func (*FileArchive) isFile() {}
+
+// NewFileArchive creates archive input which will be extracted within limit
+func NewFileArchive(f File, l ArchiveLimit) File {
+	return &FileArchive{File: f, Limit: l}
+}
+
 // FileCollector represent pipe output which will be collected through pipe
 type FileCollector struct {
 	Name  string
@@ -0,0 +1,150 @@
+package envexec
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// copyOutBundle copies the directory tree from container into a directory
+// replacing the store file, so that it is stored as a bundle
+func copyOutBundle(
+	m Environment,
+	c *Cmd,
+	newStoreFile NewStoreFile,
+	put func(*os.File, string),
+	addError func(FileError),
+) (err error) {
+	t := ErrCopyOutOpen
+	defer func() {
+		if err != nil {
+			addError(FileError{
+				Name:    c.CopyOutBundle,
+				Type:    t,
+				Message: err.Error(),
+			})
+		}
+	}()
+
+	root, err := copyOutDirName(c.CopyOutBundle)
+	if err != nil {
+		return fmt.Errorf("copyout bundle: %w", err)
+	}
+	f, err := newStoreFile()
+	if err != nil {
+		t = ErrCopyOutCreateFile
+		return fmt.Errorf("copyout bundle: failed to create store file for %q: %w", c.CopyOutBundle, err)
+	}
+	dir := f.Name()
+	f.Close()
+	if err := os.Remove(dir); err != nil {
+		t = ErrCopyOutCreateFile
+		return fmt.Errorf("copyout bundle: %w", err)
+	}
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t = ErrCopyOutCreateFile
+		return fmt.Errorf("copyout bundle: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			os.RemoveAll(dir)
+		}
+	}()
+
+	var (
+		count int
+		total int64
+	)
+	found := root == "."
+	descend := func(p string) bool {
+		return inDir(root, p) || inDir(p, root)
+	}
+	err = walkWorkDir(m, descend, func(p string, d fs.DirEntry) error {
+		if p == root {
+			found = d.IsDir()
+			return nil
+		}
+		if !inDir(root, p) {
+			return nil
+		}
+		if count++; c.CopyOutMaxEntries > 0 && count > c.CopyOutMaxEntries {
+			t = ErrCopyOutSizeExceeded
+			return fmt.Errorf("number of entries exceeds limit (%d)", c.CopyOutMaxEntries)
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			if err := os.Mkdir(filepath.Join(dir, rel), 0o755); err != nil {
+				t = ErrCopyOutCreateFile
+				return err
+			}
+			return nil
+
+		case d.Type().IsRegular():
+			return copyOutBundleFile(m, c, p, filepath.Join(dir, rel), &total, &t)
+
+		default:
+			t = ErrCopyOutNotRegularFile
+			return fmt.Errorf("%q is not a regular file or directory: %v", p, d.Type())
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("copyout bundle: %w", err)
+	}
+	if !found {
+		return fmt.Errorf("copyout bundle: %q is not a directory", c.CopyOutBundle)
+	}
+	d, err := os.Open(dir)
+	if err != nil {
+		t = ErrCopyOutCreateFile
+		return fmt.Errorf("copyout bundle: %w", err)
+	}
+	put(d, c.CopyOutBundle)
+	return nil
+}
+
+func copyOutBundleFile(m Environment, c *Cmd, src, dst string, total *int64, t *FileErrorType) error {
+	cf, err := m.Open(src, os.O_RDONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer cf.Close()
+
+	stat, err := cf.Stat()
+	if err != nil {
+		return err
+	}
+	// check regular file in case it is replaced
+	if !stat.Mode().IsRegular() {
+		*t = ErrCopyOutNotRegularFile
+		return fmt.Errorf("%q is not a regular file: %v", src, stat.Mode())
+	}
+	s := stat.Size()
+	*total += s
+	if c.CopyOutMax > 0 && *total > int64(c.CopyOutMax) {
+		*t = ErrCopyOutSizeExceeded
+		return fmt.Errorf("total size (%d) exceeds limit (%d)", *total, c.CopyOutMax)
+	}
+	perm := os.FileMode(0o644)
+	if stat.Mode()&0o111 != 0 {
+		perm = 0o755
+	}
+	f, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_EXCL, perm)
+	if err != nil {
+		*t = ErrCopyOutCreateFile
+		return err
+	}
+	defer f.Close()
+
+	// Ensure not copy over file size
+	if _, err := f.ReadFrom(io.LimitReader(cf, s)); err != nil {
+		*t = ErrCopyOutCopyContent
+		return fmt.Errorf("failed to copy content for %q: %w", src, err)
+	}
+	return nil
+}
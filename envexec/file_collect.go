@@ -22,6 +22,12 @@ func copyOutAndCollect(m Environment, c *Cmd, ptc []pipeCollector, newStoreFile
 	put := func(f *os.File, n string) {
 		l.Lock()
 		defer l.Unlock()
+		// the same file matched by multiple copy out files
+		if _, ok := rt[n]; ok {
+			f.Close()
+			os.RemoveAll(f.Name())
+			return
+		}
 		rt[n] = f
 	}
 	addError := func(e FileError) {
@@ -34,7 +40,14 @@ func copyOutAndCollect(m Environment, c *Cmd, ptc []pipeCollector, newStoreFile
 	for _, n := range c.CopyOut {
 		n := n
 		g.Go(func() error {
-			return copyOutFile(m, c, n, newStoreFile, put, addError)
+			switch {
+			case n.Archive:
+				return copyOutArchive(m, c, n, newStoreFile, put, addError)
+			case IsGlob(n.Name):
+				return copyOutGlob(m, c, n, newStoreFile, put, addError)
+			default:
+				return copyOutFile(m, c, n, newStoreFile, put, addError)
+			}
 		})
 	}
 
@@ -46,6 +59,13 @@ func copyOutAndCollect(m Environment, c *Cmd, ptc []pipeCollector, newStoreFile
 		})
 	}
 
+	// copy out bundle
+	if c.CopyOutBundle != "" {
+		g.Go(func() error {
+			return copyOutBundle(m, c, newStoreFile, put, addError)
+		})
+	}
+
 	// copy out dir
 	if c.CopyOutDir != "" {
 		g.Go(func() error {
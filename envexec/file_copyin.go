@@ -1,6 +1,7 @@
 package envexec
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"os"
@@ -37,6 +38,11 @@ func copyIn(m Environment, copyIn map[string]File) ([]FileError, error) {
 				}
 			}()
 
+			if a, ok := f.(*FileArchive); ok {
+				t = ErrCopyInCopyContent
+				return copyInArchive(m, n, a)
+			}
+
 			// Temporary patch to support
 			temp, ok := f.(*FileInput)
 			if ok {
@@ -117,6 +123,37 @@ func copyIn(m Environment, copyIn map[string]File) ([]FileError, error) {
 	return fileError, g.Wait()
 }
 
+// copyInArchive extracts the archive into the directory n in container
+func copyInArchive(m Environment, n string, a *FileArchive) error {
+	hf, err := FileToReader(a.File)
+	if err != nil {
+		return fmt.Errorf("copyin: file to reader: %w", err)
+	}
+	defer hf.Close()
+
+	// zip requires random access
+	var (
+		r    io.ReaderAt
+		size int64
+	)
+	if f, ok := hf.(*os.File); ok {
+		if fi, err := f.Stat(); err == nil && fi.Mode().IsRegular() {
+			r, size = f, fi.Size()
+		}
+	}
+	if r == nil {
+		content, err := io.ReadAll(hf)
+		if err != nil {
+			return fmt.Errorf("copyin: read archive: %w", err)
+		}
+		r, size = bytes.NewReader(content), int64(len(content))
+	}
+	if err := ExtractArchive(m, n, r, size, a.Limit); err != nil {
+		return fmt.Errorf("copyin: extract archive to %q: %w", n, err)
+	}
+	return nil
+}
+
 func symlink(m Environment, symlinks map[string]string) (*FileError, error) {
 	for k, v := range symlinks {
 		if err := m.Symlink(v, k); err != nil {
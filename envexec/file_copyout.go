@@ -0,0 +1,302 @@
+package envexec
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// IsGlob returns whether the copy out name is a glob pattern
+func IsGlob(name string) bool {
+	return strings.ContainsAny(name, `*?[`)
+}
+
+// MatchGlob returns whether the slash separated name matches the glob
+// pattern, the pattern "**" matches zero or more directories
+func MatchGlob(pattern, name string) bool {
+	return matchGlob(strings.Split(pattern, "/"), strings.Split(name, "/"), false)
+}
+
+// Match returns whether the result file name is copied out by the copy out file
+func (f CmdCopyOutFile) Match(name string) bool {
+	if !f.Archive && IsGlob(f.Name) {
+		return MatchGlob(f.Name, name)
+	}
+	return f.Name == name
+}
+
+// matchGlob matches the pattern and name segments, if prefix is set it returns
+// whether names under the directory name could match
+func matchGlob(pattern, name []string, prefix bool) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			if prefix {
+				return true
+			}
+			for i := 0; i <= len(name); i++ {
+				if matchGlob(pattern[1:], name[i:], false) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return prefix
+		}
+		if ok, err := path.Match(pattern[0], name[0]); !ok || err != nil {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0 && !prefix
+}
+
+// inDir returns whether the path p is dir or under dir
+func inDir(dir, p string) bool {
+	return dir == "." || p == dir || strings.HasPrefix(p, dir+string(filepath.Separator))
+}
+
+// walkWorkDir calls fn for entries in the work dir in lexical order without
+// following symbolic links, directories are descended if descend returns true
+func walkWorkDir(m Environment, descend func(string) bool, fn func(string, fs.DirEntry) error) error {
+	return walkDir(m, ".", descend, fn)
+}
+
+func walkDir(m Environment, dir string, descend func(string) bool, fn func(string, fs.DirEntry) error) error {
+	d, err := m.Open(dir, os.O_RDONLY, 0)
+	if err != nil {
+		return err
+	}
+	entries, err := d.ReadDir(-1)
+	d.Close()
+	if err != nil {
+		return err
+	}
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+	for _, e := range entries {
+		p := filepath.Join(dir, e.Name())
+		if err := fn(p, e); err != nil {
+			return err
+		}
+		if e.IsDir() && descend(p) {
+			if err := walkDir(m, p, descend, fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// copyOutDirName returns the cleaned directory name in the work dir
+func copyOutDirName(name string) (string, error) {
+	dir := filepath.Clean(name)
+	if !filepath.IsLocal(dir) {
+		return "", fmt.Errorf("%q is outside the work dir", name)
+	}
+	return dir, nil
+}
+
+// copyOutGlob copies out the regular files matching the glob pattern, each
+// file is put by its path in the work dir. The number of files is limited by
+// CopyOutMaxEntries and the total size by CopyOutMax.
+func copyOutGlob(
+	m Environment,
+	c *Cmd,
+	n CmdCopyOutFile,
+	newStoreFile NewStoreFile,
+	put func(*os.File, string),
+	addError func(FileError),
+) error {
+	pattern := strings.Split(path.Clean(filepath.ToSlash(n.Name)), "/")
+	for _, p := range pattern {
+		if _, err := path.Match(p, ""); err != nil {
+			addError(FileError{Name: n.Name, Type: ErrCopyOutOpen, Message: err.Error()})
+			return fmt.Errorf("copyout: glob %q: %w", n.Name, err)
+		}
+	}
+
+	var (
+		matched []string
+		total   int64
+	)
+	t := ErrCopyOutOpen
+	descend := func(p string) bool {
+		return matchGlob(pattern, strings.Split(filepath.ToSlash(p), "/"), true)
+	}
+	err := walkWorkDir(m, descend, func(p string, d fs.DirEntry) error {
+		if !d.Type().IsRegular() || !matchGlob(pattern, strings.Split(filepath.ToSlash(p), "/"), false) {
+			return nil
+		}
+		if c.CopyOutMaxEntries > 0 && len(matched) >= c.CopyOutMaxEntries {
+			t = ErrCopyOutSizeExceeded
+			return fmt.Errorf("number of files exceeds limit (%d)", c.CopyOutMaxEntries)
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		total += fi.Size()
+		if c.CopyOutMax > 0 && total > int64(c.CopyOutMax) {
+			t = ErrCopyOutSizeExceeded
+			return fmt.Errorf("total size (%d) exceeds limit (%d)", total, c.CopyOutMax)
+		}
+		matched = append(matched, p)
+		return nil
+	})
+	if err != nil {
+		addError(FileError{Name: n.Name, Type: t, Message: err.Error()})
+		return fmt.Errorf("copyout: glob %q: %w", n.Name, err)
+	}
+	if len(matched) == 0 {
+		if n.Optional {
+			return nil
+		}
+		addError(FileError{Name: n.Name, Type: ErrCopyOutOpen, Message: "no file matches the pattern"})
+		return fmt.Errorf("copyout: glob %q: no file matches the pattern", n.Name)
+	}
+	for _, p := range matched {
+		if err := copyOutFile(m, c, CmdCopyOutFile{Name: p}, newStoreFile, put, addError); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyOutArchive copies out the directory as a tar archive, the total size of
+// files is limited by CopyOutMax and the number of entries by CopyOutMaxEntries
+func copyOutArchive(
+	m Environment,
+	c *Cmd,
+	n CmdCopyOutFile,
+	newStoreFile NewStoreFile,
+	put func(*os.File, string),
+	addError func(FileError),
+) (err error) {
+	t := ErrCopyOutOpen
+	defer func() {
+		if err != nil {
+			addError(FileError{
+				Name:    n.Name,
+				Type:    t,
+				Message: err.Error(),
+			})
+		}
+	}()
+
+	root, err := copyOutDirName(n.Name)
+	if err != nil {
+		return fmt.Errorf("copyout archive: %w", err)
+	}
+	buf, err := newStoreFile()
+	if err != nil {
+		t = ErrCopyOutCreateFile
+		return fmt.Errorf("copyout archive: failed to create store file for %q: %w", n.Name, err)
+	}
+	defer func() {
+		if err != nil {
+			buf.Close()
+			os.Remove(buf.Name())
+		}
+	}()
+
+	var (
+		count int
+		total int64
+	)
+	found := root == "."
+	tw := tar.NewWriter(buf)
+	descend := func(p string) bool {
+		return inDir(root, p) || inDir(p, root)
+	}
+	err = walkWorkDir(m, descend, func(p string, d fs.DirEntry) error {
+		if p == root {
+			found = d.IsDir()
+			return nil
+		}
+		if !inDir(root, p) {
+			return nil
+		}
+		if count++; c.CopyOutMaxEntries > 0 && count > c.CopyOutMaxEntries {
+			t = ErrCopyOutSizeExceeded
+			return fmt.Errorf("number of entries exceeds limit (%d)", c.CopyOutMaxEntries)
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		switch {
+		case d.IsDir():
+			return tw.WriteHeader(&tar.Header{Typeflag: tar.TypeDir, Name: rel + "/", Mode: 0o755})
+
+		case d.Type().IsRegular():
+			cf, err := m.Open(p, os.O_RDONLY, 0)
+			if err != nil {
+				return err
+			}
+			defer cf.Close()
+
+			stat, err := cf.Stat()
+			if err != nil {
+				return err
+			}
+			if !stat.Mode().IsRegular() {
+				t = ErrCopyOutNotRegularFile
+				return fmt.Errorf("%q is not a regular file: %v", p, stat.Mode())
+			}
+			total += stat.Size()
+			if c.CopyOutMax > 0 && total > int64(c.CopyOutMax) {
+				t = ErrCopyOutSizeExceeded
+				return fmt.Errorf("total size (%d) exceeds limit (%d)", total, c.CopyOutMax)
+			}
+			mode := int64(0o644)
+			if stat.Mode()&0o111 != 0 {
+				mode = 0o755
+			}
+			if err := tw.WriteHeader(&tar.Header{
+				Typeflag: tar.TypeReg,
+				Name:     rel,
+				Mode:     mode,
+				Size:     stat.Size(),
+				ModTime:  stat.ModTime(),
+			}); err != nil {
+				return err
+			}
+			// Ensure not copy over file size
+			if _, err := io.Copy(tw, io.LimitReader(cf, stat.Size())); err != nil {
+				t = ErrCopyOutCopyContent
+				return err
+			}
+			return nil
+
+		default:
+			t = ErrCopyOutNotRegularFile
+			return fmt.Errorf("%q is not a regular file or directory: %v", p, d.Type())
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("copyout archive: %w", err)
+	}
+	if !found {
+		if n.Optional {
+			buf.Close()
+			os.Remove(buf.Name())
+			return nil
+		}
+		return fmt.Errorf("copyout archive: %q is not a directory", n.Name)
+	}
+	if err := tw.Close(); err != nil {
+		t = ErrCopyOutCopyContent
+		return fmt.Errorf("copyout archive: %w", err)
+	}
+	put(buf, n.Name)
+	return nil
+}
@@ -0,0 +1,228 @@
+package envexec
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// dirEnv is the environment with work dir on the host, only files are supported
+type dirEnv struct {
+	dirTarget
+}
+
+func (d dirEnv) Execve(context.Context, ExecveParam) (Process, error) {
+	return nil, errors.ErrUnsupported
+}
+
+func (d dirEnv) WorkDir() *os.File {
+	f, _ := os.Open(string(d.dirTarget))
+	return f
+}
+
+func (d dirEnv) Symlink(oldName, newName string) error {
+	return os.Symlink(oldName, filepath.Join(string(d.dirTarget), newName))
+}
+
+func (d dirEnv) MkWorkDir() error {
+	return os.MkdirAll(string(d.dirTarget), 0o755)
+}
+
+func (d dirEnv) CopyDir(src, dst string) error {
+	return errors.ErrUnsupported
+}
+
+func newDirEnv(t *testing.T, files map[string]string) dirEnv {
+	t.Helper()
+	m := dirEnv{dirTarget(t.TempDir())}
+	for name, content := range files {
+		if err := m.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			t.Fatalf("MkdirAll: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(string(m.dirTarget), name), []byte(content), 0o644); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+	}
+	return m
+}
+
+type copyOutResult struct {
+	files  map[string]*os.File
+	errors []FileError
+}
+
+func (r *copyOutResult) run(t *testing.T, fn func(
+	Environment, *Cmd, CmdCopyOutFile, NewStoreFile, func(*os.File, string), func(FileError),
+) error, m Environment, c *Cmd, n CmdCopyOutFile) error {
+	t.Helper()
+	dir := t.TempDir()
+	r.files = make(map[string]*os.File)
+	t.Cleanup(func() {
+		for _, f := range r.files {
+			f.Close()
+		}
+	})
+	newStoreFile := func() (*os.File, error) {
+		return os.CreateTemp(dir, "")
+	}
+	put := func(f *os.File, name string) {
+		r.files[name] = f
+	}
+	addError := func(e FileError) {
+		r.errors = append(r.errors, e)
+	}
+	return fn(m, c, n, newStoreFile, put, addError)
+}
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern, name string
+		match         bool
+	}{
+		{"*.txt", "a.txt", true},
+		{"*.txt", "d/a.txt", false},
+		{"d/*.txt", "d/a.txt", true},
+		{"d/?.txt", "d/ab.txt", false},
+		{"**", "a", true},
+		{"**", "d/e/a", true},
+		{"**/*.txt", "a.txt", true},
+		{"**/*.txt", "d/e/a.txt", true},
+		{"**/*.txt", "d/e/a.out", false},
+		{"d/**/a.txt", "d/a.txt", true},
+		{"d/**/a.txt", "d/e/f/a.txt", true},
+		{"d/**/a.txt", "e/a.txt", false},
+		{"d/**", "d", true},
+		{"d/**", "d/e/a", true},
+		{"d/**/e/*.txt", "d/x/e/a.txt", true},
+		{"d/**/e/*.txt", "d/x/f/a.txt", false},
+		{"[ab].txt", "b.txt", true},
+		{"[ab].txt", "c.txt", false},
+	}
+	for _, tc := range tests {
+		if got := MatchGlob(tc.pattern, tc.name); got != tc.match {
+			t.Errorf("MatchGlob(%q, %q) = %v, expected %v", tc.pattern, tc.name, got, tc.match)
+		}
+	}
+}
+
+func TestCopyOutGlob(t *testing.T) {
+	files := map[string]string{
+		"a.txt":     "aa",
+		"b.out":     "b",
+		"d/c.txt":   "ccc",
+		"d/e/f.txt": "ffff",
+	}
+	tests := []struct {
+		name    string
+		pattern string
+		c       Cmd
+		files   []string
+		errType FileErrorType // expected error type, zero (copy in only) for success
+	}{
+		{"top level", "*.txt", Cmd{}, []string{"a.txt"}, 0},
+		{"recursive", "**/*.txt", Cmd{}, []string{"a.txt", "d/c.txt", "d/e/f.txt"}, 0},
+		{"within limits", "**/*.txt", Cmd{CopyOutMax: 9, CopyOutMaxEntries: 3}, []string{"a.txt", "d/c.txt", "d/e/f.txt"}, 0},
+		{"no match", "*.none", Cmd{}, nil, ErrCopyOutOpen},
+		{"bad pattern", "[", Cmd{}, nil, ErrCopyOutOpen},
+		{"entries", "**/*.txt", Cmd{CopyOutMaxEntries: 2}, nil, ErrCopyOutSizeExceeded},
+		{"total size", "**/*.txt", Cmd{CopyOutMax: 8}, nil, ErrCopyOutSizeExceeded},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := newDirEnv(t, files)
+			var r copyOutResult
+			err := r.run(t, copyOutGlob, m, &tc.c, CmdCopyOutFile{Name: tc.pattern})
+			if tc.errType != 0 {
+				if err == nil || len(r.errors) != 1 || r.errors[0].Type != tc.errType {
+					t.Fatalf("expected error type %v, got %v, %+v", tc.errType, err, r.errors)
+				}
+				if len(r.files) != 0 {
+					t.Fatalf("expected no file copied out, got %d", len(r.files))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("copyOutGlob: %v", err)
+			}
+			var names []string
+			for name, f := range r.files {
+				names = append(names, filepath.ToSlash(name))
+				b, _ := os.ReadFile(f.Name())
+				if string(b) != files[filepath.ToSlash(name)] {
+					t.Fatalf("%s: expected %q, got %q", name, files[name], b)
+				}
+			}
+			slices.Sort(names)
+			if !slices.Equal(names, tc.files) {
+				t.Fatalf("expected %v, got %v", tc.files, names)
+			}
+		})
+	}
+}
+
+func TestCopyOutArchive(t *testing.T) {
+	files := map[string]string{
+		"a.txt":     "aa",
+		"d/c.txt":   "ccc",
+		"d/e/f.txt": "ffff",
+	}
+	tests := []struct {
+		name    string
+		dir     string
+		c       Cmd
+		entries []string
+		errType FileErrorType // expected error type, zero (copy in only) for success
+	}{
+		{"work dir", ".", Cmd{}, []string{"a.txt", "d/", "d/c.txt", "d/e/", "d/e/f.txt"}, 0},
+		{"sub dir", "d", Cmd{}, []string{"c.txt", "e/", "e/f.txt"}, 0},
+		{"within limits", "d", Cmd{CopyOutMax: 7, CopyOutMaxEntries: 3}, []string{"c.txt", "e/", "e/f.txt"}, 0},
+		{"outside", "../d", Cmd{}, nil, ErrCopyOutOpen},
+		{"not dir", "a.txt", Cmd{}, nil, ErrCopyOutOpen},
+		{"entries", "d", Cmd{CopyOutMaxEntries: 2}, nil, ErrCopyOutSizeExceeded},
+		{"total size", ".", Cmd{CopyOutMax: 8}, nil, ErrCopyOutSizeExceeded},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := newDirEnv(t, files)
+			var r copyOutResult
+			err := r.run(t, copyOutArchive, m, &tc.c, CmdCopyOutFile{Name: tc.dir, Archive: true})
+			if tc.errType != 0 {
+				if err == nil || len(r.errors) != 1 || r.errors[0].Type != tc.errType {
+					t.Fatalf("expected error type %v, got %v, %+v", tc.errType, err, r.errors)
+				}
+				if len(r.files) != 0 {
+					t.Fatalf("expected no file copied out, got %d", len(r.files))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("copyOutArchive: %v", err)
+			}
+			f := r.files[tc.dir]
+			if f == nil {
+				t.Fatalf("expected archive of %q, got %v", tc.dir, r.files)
+			}
+			f.Seek(0, io.SeekStart)
+			var entries []string
+			tr := tar.NewReader(f)
+			for {
+				h, err := tr.Next()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatalf("tar: %v", err)
+				}
+				entries = append(entries, h.Name)
+			}
+			if !slices.Equal(entries, tc.entries) {
+				t.Fatalf("expected %v, got %v", tc.entries, entries)
+			}
+		})
+	}
+}
@@ -42,7 +42,8 @@ type Pipe struct {
 	Proxy bool
 }
 
-// Run starts the cmd and returns exec results
+// Run starts the cmd and returns exec results. Services start first and the
+// other cmds start after all services are ready
 func (r *Group) Run(ctx context.Context) ([]Result, error) {
 	// prepare files
 	fds, pipeToCollect, err := prepareFds(r, r.NewStoreFile)
@@ -50,13 +51,23 @@ func (r *Group) Run(ctx context.Context) ([]Result, error) {
 		return nil, err
 	}
 
-	// wait all cmd to finish
-	var g errgroup.Group
 	result := make([]Result, len(r.Cmd))
+
+	// start services
+	var sg errgroup.Group
+	serviceCtx, stopServices := context.WithCancel(ctx)
+	defer stopServices()
+
+	services := make([]*serviceRun, 0)
 	for i, c := range r.Cmd {
-		i, c := i, c
-		g.Go(func() error {
-			r, err := runSingle(ctx, c, fds[i], pipeToCollect[i], r.NewStoreFile)
+		if c.Service == nil {
+			continue
+		}
+		s := newServiceRun(c)
+		services = append(services, s)
+		sg.Go(func() error {
+			r, err := runSingle(serviceCtx, c, fds[i], pipeToCollect[i], r.NewStoreFile)
+			s.finish(&r)
 			result[i] = r
 			if err != nil {
 				result[i].Status = StatusInternalError
@@ -66,6 +77,43 @@ func (r *Group) Run(ctx context.Context) ([]Result, error) {
 			return nil
 		})
 	}
+	readyErr := waitServices(ctx, services)
+
+	// wait all cmd to finish
+	var g errgroup.Group
+	for i, c := range r.Cmd {
+		switch {
+		case c.Service != nil:
+		case readyErr != nil:
+			closePipes(pipeToCollect[i])
+			closeFiles(fds[i]...)
+			result[i].Status = StatusInternalError
+			result[i].Error = readyErr.Error()
+		default:
+			g.Go(func() error {
+				r, err := runSingle(ctx, c, fds[i], pipeToCollect[i], r.NewStoreFile)
+				result[i] = r
+				if err != nil {
+					result[i].Status = StatusInternalError
+					result[i].Error = err.Error()
+					return err
+				}
+				return nil
+			})
+		}
+	}
 	err = g.Wait()
+
+	// stop services after other cmds finished, services that failed to be
+	// ready are killed without being marked as stopped
+	if readyErr == nil {
+		for _, s := range services {
+			s.stop()
+		}
+	}
+	stopServices()
+	if serr := sg.Wait(); err == nil {
+		err = serr
+	}
 	return result, err
 }
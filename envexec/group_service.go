@@ -0,0 +1,111 @@
+package envexec
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// serviceRun tracks a running service cmd in group
+type serviceRun struct {
+	cmd  *Cmd
+	done chan struct{}
+
+	mu       sync.Mutex
+	finished bool
+	stopped  bool // stopped by the group while running
+}
+
+func newServiceRun(c *Cmd) *serviceRun {
+	return &serviceRun{
+		cmd:  c,
+		done: make(chan struct{}),
+	}
+}
+
+// finish marks the service as exited. Service killed by the group is
+// considered as accepted unless it exceeded its limits
+func (s *serviceRun) finish(r *Result) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.finished = true
+	close(s.done)
+	if s.stopped && r.Time <= s.cmd.TimeLimit &&
+		(r.Status == StatusTimeLimitExceeded || r.Status == StatusSignalled) {
+		r.Status = StatusAccepted
+	}
+}
+
+// stop marks the service as stopped by the group if it is still running
+func (s *serviceRun) stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.finished {
+		s.stopped = true
+	}
+}
+
+// ready checks the readiness probe of the service
+func (s *serviceRun) ready() (bool, error) {
+	svc := s.cmd.Service
+	if svc.File != "" {
+		f, err := s.cmd.Environment.Open(svc.File, os.O_RDONLY, 0)
+		if err != nil {
+			return false, nil
+		}
+		f.Close()
+	}
+	if svc.Port > 0 {
+		p, ok := s.cmd.Environment.(PortProber)
+		if !ok {
+			return false, fmt.Errorf("service: port probe is not supported")
+		}
+		return p.ProbePort(svc.Port)
+	}
+	return true, nil
+}
+
+// wait waits until the service is ready, exited or timeout
+func (s *serviceRun) wait(ctx context.Context) error {
+	timeout := s.cmd.Service.Timeout
+	if timeout <= 0 {
+		timeout = defaultServiceTimeout
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	ticker := time.NewTicker(serviceProbeInterval)
+	defer ticker.Stop()
+
+	for {
+		ok, err := s.ready()
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.done:
+			return fmt.Errorf("service: exited before ready")
+		case <-timer.C:
+			return fmt.Errorf("service: not ready after %v", timeout)
+		case <-ticker.C:
+		}
+	}
+}
+
+// waitServices waits all services to be ready
+func waitServices(ctx context.Context, services []*serviceRun) error {
+	for _, s := range services {
+		if err := s.wait(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -23,6 +23,30 @@ type ExecveParam struct {
 	// TTY specifies whether to use TTY
 	TTY bool
 
+	// SeccompAudit specifies whether to run with the seccomp audit filter
+	// that logs syscalls instead of killing the process
+	SeccompAudit bool
+
+	// SyscallTrace specifies the file to write the syscall trace log of the
+	// process and its descendants, nil to disable
+	SyscallTrace *os.File
+
+	// SyscallTraceLimit specifies the maximum size of the syscall trace log, 0
+	// for unlimited
+	SyscallTraceLimit Size
+
+	// TraceProcess specifies whether to record the processes created
+	TraceProcess bool
+
+	// Perf specifies whether to count the perf events
+	Perf bool
+
+	// Network specifies the network access of the process
+	Network NetworkMode
+
+	// CoreLimit specifies the core file size limit, 0 to disable core dump
+	CoreLimit Size
+
 	// Process Limitations
 	Limit Limit
 }
@@ -31,6 +55,7 @@ type ExecveParam struct {
 type Limit struct {
 	Time         time.Duration // Time limit
 	Memory       Size          // Memory limit
+	MemoryHigh   Size          // Memory throttle limit
 	Proc         uint64        // Process count limit
 	Stack        Size          // Stack limit
 	Output       Size          // Output limit
@@ -39,6 +64,10 @@ type Limit struct {
 	CPUSet       string        // CPU set limit
 	DataSegment  bool          // Use stricter memory limit (e.g. rlimit)
 	AddressSpace bool          // rlimit address space
+	IOReadBps    Size          // Block device read bytes per second
+	IOWriteBps   Size          // Block device write bytes per second
+	IOReadIOPS   uint64        // Block device read operations per second
+	IOWriteIOPS  uint64        // Block device write operations per second
 }
 
 // Usage defines the peak process resource usage
@@ -54,6 +83,58 @@ type Process interface {
 	Usage() Usage          // Usage retrieves the process usage during the run time
 }
 
+// AuditProcess is implemented by Process running in seccomp audit mode
+type AuditProcess interface {
+	Process
+	Syscalls() []string // Syscalls returns sorted names of syscalls logged by seccomp
+}
+
+// PidProcess is implemented by Process that knows its pid in the container,
+// which names the core file dumped by the kernel
+type PidProcess interface {
+	Process
+	Pid() int // Pid returns the pid in the container pid namespace, 0 if unknown
+}
+
+// TraceProcess is implemented by Process recording the processes created
+type TraceProcess interface {
+	Process
+	Processes() []ProcessInfo // Processes returns the processes in creation order
+}
+
+// ProcessInfo defines a process created during the run
+type ProcessInfo struct {
+	Pid        int           // Pid inside the container
+	PPid       int           // PPid is the parent pid, 0 for the first process
+	Args       []string      // Args is the argv after the last execve
+	Start      time.Duration // Start is the time since the first process started
+	End        time.Duration // End is the time since the first process started, 0 if not exited
+	ExitStatus int           // ExitStatus is the exit code or the signal number
+	Signal     string        // Signal is the signal name if killed by signal
+}
+
+// StatProcess is implemented by Process that collects additional statistics
+type StatProcess interface {
+	Process
+	Stat() ProcessStat // Stat returns the statistics after the process exits
+}
+
+// ProcessStat defines additional statistics of the process
+type ProcessStat struct {
+	IORead           Size      // bytes read from block devices
+	IOWrite          Size      // bytes written to block devices
+	MemoryHighEvents uint64    // times throttled by memory.high
+	Perf             *PerfStat // perf counters if enabled
+}
+
+// PerfStat defines the perf counters of the process and its descendants
+type PerfStat struct {
+	TaskClock    time.Duration // CPU time counted by task-clock
+	Instructions uint64        // user space instructions retired
+	Cycles       uint64        // user space CPU cycles
+	Unsupported  []string      // names of counters not supported by the host
+}
+
 // Environment defines the interface to access container execution environment
 type Environment interface {
 	Execve(context.Context, ExecveParam) (Process, error)
@@ -68,5 +149,25 @@ type Environment interface {
 	CopyDir(src, dst string) error
 }
 
+// DiskLimiter is implemented by Environment that is able to limit the size and
+// file count of its work dir, the limit applies until the environment reset
+type DiskLimiter interface {
+	SetDiskLimit(size Size, files uint64) error
+	DiskUsage() (DiskUsage, error)
+}
+
+// DiskUsage defines the usage of the work dir
+type DiskUsage struct {
+	Size  Size
+	Files uint64
+	Full  bool // Full indicates either size or file count limit is reached
+}
+
+// PortProber is implemented by Environment that is able to check whether a TCP
+// port is listened inside its network
+type PortProber interface {
+	ProbePort(port int) (bool, error)
+}
+
 // NewStoreFile creates a new file in storage
 type NewStoreFile func() (*os.File, error)
@@ -0,0 +1,56 @@
+package envexec
+
+import (
+	"fmt"
+)
+
+// NetworkMode defines the network access of the process
+type NetworkMode int
+
+// Defines network modes
+const (
+	// no network access (default)
+	NetworkNone NetworkMode = iota
+
+	// private network namespace with loopback interface
+	NetworkLoopback
+
+	// private network namespace with loopback interface shared by all
+	// commands in the same group requesting it
+	NetworkShared
+)
+
+var networkModeToString = []string{
+	"none",
+	"loopback",
+	"shared",
+}
+
+// stringToNetworkMode map string to corresponding NetworkMode
+var stringToNetworkMode = make(map[string]NetworkMode)
+
+func (m NetworkMode) String() string {
+	mi := int(m)
+	if mi < 0 || mi >= len(networkModeToString) {
+		return networkModeToString[0]
+	}
+	return networkModeToString[mi]
+}
+
+// StringToNetworkMode convert string to NetworkMode, empty string is NetworkNone
+func StringToNetworkMode(s string) (NetworkMode, error) {
+	if s == "" {
+		return NetworkNone, nil
+	}
+	v, ok := stringToNetworkMode[s]
+	if !ok {
+		return 0, fmt.Errorf("invalid network mode: %s", s)
+	}
+	return v, nil
+}
+
+func init() {
+	for i, v := range networkModeToString {
+		stringToNetworkMode[v] = NetworkMode(i)
+	}
+}
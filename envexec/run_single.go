@@ -2,7 +2,9 @@ package envexec
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"time"
 
 	"github.com/criyle/go-sandbox/runner"
 )
@@ -24,6 +26,14 @@ func runSingle(pc context.Context, c *Cmd, fds []*os.File, ptc []pipeCollector,
 		closePipes(ptc)
 		closeFiles(fds...)
 	}
+	// disk limit
+	if err := setDiskLimit(m, c); err != nil {
+		result.Status = StatusInternalError
+		result.Error = err.Error()
+		closePipes(ptc)
+		closeFiles(fds...)
+		return result, nil
+	}
 	// copyin
 	if fe, err := runSingleCopyIn(m, c.CopyIn); err != nil {
 		resultFileError(err, fe)
@@ -35,8 +45,19 @@ func runSingle(pc context.Context, c *Cmd, fds []*os.File, ptc []pipeCollector,
 		return result, nil
 	}
 
+	// syscall trace log
+	trace, err := prepareSyscallTrace(c, newStoreFile)
+	if err != nil {
+		result.Status = StatusInternalError
+		result.Error = err.Error()
+		closePipes(ptc)
+		closeFiles(fds...)
+		return result, nil
+	}
+
 	// run cmd and wait for result
-	rt := runSingleWait(pc, m, c, fds)
+	start := time.Now()
+	rt, process := runSingleWait(pc, m, c, fds, trace)
 
 	// collect result
 	files, fe, err := copyOutAndCollect(m, c, ptc, newStoreFile)
@@ -51,6 +72,46 @@ func runSingle(pc context.Context, c *Cmd, fds []*os.File, ptc []pipeCollector,
 		Files:      files,
 		FileError:  fe,
 	}
+	if p, ok := process.(AuditProcess); ok {
+		result.Syscalls = p.Syscalls()
+	}
+	if p, ok := process.(TraceProcess); ok {
+		result.Processes = p.Processes()
+	}
+	if p, ok := process.(StatProcess); ok {
+		s := p.Stat()
+		result.IORead = s.IORead
+		result.IOWrite = s.IOWrite
+		result.MemoryHighEvents = s.MemoryHighEvents
+		result.Perf = s.Perf
+	}
+	if d, ok := m.(DiskLimiter); ok {
+		if u, err := d.DiskUsage(); err == nil {
+			result.DiskUsage = u.Size
+			// writes failed with ENOSPC usually end up with non-zero exit or crash
+			if u.Full && (result.Status == StatusNonzeroExitStatus || result.Status == StatusSignalled) {
+				result.Status = StatusDiskLimitExceeded
+			}
+		}
+	}
+	if c.CoreDump && rt.Status == runner.StatusSignalled {
+		var pid int
+		if p, ok := process.(PidProcess); ok {
+			pid = p.Pid()
+		}
+		crash, err := collectCrash(m, pid, rt.ExitStatus, start, limitByCopyOut(c, c.CoreDumpMax), newStoreFile)
+		if err != nil && result.Error == "" {
+			result.Error = err.Error()
+		}
+		result.Crash = crash
+	}
+	if trace != nil {
+		if result.Files == nil {
+			result.Files = make(map[string]*os.File)
+		}
+		trace.Seek(0, 0)
+		result.Files[c.SyscallTrace] = trace
+	}
 	// collect error (only if the process exits normally)
 	if rt.Status == runner.StatusNormal && err != nil && result.Error == "" {
 		switch err := err.(type) {
@@ -77,17 +138,47 @@ func runSingleCopyIn(m Environment, copyInFiles map[string]File) ([]FileError, e
 	return copyIn(m, copyInFiles)
 }
 
-func runSingleWait(pc context.Context, m Environment, c *Cmd, fds []*os.File) RunnerResult {
+func setDiskLimit(m Environment, c *Cmd) error {
+	if c.DiskLimit == 0 && c.FileCountLimit == 0 {
+		return nil
+	}
+	d, ok := m.(DiskLimiter)
+	if !ok {
+		return fmt.Errorf("disk limit is not supported by the environment")
+	}
+	return d.SetDiskLimit(c.DiskLimit, c.FileCountLimit)
+}
+
+func prepareSyscallTrace(c *Cmd, newStoreFile NewStoreFile) (*os.File, error) {
+	if c.SyscallTrace == "" {
+		return nil, nil
+	}
+	f, err := newStoreFile()
+	if err != nil {
+		return nil, fmt.Errorf("syscall trace: create file: %w", err)
+	}
+	return f, nil
+}
+
+// limitByCopyOut returns the size limit l further limited by CopyOutMax
+func limitByCopyOut(c *Cmd, l Size) Size {
+	if c.CopyOutMax > 0 && (l == 0 || l > c.CopyOutMax) {
+		l = c.CopyOutMax
+	}
+	return l
+}
+
+func runSingleWait(pc context.Context, m Environment, c *Cmd, fds []*os.File, trace *os.File) (RunnerResult, Process) {
 	// start the cmd (they will be canceled in other goroutines)
 	ctx, cancel := context.WithCancel(pc)
 	defer cancel()
 
-	process, err := runSingleExecve(ctx, m, c, fds)
+	process, err := runSingleExecve(ctx, m, c, fds, trace)
 	if err != nil {
 		return runner.Result{
 			Status: runner.StatusRunnerError,
 			Error:  err.Error(),
-		}
+		}, nil
 	}
 
 	// starts waiter to periodically check cpu usage
@@ -95,10 +186,10 @@ func runSingleWait(pc context.Context, m Environment, c *Cmd, fds []*os.File) Ru
 	// cancel the process as waiter exits
 	cancel()
 
-	return process.Result()
+	return process.Result(), process
 }
 
-func runSingleExecve(ctx context.Context, m Environment, c *Cmd, fds []*os.File) (Process, error) {
+func runSingleExecve(ctx context.Context, m Environment, c *Cmd, fds []*os.File, trace *os.File) (Process, error) {
 	defer closeFiles(fds...)
 
 	extraMemoryLimit := c.ExtraMemoryLimit
@@ -116,15 +207,28 @@ func runSingleExecve(ctx context.Context, m Environment, c *Cmd, fds []*os.File)
 		stackLimit = memoryLimit
 	}
 
+	var coreLimit Size
+	if c.CoreDump {
+		coreLimit = c.CoreDumpMax
+	}
+
 	// set running parameters
 	execParam := ExecveParam{
-		Args:  c.Args,
-		Env:   c.Env,
-		Files: getFdArray(fds),
-		TTY:   c.TTY,
+		Args:              c.Args,
+		Env:               c.Env,
+		Files:             getFdArray(fds),
+		TTY:               c.TTY,
+		SeccompAudit:      c.SeccompAudit,
+		SyscallTrace:      trace,
+		SyscallTraceLimit: limitByCopyOut(c, c.SyscallTraceMax),
+		TraceProcess:      c.TraceProcess,
+		Perf:              c.Perf,
+		Network:           c.Network,
+		CoreLimit:         coreLimit,
 		Limit: Limit{
 			Time:         c.TimeLimit,
 			Memory:       memoryLimit,
+			MemoryHigh:   c.MemoryHigh,
 			Proc:         c.ProcLimit,
 			Stack:        stackLimit,
 			Output:       c.OutputLimit,
@@ -133,6 +237,10 @@ func runSingleExecve(ctx context.Context, m Environment, c *Cmd, fds []*os.File)
 			CPUSet:       c.CPUSetLimit,
 			DataSegment:  c.DataSegmentLimit,
 			AddressSpace: c.AddressSpaceLimit,
+			IOReadBps:    c.IOReadBps,
+			IOWriteBps:   c.IOWriteBps,
+			IOReadIOPS:   c.IOReadIOPS,
+			IOWriteIOPS:  c.IOWriteIOPS,
 		},
 	}
 	return m.Execve(ctx, execParam)
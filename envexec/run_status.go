@@ -34,6 +34,9 @@ const (
 
 	// internal error including: cgroup init failed, container failed, etc
 	StatusInternalError
+
+	// work dir is full by disk or file count limit
+	StatusDiskLimitExceeded // DLE
 )
 
 var statusToString = []string{
@@ -51,6 +54,7 @@ var statusToString = []string{
 	"Judgement Failed",
 	"Invalid Interaction",
 	"Internal Error",
+	"Disk Limit Exceeded",
 }
 
 // stringToStatus map string to corresponding Status
@@ -0,0 +1,124 @@
+package filestore
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/criyle/go-judge/envexec"
+)
+
+// ErrInvalidBundle is returned when the archive cannot be extracted as a bundle
+var ErrInvalidBundle = errors.New("invalid bundle")
+
+// BundleLimit defines the limits to extract an archive into a bundle
+type BundleLimit = envexec.ArchiveLimit
+
+// AddBundle extracts the tar (optionally gzip compressed) or zip archive into
+// a directory and adds it to the file store as a bundle, so that the whole
+// tree is copied in by the id
+func AddBundle(fs FileStore, name string, r io.ReaderAt, size int64, l BundleLimit) (string, error) {
+	f, err := fs.New()
+	if err != nil {
+		return "", err
+	}
+	// replace the store file by a directory with the same id
+	dir := f.Name()
+	f.Close()
+	if err := os.Remove(dir); err != nil {
+		return "", err
+	}
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		return "", err
+	}
+	if err := envexec.ExtractArchive(hostDir(dir), "", r, size, l); err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("%w: %w", ErrInvalidBundle, err)
+	}
+	id, err := fs.Add(name, dir)
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+	return id, nil
+}
+
+// WriteBundle writes the bundle directory as a tar archive
+func WriteBundle(w io.Writer, dir string) error {
+	tw := tar.NewWriter(w)
+	if err := tw.AddFS(os.DirFS(dir)); err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// hostDir creates the extracted entries in the directory on the host
+type hostDir string
+
+func (d hostDir) Open(path string, flags int, perm os.FileMode) (*os.File, error) {
+	return os.OpenFile(filepath.Join(string(d), path), flags, perm)
+}
+
+func (d hostDir) MkdirAll(path string, perm os.FileMode) error {
+	return os.MkdirAll(filepath.Join(string(d), path), perm)
+}
+
+// pathSize returns the size of the file or the total size of files in the
+// bundle directory
+func pathSize(path string) int64 {
+	var size int64
+	filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err == nil && d.Type().IsRegular() {
+			if fi, err := d.Info(); err == nil {
+				size += fi.Size()
+			}
+		}
+		return nil
+	})
+	return size
+}
+
+// dirDigest returns the digest of the directory tree by the path, type and
+// content digest of each entry in lexical order, with the total file size
+func dirDigest(dir string) (string, int64, error) {
+	h := sha256.New()
+	var size int64
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || path == dir {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		switch {
+		case d.IsDir():
+			fmt.Fprintf(h, "d %s\n", rel)
+		case d.Type().IsRegular():
+			d, err := fileDigest(path)
+			if err != nil {
+				return err
+			}
+			fi, err := os.Stat(path)
+			if err != nil {
+				return err
+			}
+			size += fi.Size()
+			fmt.Fprintf(h, "f %s %s\n", rel, d)
+		default:
+			return fmt.Errorf("%q: not a regular file or directory", rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), size, nil
+}
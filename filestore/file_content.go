@@ -0,0 +1,282 @@
+package filestore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/criyle/go-judge/envexec"
+)
+
+var _ RefFileStore = &fileContentStore{}
+
+// fileContentStore stores files by the SHA-256 digest of the content, so that
+// identical content is stored once with reference count
+type fileContentStore struct {
+	dir   string               // directory to store file
+	tmp   string               // directory to store file not yet added
+	meta  map[string]*fileMeta // id to metadata mapping
+	refs  map[string]int       // id to reference count
+	index *index               // persisted index
+	mu    sync.RWMutex
+}
+
+// NewFileContentStore create new content-addressed file store. The index with
+// reference count is persisted in the directory, existing files not in the
+// index are loaded with a single reference.
+func NewFileContentStore(dir string) (FileStore, error) {
+	dir = filepath.Clean(dir)
+	tmp := filepath.Join(dir, tmpDir)
+	if err := os.RemoveAll(tmp); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(tmp, 0o755); err != nil {
+		return nil, err
+	}
+	s := &fileContentStore{
+		dir:  dir,
+		tmp:  tmp,
+		meta: make(map[string]*fileMeta),
+		refs: make(map[string]int),
+	}
+	idx, records, err := openIndex(dir)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range fi {
+		if (!f.Type().IsRegular() && !f.IsDir()) || !isDigest(f.Name()) {
+			continue
+		}
+		if r, ok := records[f.Name()]; ok {
+			s.meta[f.Name()] = r.fileMeta()
+			s.refs[f.Name()] = max(r.Refs, 1)
+			continue
+		}
+		fi, err := f.Info()
+		if err != nil {
+			continue
+		}
+		m := &fileMeta{
+			name:    f.Name(),
+			size:    fi.Size(),
+			sha256:  f.Name(),
+			created: fi.ModTime(),
+			bundle:  fi.IsDir(),
+		}
+		if fi.IsDir() {
+			m.size = pathSize(filepath.Join(dir, f.Name()))
+		}
+		m.touch(fi.ModTime())
+		s.meta[f.Name()] = m
+		s.refs[f.Name()] = 1
+	}
+	if err := idx.compact(s.records()); err != nil {
+		return nil, err
+	}
+	s.index = idx
+	return s, nil
+}
+
+func (s *fileContentStore) Add(name, path string) (string, error) {
+	if s.tmp != filepath.Dir(path) {
+		return "", fmt.Errorf("add: %s does not have prefix %s", path, s.tmp)
+	}
+	st, err := statFile(path)
+	if err != nil {
+		return "", fmt.Errorf("add: %w", err)
+	}
+	id := st.sha256
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// identical content added again keeps the name it was first added with
+	if s.refs[id] > 0 {
+		s.refs[id]++
+		if err := s.write(id); err != nil {
+			s.refs[id]--
+			return "", fmt.Errorf("add: index: %w", err)
+		}
+		os.RemoveAll(path)
+		return id, nil
+	}
+
+	p := filepath.Join(s.dir, id)
+	if err := os.Rename(path, p); err != nil {
+		return "", fmt.Errorf("add: %w", err)
+	}
+	m := newFileMeta(name)
+	m.setStat(st)
+	s.meta[id] = m
+	s.refs[id] = 1
+	if err := s.write(id); err != nil {
+		// roll back so that the caller can retry or remove the file
+		delete(s.meta, id)
+		delete(s.refs, id)
+		os.Rename(p, path)
+		return "", fmt.Errorf("add: index: %w", err)
+	}
+	return id, nil
+}
+
+func (s *fileContentStore) AddRef(id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.refs[id] == 0 {
+		return false, nil
+	}
+	s.refs[id]++
+	if err := s.write(id); err != nil {
+		s.refs[id]--
+		return false, fmt.Errorf("add ref: index: %w", err)
+	}
+	return true, nil
+}
+
+func (s *fileContentStore) Get(id string) (string, envexec.File) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	m, ok := s.meta[id]
+	if !ok {
+		return "", nil
+	}
+	m.touch(time.Now())
+	return m.name, envexec.NewFileInput(filepath.Join(s.dir, id))
+}
+
+// Stat does not read the content since the digest is the id and the size is
+// filled when the file is added or loaded
+func (s *fileContentStore) Stat(id string) (FileMeta, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	m, ok := s.meta[id]
+	if !ok {
+		return FileMeta{}, false
+	}
+	meta := m.meta()
+	meta.Refs = s.refs[id]
+	return meta, true
+}
+
+func (s *fileContentStore) SetLabels(id string, labels map[string]string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, ok := s.meta[id]
+	if !ok {
+		return false
+	}
+	m.setLabels(labels)
+	s.write(id)
+	return true
+}
+
+func (s *fileContentStore) Lease(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, ok := s.meta[id]
+	if !ok {
+		return false
+	}
+	m.leases++
+	return true
+}
+
+func (s *fileContentStore) Release(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if m, ok := s.meta[id]; ok {
+		m.release()
+	}
+}
+
+// Flush persists the access time kept in memory to the index
+func (s *fileContentStore) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.index.flush(s.records)
+}
+
+func (s *fileContentStore) Remove(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.refs[id] == 0 {
+		return false
+	}
+	s.refs[id]--
+	if s.refs[id] > 0 {
+		s.write(id)
+		return true
+	}
+	delete(s.refs, id)
+	delete(s.meta, id)
+	os.RemoveAll(filepath.Join(s.dir, id))
+	s.index.write(indexRecord{ID: id, Delete: true}, len(s.meta), s.records)
+	return true
+}
+
+func (s *fileContentStore) List() map[string]string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	names := make(map[string]string, len(s.meta))
+	for id, m := range s.meta {
+		names[id] = m.name
+	}
+	return names
+}
+
+func (s *fileContentStore) New() (*os.File, error) {
+	return os.CreateTemp(s.tmp, "")
+}
+
+// write persists the metadata and reference count to the index, caller
+// should hold the lock
+func (s *fileContentStore) write(id string) error {
+	return s.index.write(newIndexRecord(id, s.meta[id], s.refs[id]), len(s.meta), s.records)
+}
+
+// records returns the live records for index compaction, caller should hold
+// the lock
+func (s *fileContentStore) records() map[string]indexRecord {
+	records := make(map[string]indexRecord, len(s.meta))
+	for id, m := range s.meta {
+		records[id] = newIndexRecord(id, m, s.refs[id])
+	}
+	return records
+}
+
+func fileDigest(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func isDigest(id string) bool {
+	b, err := hex.DecodeString(id)
+	return err == nil && len(b) == sha256.Size
+}
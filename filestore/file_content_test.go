@@ -0,0 +1,88 @@
+package filestore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+)
+
+// addFile creates a file with content through New and adds it to the store
+func addFile(t *testing.T, fs FileStore, name, content string) string {
+	t.Helper()
+	f, err := fs.New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if _, err := f.WriteString(content); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	f.Close()
+	id, err := fs.Add(name, f.Name())
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	return id
+}
+
+func TestFileContentStoreRefs(t *testing.T) {
+	fs, err := NewFileContentStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create file store: %v", err)
+	}
+	content := "print(58 - 7 * 3)"
+	sum := sha256.Sum256([]byte(content))
+	digest := hex.EncodeToString(sum[:])
+
+	for _, name := range []string{"a.py", "b.py"} {
+		if id := addFile(t, fs, name, content); id != digest {
+			t.Fatalf("Add %s: expected id %s, got %s", name, digest, id)
+		}
+	}
+	// the name is not changed by other uploads of the same content
+	if names := fs.List(); names[digest] != "a.py" {
+		t.Fatalf("List: expected first name kept, got %v", names)
+	}
+	if ok, err := AddRef(fs, digest); !ok || err != nil {
+		t.Fatalf("AddRef: expected true, got %v, %v", ok, err)
+	}
+	if ok, err := AddRef(fs, "missing"); ok || err != nil {
+		t.Fatalf("AddRef missing: expected false, got %v, %v", ok, err)
+	}
+	m, ok := fs.Stat(digest)
+	if !ok || m.Refs != 3 || m.SHA256 != digest || m.Size != int64(len(content)) || m.Name != "a.py" {
+		t.Fatalf("Stat: unexpected %+v, %v", m, ok)
+	}
+
+	for i := range 3 {
+		if _, f := fs.Get(digest); f == nil {
+			t.Fatalf("File should exist after %d removes", i)
+		}
+		if !fs.Remove(digest) {
+			t.Fatalf("Remove %d: expected true", i)
+		}
+	}
+	if _, f := fs.Get(digest); f != nil {
+		t.Fatalf("File should be removed after all references removed")
+	}
+	if fs.Remove(digest) {
+		t.Fatalf("Remove: expected false after all references removed")
+	}
+	if ok, _ := AddRef(fs, digest); ok {
+		t.Fatalf("AddRef: expected false after all references removed")
+	}
+}
+
+func TestAddRefNotContentAddressed(t *testing.T) {
+	stores := map[string]FileStore{
+		"local":   NewFileLocalStore(t.TempDir()),
+		"lru":     NewLRU(NewFileLocalStore(t.TempDir()), 0, 0),
+		"timeout": NewTimeout(NewFileLocalStore(t.TempDir()), time.Hour, time.Hour),
+	}
+	for name, fs := range stores {
+		if _, err := AddRef(fs, "id"); !errors.Is(err, ErrNotContentAddressed) {
+			t.Fatalf("%s: expected ErrNotContentAddressed, got %v", name, err)
+		}
+	}
+}
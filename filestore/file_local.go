@@ -5,38 +5,92 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/criyle/go-judge/envexec"
 )
 
 type fileLocalStore struct {
-	dir  string            // directory to store file
-	name map[string]string // id to name mapping if exists
-	mu   sync.RWMutex
+	dir   string               // directory to store file
+	tmp   string               // directory to store file not yet added
+	meta  map[string]*fileMeta // id to metadata mapping if exists
+	index *index               // persisted index, nil if failed to open
+	mu    sync.RWMutex
 }
 
-// NewFileLocalStore create new local file store
+// NewFileLocalStore create new local file store. The index is persisted in
+// the directory and reloaded for files still exist, files created by New but
+// not added before restart are removed.
 func NewFileLocalStore(dir string) FileStore {
-	return &fileLocalStore{
+	s := &fileLocalStore{
 		dir:  filepath.Clean(dir),
-		name: make(map[string]string),
+		meta: make(map[string]*fileMeta),
 	}
+	s.tmp = filepath.Join(s.dir, tmpDir)
+	os.RemoveAll(s.tmp)
+	os.MkdirAll(s.tmp, 0o755)
+
+	idx, records, err := openIndex(s.dir)
+	if err != nil {
+		return s
+	}
+	for id, r := range records {
+		if _, err := os.Stat(filepath.Join(s.dir, id)); err == nil {
+			s.meta[id] = r.fileMeta()
+		}
+	}
+	if err := idx.compact(s.records()); err == nil {
+		s.index = idx
+	}
+	return s
 }
 
 func (s *fileLocalStore) Add(name, path string) (string, error) {
+	dir := filepath.Dir(path)
+	if dir != s.dir && dir != s.tmp {
+		return "", fmt.Errorf("add: %s does not have prefix %s", path, s.dir)
+	}
+	id := filepath.Base(path)
+	st, err := statFile(path)
+	if err != nil {
+		return "", fmt.Errorf("add: %w", err)
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if s.dir == filepath.Dir(path) {
-		id := filepath.Base(path)
-		s.name[id] = name
-		return id, nil
+	p := filepath.Join(s.dir, id)
+	if dir == s.tmp {
+		if err := os.Rename(path, p); err != nil {
+			return "", fmt.Errorf("add: %w", err)
+		}
 	}
-	return "", fmt.Errorf("add: %s does not have prefix %s", path, s.dir)
+	prev, existed := s.meta[id]
+	m := newFileMeta(name)
+	m.setStat(st)
+	s.meta[id] = m
+	if err := s.write(id, m); err != nil {
+		// roll back so that the caller can retry or remove the file
+		if existed {
+			s.meta[id] = prev
+		} else {
+			delete(s.meta, id)
+		}
+		if dir == s.tmp {
+			os.Rename(p, path)
+		}
+		return "", fmt.Errorf("add: index: %w", err)
+	}
+	return id, nil
 }
 
 func (s *fileLocalStore) Get(id string) (string, envexec.File) {
+	if !validID(id) {
+		return "", nil
+	}
+
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
@@ -44,18 +98,104 @@ func (s *fileLocalStore) Get(id string) (string, envexec.File) {
 	if _, err := os.Stat(p); os.IsNotExist(err) {
 		return "", nil
 	}
-	name, ok := s.name[id]
+	m, ok := s.meta[id]
+	if !ok {
+		return id, envexec.NewFileInput(p)
+	}
+	m.touch(time.Now())
+	return m.name, envexec.NewFileInput(p)
+}
+
+func (s *fileLocalStore) Stat(id string) (FileMeta, bool) {
+	if !validID(id) {
+		return FileMeta{}, false
+	}
+	p := filepath.Join(s.dir, id)
+	if _, err := os.Stat(p); err != nil {
+		return FileMeta{}, false
+	}
+
+	s.mu.RLock()
+	if m, ok := s.meta[id]; ok && m.stated() {
+		defer s.mu.RUnlock()
+		return m.meta(), true
+	}
+	s.mu.RUnlock()
+
+	// files put into the directory without Add are hashed outside the lock
+	st, err := statFile(p)
+	if err != nil {
+		return FileMeta{}, false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m := s.lookup(id)
+	if m == nil {
+		return FileMeta{}, false
+	}
+	if !m.stated() {
+		m.setStat(st)
+		s.write(id, m)
+	}
+	return m.meta(), true
+}
+
+func (s *fileLocalStore) SetLabels(id string, labels map[string]string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, ok := s.meta[id]
 	if !ok {
-		name = id
+		return false
 	}
-	return name, envexec.NewFileInput(p)
+	m.setLabels(labels)
+	s.write(id, m)
+	return true
+}
+
+func (s *fileLocalStore) Lease(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m := s.lookup(id)
+	if m == nil {
+		return false
+	}
+	m.leases++
+	return true
+}
+
+func (s *fileLocalStore) Release(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if m, ok := s.meta[id]; ok {
+		m.release()
+	}
+}
+
+// Flush persists the access time kept in memory to the index
+func (s *fileLocalStore) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.index.flush(s.records)
 }
 
 func (s *fileLocalStore) Remove(id string) bool {
+	if !validID(id) {
+		return false
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	delete(s.name, id)
+	if _, ok := s.meta[id]; ok {
+		delete(s.meta, id)
+		s.index.write(indexRecord{ID: id, Delete: true}, len(s.meta), s.records)
+	}
 	p := filepath.Join(s.dir, id)
 	fmt.Println("Removing file:", p)
 	if _, err := os.Stat(p); os.IsNotExist(err) {
@@ -76,7 +216,14 @@ func (s *fileLocalStore) List() map[string]string {
 
 	names := make(map[string]string, len(fi))
 	for _, f := range fi {
-		names[f.Name()] = s.name[f.Name()]
+		if strings.HasPrefix(f.Name(), ".") {
+			continue // index and temporary files
+		}
+		if m, ok := s.meta[f.Name()]; ok {
+			names[f.Name()] = m.name
+		} else {
+			names[f.Name()] = ""
+		}
 	}
 	return names
 }
@@ -87,7 +234,10 @@ func (s *fileLocalStore) New() (*os.File, error) {
 		if err != nil {
 			return nil, err
 		}
-		f, err := os.OpenFile(filepath.Join(s.dir, id), os.O_CREATE|os.O_RDWR|os.O_EXCL, 0644)
+		if _, err := os.Stat(filepath.Join(s.dir, id)); err == nil {
+			continue
+		}
+		f, err := os.OpenFile(filepath.Join(s.tmp, id), os.O_CREATE|os.O_RDWR|os.O_EXCL, 0644)
 		if err == nil {
 			return f, nil
 		}
@@ -97,3 +247,37 @@ func (s *fileLocalStore) New() (*os.File, error) {
 	}
 	return nil, errUniqueIDNotGenerated
 }
+
+// lookup returns the metadata of the file, the metadata is created for files
+// exist in the directory but not added, caller should hold the lock
+func (s *fileLocalStore) lookup(id string) *fileMeta {
+	if !validID(id) {
+		return nil
+	}
+	fi, err := os.Stat(filepath.Join(s.dir, id))
+	if err != nil {
+		return nil
+	}
+	m, ok := s.meta[id]
+	if !ok {
+		m = &fileMeta{name: id, size: -1, created: fi.ModTime()}
+		m.touch(fi.ModTime())
+		s.meta[id] = m
+	}
+	return m
+}
+
+// write persists the metadata to the index, caller should hold the lock
+func (s *fileLocalStore) write(id string, m *fileMeta) error {
+	return s.index.write(newIndexRecord(id, m, 0), len(s.meta), s.records)
+}
+
+// records returns the live records for index compaction, caller should hold
+// the lock
+func (s *fileLocalStore) records() map[string]indexRecord {
+	records := make(map[string]indexRecord, len(s.meta))
+	for id, m := range s.meta {
+		records[id] = newIndexRecord(id, m, 0)
+	}
+	return records
+}
@@ -0,0 +1,557 @@
+package filestore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/criyle/go-judge/envexec"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"golang.org/x/sync/singleflight"
+)
+
+// S3Config defines the S3-compatible object storage for the file store
+type S3Config struct {
+	Endpoint  string // host and port of the storage
+	Bucket    string // bucket should exist
+	Prefix    string // prefix of object keys
+	Region    string
+	AccessKey string
+	SecretKey string
+	Insecure  bool // use plain HTTP
+
+	CacheMaxSize int64       // max total size of the local copies, 0 for unlimited
+	BundleLimit  BundleLimit // limit to extract the downloaded bundles
+}
+
+// user metadata keys of the objects
+const (
+	s3MetaName    = "Name"
+	s3MetaSHA256  = "Sha256"
+	s3MetaCreated = "Created"
+	s3MetaLabels  = "Labels"
+	s3MetaBundle  = "Bundle" // bundle is stored as tar archive
+	s3MetaSize    = "Size"   // total size of files in the bundle
+)
+
+const (
+	s3RequestTimeout  = 30 * time.Second // timeout of requests without content
+	s3TransferTimeout = 10 * time.Minute // timeout to upload or download an object
+	s3CheckInterval   = 10 * time.Second // local copies are served without checking the object within
+)
+
+// fileS3Store stores files as objects in S3-compatible storage so that they
+// are shared between nodes. Objects are immutable once added except labels,
+// so the local copy in dir is served if the object was checked to exist
+// within the check interval. The least recently used local copies are evicted
+// when the cache is full. Requests to the storage are made without holding
+// the lock.
+type fileS3Store struct {
+	client        *minio.Client
+	bucket        string
+	prefix        string
+	dir           string               // directory to cache objects
+	tmp           string               // directory to store file not yet added
+	meta          map[string]*fileMeta // id to metadata of files used on this node
+	cache         map[string]*s3Cached // id to local copy in dir
+	cacheSize     int64                // total size of the local copies
+	cacheMaxSize  int64
+	checkInterval time.Duration
+	bundleLimit   BundleLimit
+	mu            sync.Mutex
+	fetch         singleflight.Group // downloads of the same id are merged
+}
+
+// s3Cached is the local copy of an object
+type s3Cached struct {
+	size    int64
+	checked time.Time // time the object was last checked to exist
+}
+
+// NewFileS3Store create new file store backed by S3-compatible storage with
+// read-through cache in dir
+func NewFileS3Store(dir string, conf S3Config) (FileStore, error) {
+	client, err := minio.New(conf.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(conf.AccessKey, conf.SecretKey, ""),
+		Secure: !conf.Insecure,
+		Region: conf.Region,
+	})
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), s3RequestTimeout)
+	defer cancel()
+	ok, err := client.BucketExists(ctx, conf.Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("s3: %w", err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("s3: bucket %q does not exist", conf.Bucket)
+	}
+
+	dir = filepath.Clean(dir)
+	tmp := filepath.Join(dir, tmpDir)
+	if err := os.RemoveAll(tmp); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(tmp, 0o755); err != nil {
+		return nil, err
+	}
+	s := &fileS3Store{
+		client:        client,
+		bucket:        conf.Bucket,
+		prefix:        conf.Prefix,
+		dir:           dir,
+		tmp:           tmp,
+		meta:          make(map[string]*fileMeta),
+		cache:         make(map[string]*s3Cached),
+		cacheMaxSize:  conf.CacheMaxSize,
+		checkInterval: s3CheckInterval,
+		bundleLimit:   conf.BundleLimit,
+	}
+	// local copies left by the previous run are checked on use
+	fi, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range fi {
+		if !validID(f.Name()) {
+			continue
+		}
+		size := pathSize(filepath.Join(dir, f.Name()))
+		s.cache[f.Name()] = &s3Cached{size: size}
+		s.cacheSize += size
+	}
+	s.evict("")
+	return s, nil
+}
+
+func (s *fileS3Store) Add(name, path string) (string, error) {
+	if s.tmp != filepath.Dir(path) {
+		return "", fmt.Errorf("add: %s does not have prefix %s", path, s.tmp)
+	}
+	id := filepath.Base(path)
+	st, err := statFile(path)
+	if err != nil {
+		return "", fmt.Errorf("add: %w", err)
+	}
+	m := newFileMeta(name)
+	m.setStat(st)
+	if err := s.put(id, path, m); err != nil {
+		return "", fmt.Errorf("add: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Rename(path, filepath.Join(s.dir, id)); err != nil {
+		os.RemoveAll(path)
+	} else {
+		s.cache[id] = &s3Cached{size: m.size, checked: time.Now()}
+		s.cacheSize += m.size
+	}
+	s.meta[id] = m
+	s.evict(id)
+	return id, nil
+}
+
+func (s *fileS3Store) Get(id string) (string, envexec.File) {
+	if !validID(id) {
+		return "", nil
+	}
+	p := filepath.Join(s.dir, id)
+
+	s.mu.Lock()
+	if m, c := s.meta[id], s.cache[id]; m != nil && c != nil && time.Since(c.checked) < s.checkInterval {
+		m.touch(time.Now())
+		s.mu.Unlock()
+		return m.name, envexec.NewFileInput(p)
+	}
+	s.mu.Unlock()
+
+	info, err := s.stat(id)
+	if isS3NotFound(err) {
+		// removed by other nodes
+		s.mu.Lock()
+		s.drop(id)
+		s.mu.Unlock()
+		return "", nil
+	}
+	if err != nil {
+		return "", nil
+	}
+	m := s3FileMeta(info)
+	_, err, _ = s.fetch.Do(id, func() (any, error) {
+		s.mu.Lock()
+		_, ok := s.cache[id]
+		s.mu.Unlock()
+		if ok {
+			return nil, nil // fetched by the call just finished
+		}
+		if err := s.download(id, m.bundle); err != nil {
+			return nil, err
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		// used before eviction so that the new copy is not evicted first
+		s.cache[id] = &s3Cached{size: m.size}
+		s.cacheSize += m.size
+		s.use(id, m)
+		return nil, nil
+	})
+	if err != nil {
+		return "", nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.cache[id]
+	if !ok {
+		return "", nil // evicted right after downloaded
+	}
+	c.checked = time.Now()
+	s.use(id, m)
+	s.evict(id)
+	return m.name, envexec.NewFileInput(p)
+}
+
+// use records the metadata of the file accessed now, caller should hold the
+// lock
+func (s *fileS3Store) use(id string, m *fileMeta) {
+	if lm, ok := s.meta[id]; ok {
+		m.leases = lm.leases
+	}
+	m.touch(time.Now())
+	s.meta[id] = m
+}
+
+func (s *fileS3Store) Stat(id string) (FileMeta, bool) {
+	info, err := s.stat(id)
+	if err != nil {
+		return FileMeta{}, false
+	}
+	m := s3FileMeta(info)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if lm, ok := s.meta[id]; ok {
+		m.touch(lm.accessedAt())
+		m.leases = lm.leases
+	}
+	return m.meta(), true
+}
+
+func (s *fileS3Store) SetLabels(id string, labels map[string]string) bool {
+	info, err := s.stat(id)
+	if err != nil {
+		return false
+	}
+	m := s3FileMeta(info)
+	m.setLabels(labels)
+
+	ctx, cancel := context.WithTimeout(context.Background(), s3RequestTimeout)
+	defer cancel()
+	_, err = s.client.CopyObject(ctx, minio.CopyDestOptions{
+		Bucket:          s.bucket,
+		Object:          s.prefix + id,
+		UserMetadata:    s3UserMeta(m),
+		ReplaceMetadata: true,
+	}, minio.CopySrcOptions{
+		Bucket: s.bucket,
+		Object: s.prefix + id,
+	})
+	return err == nil
+}
+
+func (s *fileS3Store) Lease(id string) bool {
+	s.mu.Lock()
+	if m, ok := s.meta[id]; ok {
+		m.leases++
+		s.mu.Unlock()
+		return true
+	}
+	s.mu.Unlock()
+
+	info, err := s.stat(id)
+	if err != nil {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, ok := s.meta[id]
+	if !ok {
+		m = s3FileMeta(info)
+		s.meta[id] = m
+	}
+	m.leases++
+	return true
+}
+
+func (s *fileS3Store) Release(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, ok := s.meta[id]
+	if !ok {
+		return
+	}
+	m.release()
+	if m.leases > 0 {
+		return
+	}
+	// metadata is kept only for the files with local copy
+	if _, ok := s.cache[id]; !ok {
+		delete(s.meta, id)
+	}
+	s.evict("")
+}
+
+func (s *fileS3Store) Remove(id string) bool {
+	if !validID(id) {
+		return false
+	}
+	s.mu.Lock()
+	s.drop(id)
+	s.mu.Unlock()
+
+	if _, err := s.stat(id); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), s3RequestTimeout)
+	defer cancel()
+	err := s.client.RemoveObject(ctx, s.bucket, s.prefix+id, minio.RemoveObjectOptions{})
+	return err == nil
+}
+
+func (s *fileS3Store) List() map[string]string {
+	ctx, cancel := context.WithTimeout(context.Background(), s3RequestTimeout)
+	defer cancel()
+
+	names := make(map[string]string)
+	for obj := range s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{
+		Prefix:       s.prefix,
+		Recursive:    true,
+		WithMetadata: true,
+	}) {
+		if obj.Err != nil {
+			return nil
+		}
+		names[strings.TrimPrefix(obj.Key, s.prefix)] = s3FileMeta(obj).name
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// storage may not support listing with metadata
+	for id, name := range names {
+		if m, ok := s.meta[id]; ok && name == "" {
+			names[id] = m.name
+		}
+	}
+	return names
+}
+
+// New creates the file without checking the storage since the random id is
+// unlikely to collide
+func (s *fileS3Store) New() (*os.File, error) {
+	for range [50]struct{}{} {
+		id, err := generateID()
+		if err != nil {
+			return nil, err
+		}
+		f, err := os.OpenFile(filepath.Join(s.tmp, id), os.O_CREATE|os.O_RDWR|os.O_EXCL, 0644)
+		if err == nil {
+			return f, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+	}
+	return nil, errUniqueIDNotGenerated
+}
+
+// evict removes the least recently used local copies that are not leased
+// until the cache fits, except the copy of id keep. Caller should hold the
+// lock.
+func (s *fileS3Store) evict(keep string) {
+	if s.cacheMaxSize <= 0 || s.cacheSize <= s.cacheMaxSize {
+		return
+	}
+	type candidate struct {
+		id       string
+		accessed time.Time // zero for copies not used yet
+	}
+	var candidates []candidate
+	for id := range s.cache {
+		m := s.meta[id]
+		if id == keep || (m != nil && m.leases > 0) {
+			continue
+		}
+		c := candidate{id: id}
+		if m != nil {
+			c.accessed = m.accessedAt()
+		}
+		candidates = append(candidates, c)
+	}
+	slices.SortFunc(candidates, func(a, b candidate) int {
+		return a.accessed.Compare(b.accessed)
+	})
+	for _, c := range candidates {
+		if s.cacheSize <= s.cacheMaxSize {
+			return
+		}
+		s.drop(c.id)
+	}
+}
+
+// drop removes the local copy and the metadata of the file, caller should
+// hold the lock
+func (s *fileS3Store) drop(id string) {
+	if c, ok := s.cache[id]; ok {
+		s.cacheSize -= c.size
+		delete(s.cache, id)
+	}
+	delete(s.meta, id)
+	os.RemoveAll(filepath.Join(s.dir, id))
+}
+
+func (s *fileS3Store) stat(id string) (minio.ObjectInfo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s3RequestTimeout)
+	defer cancel()
+	return s.client.StatObject(ctx, s.bucket, s.prefix+id, minio.StatObjectOptions{})
+}
+
+// isS3NotFound returns whether the error is returned for object not exists
+func isS3NotFound(err error) bool {
+	return err != nil && minio.ToErrorResponse(err).StatusCode == http.StatusNotFound
+}
+
+// put uploads the file or the bundle as tar archive
+func (s *fileS3Store) put(id, path string, m *fileMeta) error {
+	if m.bundle {
+		f, err := os.CreateTemp(s.tmp, "")
+		if err != nil {
+			return err
+		}
+		defer os.Remove(f.Name())
+		defer f.Close()
+
+		if err := WriteBundle(f, path); err != nil {
+			return err
+		}
+		path = f.Name()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), s3TransferTimeout)
+	defer cancel()
+	_, err := s.client.FPutObject(ctx, s.bucket, s.prefix+id, path, minio.PutObjectOptions{
+		ContentType:  "application/octet-stream",
+		UserMetadata: s3UserMeta(m),
+	})
+	return err
+}
+
+// download downloads the object into the cache directory, bundle is
+// extracted from the tar archive. Caller should merge the downloads of the
+// same id since they share the temporary path.
+func (s *fileS3Store) download(id string, bundle bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s3TransferTimeout)
+	defer cancel()
+
+	tmp := filepath.Join(s.tmp, id)
+	if err := s.client.FGetObject(ctx, s.bucket, s.prefix+id, tmp, minio.GetObjectOptions{}); err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	if bundle {
+		f, err := os.Open(tmp)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		fi, err := f.Stat()
+		if err != nil {
+			return err
+		}
+
+		dir := tmp + ".d"
+		if err := os.Mkdir(dir, 0o755); err != nil {
+			return err
+		}
+		defer os.RemoveAll(dir)
+		if err := envexec.ExtractArchive(hostDir(dir), "", f, fi.Size(), s.bundleLimit); err != nil {
+			return err
+		}
+		tmp = dir
+	}
+	return os.Rename(tmp, filepath.Join(s.dir, id))
+}
+
+// s3UserMeta encodes the metadata as object user metadata, values are escaped
+// since they are sent as HTTP headers
+func s3UserMeta(m *fileMeta) map[string]string {
+	labels := make(url.Values, len(m.labels))
+	for k, v := range m.labels {
+		labels.Set(k, v)
+	}
+	meta := map[string]string{
+		s3MetaName:    url.QueryEscape(m.name),
+		s3MetaSHA256:  m.sha256,
+		s3MetaCreated: m.created.Format(time.RFC3339Nano),
+		s3MetaLabels:  url.QueryEscape(labels.Encode()),
+	}
+	if m.bundle {
+		meta[s3MetaBundle] = "true"
+		meta[s3MetaSize] = strconv.FormatInt(m.size, 10)
+	}
+	return meta
+}
+
+// s3FileMeta decodes the metadata from the object info
+func s3FileMeta(info minio.ObjectInfo) *fileMeta {
+	meta := make(map[string]string, len(info.UserMetadata))
+	for k, v := range info.UserMetadata {
+		// list with metadata returns keys with the header prefix
+		meta[strings.TrimPrefix(http.CanonicalHeaderKey(k), "X-Amz-Meta-")] = v
+	}
+	m := &fileMeta{
+		size:    info.Size,
+		sha256:  meta[s3MetaSHA256],
+		created: info.LastModified,
+	}
+	m.touch(info.LastModified)
+	m.name, _ = url.QueryUnescape(meta[s3MetaName])
+	if meta[s3MetaBundle] == "true" {
+		m.bundle = true
+		m.size, _ = strconv.ParseInt(meta[s3MetaSize], 10, 64)
+	}
+	if t, err := time.Parse(time.RFC3339Nano, meta[s3MetaCreated]); err == nil {
+		m.created = t
+	}
+	if v, err := url.QueryUnescape(meta[s3MetaLabels]); err == nil {
+		labels, _ := url.ParseQuery(v)
+		for k := range labels {
+			if m.labels == nil {
+				m.labels = make(map[string]string, len(labels))
+			}
+			m.labels[k] = labels.Get(k)
+		}
+	}
+	return m
+}
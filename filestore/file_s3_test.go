@@ -0,0 +1,411 @@
+package filestore
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/criyle/go-judge/envexec"
+)
+
+const fakeS3Bucket = "judge"
+
+type fakeS3Object struct {
+	content  []byte
+	meta     http.Header // x-amz-meta-* headers
+	modified time.Time
+}
+
+// fakeS3 is a MinIO-style stand-in serving the path-style requests used by
+// the S3 file store
+type fakeS3 struct {
+	mu       sync.Mutex
+	objects  map[string]*fakeS3Object
+	gets     map[string]int // number of GET requests by key
+	requests int            // number of object requests
+	deny     bool           // respond access denied to all object requests
+}
+
+func newFakeS3(t *testing.T) (*fakeS3, S3Config) {
+	f := &fakeS3{objects: make(map[string]*fakeS3Object), gets: make(map[string]int)}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	u, _ := url.Parse(srv.URL)
+	return f, S3Config{
+		Endpoint:  u.Host,
+		Bucket:    fakeS3Bucket,
+		Prefix:    "test/",
+		Region:    "us-east-1",
+		AccessKey: "access",
+		SecretKey: "secret",
+		Insecure:  true,
+	}
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucket != fakeS3Bucket {
+		fakeS3Error(w, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+	if key == "" {
+		switch r.Method {
+		case http.MethodHead:
+		case http.MethodGet:
+			f.list(w, r.URL.Query().Get("prefix"))
+		default:
+			fakeS3Error(w, http.StatusNotImplemented, "NotImplemented")
+		}
+		return
+	}
+	f.requests++
+	if f.deny {
+		fakeS3Error(w, http.StatusForbidden, "AccessDenied")
+		return
+	}
+
+	o := f.objects[key]
+	switch r.Method {
+	case http.MethodPut:
+		if src := r.Header.Get("X-Amz-Copy-Source"); src != "" {
+			src, _ = url.PathUnescape(src)
+			_, srcKey, _ := strings.Cut(strings.TrimPrefix(src, "/"), "/")
+			so, ok := f.objects[srcKey]
+			if !ok {
+				fakeS3Error(w, http.StatusNotFound, "NoSuchKey")
+				return
+			}
+			f.objects[key] = &fakeS3Object{content: so.content, meta: fakeS3Meta(r.Header), modified: time.Now()}
+			fmt.Fprintf(w, `<CopyObjectResult><LastModified>%s</LastModified><ETag>"etag"</ETag></CopyObjectResult>`,
+				time.Now().UTC().Format(time.RFC3339))
+			return
+		}
+		content, err := fakeS3Body(r)
+		if err != nil {
+			fakeS3Error(w, http.StatusBadRequest, "IncompleteBody")
+			return
+		}
+		f.objects[key] = &fakeS3Object{content: content, meta: fakeS3Meta(r.Header), modified: time.Now()}
+		w.Header().Set("ETag", `"etag"`)
+	case http.MethodHead, http.MethodGet:
+		if o == nil {
+			fakeS3Error(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		for k, v := range o.meta {
+			w.Header()[k] = v
+		}
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set("Last-Modified", o.modified.UTC().Format(http.TimeFormat))
+		w.Header().Set("Content-Length", strconv.Itoa(len(o.content)))
+		if r.Method == http.MethodGet {
+			f.gets[key]++
+			w.Write(o.content)
+		}
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		fakeS3Error(w, http.StatusNotImplemented, "NotImplemented")
+	}
+}
+
+func (f *fakeS3) list(w http.ResponseWriter, prefix string) {
+	type content struct {
+		Key          string
+		LastModified string
+		ETag         string
+		Size         int
+	}
+	type result struct {
+		XMLName     xml.Name `xml:"ListBucketResult"`
+		Name        string
+		Prefix      string
+		KeyCount    int
+		MaxKeys     int
+		IsTruncated bool
+		Contents    []content
+	}
+	ret := result{Name: fakeS3Bucket, Prefix: prefix, MaxKeys: 1000}
+	for k, o := range f.objects {
+		if strings.HasPrefix(k, prefix) {
+			ret.Contents = append(ret.Contents, content{
+				Key:          k,
+				LastModified: o.modified.UTC().Format(time.RFC3339),
+				ETag:         `"etag"`,
+				Size:         len(o.content),
+			})
+		}
+	}
+	ret.KeyCount = len(ret.Contents)
+	xml.NewEncoder(w).Encode(ret)
+}
+
+func fakeS3Error(w http.ResponseWriter, status int, code string) {
+	w.WriteHeader(status)
+	fmt.Fprintf(w, `<Error><Code>%s</Code></Error>`, code)
+}
+
+func fakeS3Meta(h http.Header) http.Header {
+	meta := make(http.Header)
+	for k, v := range h {
+		if strings.HasPrefix(k, "X-Amz-Meta-") {
+			meta[k] = v
+		}
+	}
+	return meta
+}
+
+// fakeS3Body decodes the body, which is aws-chunked for streaming signature
+func fakeS3Body(r *http.Request) ([]byte, error) {
+	if !strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+		return io.ReadAll(r.Body)
+	}
+	var buf bytes.Buffer
+	br := bufio.NewReader(r.Body)
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, _, _ := strings.Cut(strings.TrimSpace(line), ";")
+		n, err := strconv.ParseInt(size, 16, 64)
+		if err != nil {
+			return nil, err
+		}
+		if n == 0 {
+			return buf.Bytes(), nil
+		}
+		if _, err := io.CopyN(&buf, br, n); err != nil {
+			return nil, err
+		}
+		if _, err := br.Discard(2); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func newTestS3Store(t *testing.T, conf S3Config) *fileS3Store {
+	t.Helper()
+	fs, err := NewFileS3Store(t.TempDir(), conf)
+	if err != nil {
+		t.Fatalf("Failed to create s3 file store: %v", err)
+	}
+	return fs.(*fileS3Store)
+}
+
+func readFile(t *testing.T, fs FileStore, id string) string {
+	t.Helper()
+	_, f := fs.Get(id)
+	if f == nil {
+		t.Fatalf("%s: expected exist", id)
+	}
+	b, err := os.ReadFile(f.(*envexec.FileInput).Path)
+	if err != nil {
+		t.Fatalf("%s: read: %v", id, err)
+	}
+	return string(b)
+}
+
+func TestFileS3StoreShared(t *testing.T) {
+	fake, conf := newFakeS3(t)
+	a := newTestS3Store(t, conf)
+	b := newTestS3Store(t, conf)
+
+	id := addFile(t, a, "a.txt", "content")
+	if got := readFile(t, b, id); got != "content" {
+		t.Fatalf("Get: expected content, got %q", got)
+	}
+	if !b.SetLabels(id, map[string]string{"k": "v"}) {
+		t.Fatalf("SetLabels: expected true")
+	}
+	m, ok := a.Stat(id)
+	if !ok || m.Name != "a.txt" || m.Size != 7 || m.Labels["k"] != "v" || m.SHA256 == "" {
+		t.Fatalf("Stat: unexpected %+v, %v", m, ok)
+	}
+	if names := b.List(); len(names) != 1 {
+		t.Fatalf("List: unexpected %v", names)
+	}
+
+	// object removed by other node is removed from the cache once checked
+	b.checkInterval = 0
+	if !a.Remove(id) {
+		t.Fatalf("Remove: expected true")
+	}
+	if _, f := b.Get(id); f != nil {
+		t.Fatalf("Get: expected removed")
+	}
+	if _, err := os.Stat(filepath.Join(b.dir, id)); !os.IsNotExist(err) {
+		t.Fatalf("expected cache removed, got %v", err)
+	}
+	if len(fake.objects) != 0 {
+		t.Fatalf("expected no objects, got %d", len(fake.objects))
+	}
+}
+
+func TestFileS3StoreFetchOnce(t *testing.T) {
+	fake, conf := newFakeS3(t)
+	a := newTestS3Store(t, conf)
+	b := newTestS3Store(t, conf)
+	id := addFile(t, a, "a.txt", "content")
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, f := b.Get(id); f == nil {
+				t.Errorf("Get: expected exist")
+			}
+		}()
+	}
+	wg.Wait()
+	if n := fake.gets[conf.Prefix+id]; n != 1 {
+		t.Fatalf("expected object downloaded once, got %d", n)
+	}
+
+	// the cached copy is kept when the storage fails other than not found
+	b.checkInterval = 0
+	fake.mu.Lock()
+	fake.deny = true
+	fake.mu.Unlock()
+	if _, f := b.Get(id); f != nil {
+		t.Fatalf("Get: expected failure when storage fails")
+	}
+	if _, err := os.Stat(filepath.Join(b.dir, id)); err != nil {
+		t.Fatalf("expected cache kept, got %v", err)
+	}
+}
+
+func TestFileS3StoreBundle(t *testing.T) {
+	_, conf := newFakeS3(t)
+	a := newTestS3Store(t, conf)
+	b := newTestS3Store(t, conf)
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for name, content := range map[string]string{"a.txt": "a", "d/b.txt": "bb"} {
+		tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		tw.Write([]byte(content))
+	}
+	tw.Close()
+	id, err := AddBundle(a, "bundle", bytes.NewReader(buf.Bytes()), int64(buf.Len()), BundleLimit{})
+	if err != nil {
+		t.Fatalf("AddBundle: %v", err)
+	}
+
+	if _, f := b.Get(id); f == nil {
+		t.Fatalf("Get: expected exist")
+	}
+	got, err := os.ReadFile(filepath.Join(b.dir, id, "d", "b.txt"))
+	if err != nil || string(got) != "bb" {
+		t.Fatalf("expected bundle extracted, got %q, %v", got, err)
+	}
+	if m, ok := b.Stat(id); !ok || !m.Bundle || m.Size != 3 {
+		t.Fatalf("Stat: unexpected %+v, %v", m, ok)
+	}
+
+	// the downloaded bundle is extracted within the limit
+	conf.BundleLimit = BundleLimit{MaxEntries: 1}
+	c := newTestS3Store(t, conf)
+	if _, f := c.Get(id); f != nil {
+		t.Fatalf("Get: expected bundle exceeding the limit rejected")
+	}
+}
+
+func TestFileS3StoreCheckInterval(t *testing.T) {
+	fake, conf := newFakeS3(t)
+	a := newTestS3Store(t, conf)
+	b := newTestS3Store(t, conf)
+
+	requests := func() int {
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+		return fake.requests
+	}
+	n := requests()
+	f, err := a.New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	f.Close()
+	if requests() != n {
+		t.Fatalf("New: expected no request")
+	}
+
+	id := addFile(t, a, "a.txt", "content")
+	readFile(t, b, id)
+	n = requests()
+	for range 3 {
+		readFile(t, a, id)
+		readFile(t, b, id)
+	}
+	if requests() != n {
+		t.Fatalf("Get: expected local copies served without request, got %d", requests()-n)
+	}
+}
+
+func TestFileS3StoreCacheEvict(t *testing.T) {
+	fake, conf := newFakeS3(t)
+	a := newTestS3Store(t, conf)
+	conf.CacheMaxSize = 8
+	b := newTestS3Store(t, conf)
+
+	ids := make([]string, 3)
+	for i := range ids {
+		ids[i] = addFile(t, a, "a.txt", strconv.Itoa(i)+"abc")
+	}
+	cached := func(id string) bool {
+		_, err := os.Stat(filepath.Join(b.dir, id))
+		return err == nil
+	}
+
+	// least recently used copies are evicted except leased
+	readFile(t, b, ids[0])
+	readFile(t, b, ids[1])
+	Lease(b, ids[0])
+	readFile(t, b, ids[2])
+	for i, exist := range []bool{true, false, true} {
+		if cached(ids[i]) != exist {
+			t.Fatalf("%d: expected cached %v", i, exist)
+		}
+	}
+	Release(b, ids[0])
+	readFile(t, b, ids[1])
+	for i, exist := range []bool{false, true, true} {
+		if cached(ids[i]) != exist {
+			t.Fatalf("%d: expected cached %v after released", i, exist)
+		}
+	}
+	if n := fake.gets[conf.Prefix+ids[1]]; n != 2 {
+		t.Fatalf("expected evicted copy downloaded again, got %d", n)
+	}
+	if b.cacheSize != 8 {
+		t.Fatalf("expected cache size 8, got %d", b.cacheSize)
+	}
+
+	// local copies are counted on reopen
+	fs, err := NewFileS3Store(b.dir, conf)
+	if err != nil {
+		t.Fatalf("Failed to reopen s3 file store: %v", err)
+	}
+	if size := fs.(*fileS3Store).cacheSize; size != 8 {
+		t.Fatalf("expected cache size 8 on reopen, got %d", size)
+	}
+}
@@ -0,0 +1,157 @@
+package filestore
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	tmpDir    = ".tmp"   // directory for files created by New but not yet added
+	indexFile = ".index" // journal of the file store index
+
+	// compact the journal when the records exceeds the live entries by
+	minCompactRecords = 1024
+)
+
+// indexRecord is a line in the journal, the last record of an id wins
+type indexRecord struct {
+	ID       string            `json:"id"`
+	Delete   bool              `json:"delete,omitempty"`
+	Name     string            `json:"name,omitempty"`
+	Size     int64             `json:"size,omitzero"`
+	SHA256   string            `json:"sha256,omitempty"`
+	Created  time.Time         `json:"created,omitzero"`
+	Accessed time.Time         `json:"accessed,omitzero"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Refs     int               `json:"refs,omitempty"`
+	Bundle   bool              `json:"bundle,omitempty"`
+}
+
+// index persists the file store metadata as an append only journal in the
+// store directory, it is compacted on open, on flush and when the journal
+// grows. Access time is not journaled for every read and only persisted by
+// compaction.
+type index struct {
+	path    string
+	f       *os.File
+	records int
+}
+
+// openIndex replays the journal in dir and returns the live records
+func openIndex(dir string) (*index, map[string]indexRecord, error) {
+	p := filepath.Join(dir, indexFile)
+	records := make(map[string]indexRecord)
+	f, err := os.Open(p)
+	switch {
+	case err == nil:
+		s := bufio.NewScanner(f)
+		s.Buffer(nil, 1<<20)
+		for s.Scan() {
+			var r indexRecord
+			if err := json.Unmarshal(s.Bytes(), &r); err != nil || r.ID == "" {
+				continue // skip the partially written record
+			}
+			if r.Delete {
+				delete(records, r.ID)
+			} else {
+				records[r.ID] = r
+			}
+		}
+		f.Close()
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, nil, err
+	}
+	return &index{path: p}, records, nil
+}
+
+// compact rewrites the journal with the live records
+func (i *index) compact(records map[string]indexRecord) error {
+	tmp := i.path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, r := range records {
+		if err := enc.Encode(r); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, i.path); err != nil {
+		return err
+	}
+	if i.f != nil {
+		i.f.Close()
+	}
+	i.f, err = os.OpenFile(i.path, os.O_WRONLY|os.O_APPEND, 0o644)
+	i.records = len(records)
+	return err
+}
+
+// flush compacts the journal to persist the access time kept in memory
+func (i *index) flush(live func() map[string]indexRecord) error {
+	if i == nil || i.f == nil {
+		return nil
+	}
+	return i.compact(live())
+}
+
+// write appends the record, the journal is compacted with live records when
+// it grows too large compared to the live count
+func (i *index) write(r indexRecord, count int, live func() map[string]indexRecord) error {
+	if i == nil || i.f == nil {
+		return nil
+	}
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	if _, err := i.f.Write(append(b, '\n')); err != nil {
+		return err
+	}
+	i.records++
+	if i.records > 2*count+minCompactRecords {
+		return i.compact(live())
+	}
+	return nil
+}
+
+func newIndexRecord(id string, m *fileMeta, refs int) indexRecord {
+	return indexRecord{
+		ID:       id,
+		Name:     m.name,
+		Size:     m.size,
+		SHA256:   m.sha256,
+		Created:  m.created,
+		Accessed: m.accessedAt(),
+		Labels:   m.labels,
+		Refs:     refs,
+		Bundle:   m.bundle,
+	}
+}
+
+func (r *indexRecord) fileMeta() *fileMeta {
+	m := &fileMeta{
+		name:    r.Name,
+		size:    r.Size,
+		sha256:  r.SHA256,
+		created: r.Created,
+		labels:  r.Labels,
+		bundle:  r.Bundle,
+	}
+	m.touch(r.Accessed)
+	return m
+}
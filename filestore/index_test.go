@@ -0,0 +1,118 @@
+package filestore
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestFileLocalStoreIndexReplay(t *testing.T) {
+	dir := t.TempDir()
+	fs := NewFileLocalStore(dir)
+	a := addFile(t, fs, "a.txt", "a")
+	b := addFile(t, fs, "b.txt", "b")
+	fs.SetLabels(a, map[string]string{"k": "v"})
+	fs.Remove(b)
+	time.Sleep(time.Millisecond)
+	fs.Get(a)
+	accessed, _ := fs.Stat(a)
+	if err := Flush(fs); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	// file created by New but not added is removed on reopen
+	orphan, err := fs.New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	orphan.Close()
+
+	fs = NewFileLocalStore(dir)
+	m, ok := fs.Stat(a)
+	if !ok || m.Name != "a.txt" || m.Labels["k"] != "v" || m.Size != 1 || !m.Accessed.Equal(accessed.Accessed) {
+		t.Fatalf("Stat: unexpected %+v, %v", m, ok)
+	}
+	if _, ok := fs.Stat(b); ok {
+		t.Fatalf("%s: expected removed", b)
+	}
+	if _, err := os.Stat(orphan.Name()); !os.IsNotExist(err) {
+		t.Fatalf("%s: expected removed, got %v", orphan.Name(), err)
+	}
+}
+
+func TestFileContentStoreIndexReplay(t *testing.T) {
+	dir := t.TempDir()
+	fs, err := NewFileContentStore(dir)
+	if err != nil {
+		t.Fatalf("Failed to create file store: %v", err)
+	}
+	a := addFile(t, fs, "a.txt", "a")
+	addFile(t, fs, "a.txt", "a")
+	orphan, err := fs.New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	orphan.Close()
+
+	fs, err = NewFileContentStore(dir)
+	if err != nil {
+		t.Fatalf("Failed to reopen file store: %v", err)
+	}
+	if m, ok := fs.Stat(a); !ok || m.Refs != 2 || m.Name != "a.txt" {
+		t.Fatalf("Stat: unexpected %+v, %v", m, ok)
+	}
+	if _, err := os.Stat(orphan.Name()); !os.IsNotExist(err) {
+		t.Fatalf("%s: expected removed, got %v", orphan.Name(), err)
+	}
+}
+
+func TestIndexCompaction(t *testing.T) {
+	dir := t.TempDir()
+	fs := NewFileLocalStore(dir)
+	a := addFile(t, fs, "a.txt", "a")
+	n := 2 * minCompactRecords
+	for i := range n {
+		fs.SetLabels(a, map[string]string{"i": strconv.Itoa(i)})
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, indexFile))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if lines := bytes.Count(b, []byte("\n")); lines > minCompactRecords+2 {
+		t.Fatalf("expected journal compacted, got %d lines", lines)
+	}
+	if _, err := os.Stat(filepath.Join(dir, indexFile+".tmp")); !os.IsNotExist(err) {
+		t.Fatalf("expected no temporary journal, got %v", err)
+	}
+
+	fs = NewFileLocalStore(dir)
+	if m, ok := fs.Stat(a); !ok || m.Labels["i"] != strconv.Itoa(n-1) {
+		t.Fatalf("Stat: unexpected %+v, %v", m, ok)
+	}
+}
+
+func TestFileLocalStoreAddRollback(t *testing.T) {
+	fs := NewFileLocalStore(t.TempDir())
+	f, err := fs.New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	f.Close()
+
+	// fail the journal write
+	fs.(*fileLocalStore).index.f.Close()
+	if _, err := fs.Add("a.txt", f.Name()); err == nil {
+		t.Fatalf("Add: expected error")
+	}
+	id := filepath.Base(f.Name())
+	if _, ok := fs.Stat(id); ok {
+		t.Fatalf("%s: expected rolled back", id)
+	}
+	if _, err := os.Stat(f.Name()); err != nil {
+		t.Fatalf("%s: expected restored, got %v", f.Name(), err)
+	}
+}
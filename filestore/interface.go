@@ -5,19 +5,107 @@ import (
 	"errors"
 	"math/rand/v2"
 	"os"
+	"strings"
 
 	"github.com/criyle/go-judge/envexec"
 )
 
 var errUniqueIDNotGenerated = errors.New("unique id does not exists after tried 50 times")
 
+// ErrNotContentAddressed is returned when adding reference to a file store
+// that is not content addressed
+var ErrNotContentAddressed = errors.New("file store is not content addressed")
+
+// ErrStoreFull is returned when the bounded file store is full of pinned or
+// leased files
+var ErrStoreFull = errors.New("file store is full of pinned or leased files")
+
 // FileStore defines interface to store file
 type FileStore interface {
-	Add(name, path string) (string, error) // Add creates a file with path to the storage, returns id
-	Remove(string) bool                    // Remove deletes a file by id
-	Get(string) (string, envexec.File)     // Get file by id, nil if not exists
-	List() map[string]string               // List return all file ids to original name
-	New() (*os.File, error)                // Create a temporary file to the file store, can be added through Add to save it
+	Add(name, path string) (string, error)    // Add creates a file with path to the storage, returns id
+	Remove(string) bool                       // Remove deletes a file by id
+	Get(string) (string, envexec.File)        // Get file by id, nil if not exists
+	List() map[string]string                  // List return all file ids to original name
+	New() (*os.File, error)                   // Create a temporary file to the file store, can be added through Add to save it
+	Stat(string) (FileMeta, bool)             // Stat returns the metadata of the file by id, false if not exists
+	SetLabels(string, map[string]string) bool // SetLabels adds user labels to the file by id, false if not exists
+}
+
+// RefFileStore is a content-addressed FileStore that file ids are the SHA-256
+// digests of the content. Identical content is stored once and each Add or
+// AddRef needs a Remove to delete the file.
+type RefFileStore interface {
+	FileStore
+	AddRef(string) (bool, error) // AddRef adds a reference to the existing file by id, false if not exists
+}
+
+// AddRef adds a reference to the existing file by id, so that the client
+// knows the digest can skip the upload. It returns false if the file does not
+// exist and ErrNotContentAddressed if the file store is not content addressed.
+func AddRef(fs FileStore, id string) (bool, error) {
+	rs, ok := fs.(RefFileStore)
+	if !ok {
+		return false, ErrNotContentAddressed
+	}
+	return rs.AddRef(id)
+}
+
+// Pinner is a FileStore that can pin files to never be evicted
+type Pinner interface {
+	Pin(string) bool // Pin marks the file by id to be never evicted, false if not exists
+}
+
+// Pin pins the file by id if the file store evicts files, it returns false if
+// the file store does not evict or the file does not exist. Pinned files are
+// only removed by Remove.
+func Pin(fs FileStore, id string) bool {
+	p, ok := fs.(Pinner)
+	return ok && p.Pin(id)
+}
+
+// Flusher is a FileStore that keeps part of the metadata (e.g. access time)
+// in memory and persists it lazily
+type Flusher interface {
+	Flush() error // Flush persists the metadata kept in memory
+}
+
+// Flush persists the metadata kept in memory if the file store does, it
+// should be called before shutdown
+func Flush(fs FileStore) error {
+	if f, ok := fs.(Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+// Leaser is a FileStore that leases files, leased files are not removed by
+// expiry or eviction while in use by requests
+type Leaser interface {
+	Lease(string) bool // Lease takes a lease on the file by id, false if not exists
+	Release(string)    // Release releases a lease taken by Lease
+}
+
+// Lease takes a lease on the file by id if the file store supports, it
+// returns false if the file does not exist
+func Lease(fs FileStore, id string) bool {
+	if l, ok := fs.(Leaser); ok {
+		return l.Lease(id)
+	}
+	_, ok := fs.Stat(id)
+	return ok
+}
+
+// Release releases a lease taken by Lease
+func Release(fs FileStore, id string) {
+	if l, ok := fs.(Leaser); ok {
+		l.Release(id)
+	}
+}
+
+// validID returns whether the id names an entry in the store directory, ids
+// with path separators or naming hidden entries of the store are rejected
+func validID(id string) bool {
+	return id != "" && !strings.HasPrefix(id, ".") && !strings.ContainsAny(id, `/\`)
 }
 
 func generateID() (string, error) {
@@ -0,0 +1,261 @@
+package filestore
+
+import (
+	"container/list"
+	"os"
+	"slices"
+	"sync"
+
+	"github.com/criyle/go-judge/envexec"
+)
+
+var _ RefFileStore = &LRU{}
+
+// LRU is a file store bounded by total size and file count, the least
+// recently used files are evicted to make room for new files except pinned
+// and leased files
+type LRU struct {
+	mu sync.Mutex
+	FileStore
+	maxSize  int64
+	maxCount int
+	size     int64
+	files    *list.List // front is the most recently used
+	idToElem map[string]*list.Element
+}
+
+type lruFile struct {
+	id     string
+	size   int64
+	refs   int // number of references for content-addressed file store
+	leases int // number of leases taken through the LRU
+	pinned bool
+}
+
+// NewLRU creates a file store bounded by maxSize bytes and maxCount files,
+// zero means unlimited. Existing files are counted from the file store and
+// ordered by the last access time in the metadata.
+func NewLRU(fs FileStore, maxSize int64, maxCount int) FileStore {
+	l := &LRU{
+		FileStore: fs,
+		maxSize:   maxSize,
+		maxCount:  maxCount,
+		files:     list.New(),
+		idToElem:  make(map[string]*list.Element),
+	}
+	type existing struct {
+		id string
+		FileMeta
+	}
+	var files []existing
+	for id := range fs.List() {
+		if m, ok := fs.Stat(id); ok {
+			files = append(files, existing{id, m})
+		}
+	}
+	slices.SortFunc(files, func(a, b existing) int {
+		return b.Accessed.Compare(a.Accessed)
+	})
+	for _, f := range files {
+		l.idToElem[f.id] = l.files.PushBack(&lruFile{id: f.id, size: f.Size, refs: f.Refs})
+		l.size += f.Size
+	}
+	return l
+}
+
+func (l *LRU) New() (*os.File, error) {
+	l.mu.Lock()
+	// make room for at least one file with some content
+	ok := l.evict(1, 1, "")
+	l.mu.Unlock()
+	if !ok {
+		return nil, ErrStoreFull
+	}
+	return l.FileStore.New()
+}
+
+func (l *LRU) Add(name, path string) (string, error) {
+	size := pathSize(path)
+	id, err := l.FileStore.Add(name, path)
+	if err != nil {
+		return "", err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if e, ok := l.idToElem[id]; ok {
+		e.Value.(*lruFile).refs++
+		l.files.MoveToFront(e)
+		return id, nil
+	}
+	l.idToElem[id] = l.files.PushFront(&lruFile{id: id, size: size, refs: 1})
+	l.size += size
+	if !l.evict(0, 0, id) {
+		l.remove(l.idToElem[id])
+		return "", ErrStoreFull
+	}
+	return id, nil
+}
+
+// AddRef implements RefFileStore if the underlying file store does
+func (l *LRU) AddRef(id string) (bool, error) {
+	ok, err := AddRef(l.FileStore, id)
+	if !ok {
+		return false, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if e, ok := l.idToElem[id]; ok {
+		e.Value.(*lruFile).refs++
+		l.files.MoveToFront(e)
+	}
+	return true, nil
+}
+
+// Lease takes a lease in the underlying file store, leased files are not
+// evicted until released. The lease is taken under the lock so that the file
+// is not evicted in between.
+func (l *LRU) Lease(id string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e, ok := l.idToElem[id]
+	if !ok || !Lease(l.FileStore, id) {
+		return false
+	}
+	e.Value.(*lruFile).leases++
+	return true
+}
+
+// Release releases a lease taken by Lease
+func (l *LRU) Release(id string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	Release(l.FileStore, id)
+	if e, ok := l.idToElem[id]; ok {
+		if f := e.Value.(*lruFile); f.leases > 0 {
+			f.leases--
+		}
+	}
+}
+
+// Flush implements Flusher by the underlying file store
+func (l *LRU) Flush() error {
+	return Flush(l.FileStore)
+}
+
+// Pin marks the file to be never evicted, it is removed only by Remove
+func (l *LRU) Pin(id string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e, ok := l.idToElem[id]
+	if !ok {
+		return false
+	}
+	e.Value.(*lruFile).pinned = true
+	return true
+}
+
+func (l *LRU) Get(id string) (string, envexec.File) {
+	name, file := l.FileStore.Get(id)
+	if file == nil {
+		return name, file
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if e, ok := l.idToElem[id]; ok {
+		l.files.MoveToFront(e)
+	}
+	return name, file
+}
+
+func (l *LRU) Remove(id string) bool {
+	success := l.FileStore.Remove(id)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e, ok := l.idToElem[id]
+	if !ok {
+		return success
+	}
+	f := e.Value.(*lruFile)
+	f.refs--
+	if f.refs <= 0 {
+		l.files.Remove(e)
+		delete(l.idToElem, id)
+		l.size -= f.size
+	}
+	return success
+}
+
+// evict removes the least recently used files that are not pinned until the
+// store has room for extra size and count, except the file with id keep.
+// It returns false without evicting if there is not enough room even after
+// all evictable files are evicted, caller should hold the lock.
+func (l *LRU) evict(size int64, count int, keep string) bool {
+	if !l.full(size, count) {
+		return true
+	}
+	var (
+		keptSize  int64
+		keptCount int
+	)
+	for e := l.files.Front(); e != nil; e = e.Next() {
+		if f := e.Value.(*lruFile); !l.evictable(f, keep) {
+			keptSize += f.size
+			keptCount++
+		}
+	}
+	if l.exceeds(keptSize+size, keptCount+count) {
+		return false
+	}
+
+	e := l.files.Back()
+	for l.full(size, count) {
+		for e != nil && !l.evictable(e.Value.(*lruFile), keep) {
+			e = e.Prev()
+		}
+		if e == nil {
+			return false
+		}
+		prev := e.Prev()
+		l.remove(e)
+		e = prev
+	}
+	return true
+}
+
+// evictable returns whether the file can be evicted, pinned and leased files
+// are kept
+func (l *LRU) evictable(f *lruFile, keep string) bool {
+	return !f.pinned && f.leases == 0 && f.id != keep
+}
+
+// full returns whether the store has no room for extra size and count
+func (l *LRU) full(size int64, count int) bool {
+	return l.exceeds(l.size+size, len(l.idToElem)+count)
+}
+
+// exceeds returns whether the total size or count exceeds the limits
+func (l *LRU) exceeds(size int64, count int) bool {
+	return (l.maxSize > 0 && size > l.maxSize) || (l.maxCount > 0 && count > l.maxCount)
+}
+
+// remove removes the file with all its references, caller should hold the lock
+func (l *LRU) remove(e *list.Element) {
+	f := e.Value.(*lruFile)
+	for range f.refs {
+		l.FileStore.Remove(f.id)
+	}
+	l.files.Remove(e)
+	delete(l.idToElem, f.id)
+	l.size -= f.size
+}
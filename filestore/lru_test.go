@@ -0,0 +1,138 @@
+package filestore
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLRUEvictionOrder(t *testing.T) {
+	fs := NewLRU(NewFileLocalStore(t.TempDir()), 0, 2)
+	a := addFile(t, fs, "a", "a")
+	b := addFile(t, fs, "b", "b")
+	fs.Get(a) // a is more recently used than b
+	c := addFile(t, fs, "c", "c")
+
+	tests := []struct {
+		id    string
+		exist bool
+	}{
+		{a, true},
+		{b, false},
+		{c, true},
+	}
+	for _, tc := range tests {
+		if _, f := fs.Get(tc.id); (f != nil) != tc.exist {
+			t.Fatalf("%s: expected exist %v", tc.id, tc.exist)
+		}
+	}
+}
+
+func TestLRUPinAndLease(t *testing.T) {
+	fs := NewLRU(NewFileLocalStore(t.TempDir()), 0, 2)
+	a := addFile(t, fs, "a", "a")
+	b := addFile(t, fs, "b", "b")
+	if !Pin(fs, a) {
+		t.Fatalf("Pin: expected true")
+	}
+	if !Lease(fs, b) {
+		t.Fatalf("Lease: expected true")
+	}
+	if _, err := fs.New(); !errors.Is(err, ErrStoreFull) {
+		t.Fatalf("New: expected ErrStoreFull, got %v", err)
+	}
+
+	Release(fs, b)
+	c := addFile(t, fs, "c", "c")
+	for id, exist := range map[string]bool{a: true, b: false, c: true} {
+		if _, f := fs.Get(id); (f != nil) != exist {
+			t.Fatalf("%s: expected exist %v", id, exist)
+		}
+	}
+}
+
+func TestLRUNewFullBySize(t *testing.T) {
+	fs := NewLRU(NewFileLocalStore(t.TempDir()), 3, 0)
+	a := addFile(t, fs, "a", "abc")
+	Pin(fs, a)
+	if _, err := fs.New(); !errors.Is(err, ErrStoreFull) {
+		t.Fatalf("New: expected ErrStoreFull, got %v", err)
+	}
+	fs.Remove(a)
+	addFile(t, fs, "b", "abc")
+}
+
+func TestLRUContentRefs(t *testing.T) {
+	cs, err := NewFileContentStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create file store: %v", err)
+	}
+	fs := NewLRU(cs, 0, 1)
+	a := addFile(t, fs, "a", "a")
+	addFile(t, fs, "a", "a")
+
+	// both references are removed on eviction
+	addFile(t, fs, "b", "b")
+	if _, f := cs.Get(a); f != nil {
+		t.Fatalf("%s: expected evicted with all references", a)
+	}
+}
+
+func TestLRUAddNoRoom(t *testing.T) {
+	fs := NewLRU(NewFileLocalStore(t.TempDir()), 4, 0)
+	a := addFile(t, fs, "a", "abc")
+	b := addFile(t, fs, "b", "d")
+	Pin(fs, a)
+
+	// files are not evicted when the new file does not fit anyway, the file is
+	// created in the underlying store since New makes room for it
+	for _, content := range []string{"efghi", "ef"} {
+		f, err := fs.(*LRU).FileStore.New()
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+		f.WriteString(content)
+		f.Close()
+		if _, err := fs.Add("c", f.Name()); !errors.Is(err, ErrStoreFull) {
+			t.Fatalf("Add %q: expected ErrStoreFull, got %v", content, err)
+		}
+		for _, id := range []string{a, b} {
+			if _, ok := fs.Stat(id); !ok {
+				t.Fatalf("Add %q: %s: expected not evicted", content, id)
+			}
+		}
+	}
+}
+
+func TestLRULeaseEvict(t *testing.T) {
+	s := &leaseHookStore{FileStore: NewFileLocalStore(t.TempDir()), hook: func() {}}
+	fs := NewLRU(s, 0, 1)
+	a := addFile(t, fs, "a", "a")
+
+	// eviction runs concurrently while the lease is being taken
+	done := make(chan struct{})
+	s.hook = func() {
+		go func() {
+			fs.New()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	if !Lease(fs, a) {
+		t.Fatalf("Lease: expected true")
+	}
+	<-done
+	if _, ok := fs.Stat(a); !ok {
+		t.Fatalf("%s: expected leased file not evicted", a)
+	}
+
+	s.hook = func() {}
+	Release(fs, a)
+	addFile(t, fs, "b", "b")
+	if Lease(fs, a) {
+		t.Fatalf("%s: expected lease to fail after evicted", a)
+	}
+}
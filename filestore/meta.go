@@ -0,0 +1,161 @@
+package filestore
+
+import (
+	"maps"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// FileMeta defines the metadata of a file in the file store
+type FileMeta struct {
+	Name     string            // original name
+	Size     int64             // size in bytes
+	SHA256   string            // hex encoded SHA-256 digest of the content
+	Created  time.Time         // time the file was added
+	Accessed time.Time         // time the file was last read through Get
+	Labels   map[string]string // user labels
+	Refs     int               // number of references (1 if not content addressed)
+	Leases   int               // number of requests using the file
+	Bundle   bool              // directory tree copied in as a whole
+}
+
+// Filter defines the conditions to filter files by metadata
+type Filter struct {
+	Labels    map[string]string // files should have all the labels
+	OlderThan time.Duration     // files created before the duration ago
+	NewerThan time.Duration     // files created within the duration
+}
+
+// Match returns whether the file matches all the conditions
+func (f *Filter) Match(m FileMeta, now time.Time) bool {
+	for k, v := range f.Labels {
+		if l, ok := m.Labels[k]; !ok || l != v {
+			return false
+		}
+	}
+	if f.OlderThan > 0 && now.Sub(m.Created) < f.OlderThan {
+		return false
+	}
+	if f.NewerThan > 0 && now.Sub(m.Created) > f.NewerThan {
+		return false
+	}
+	return true
+}
+
+// ListMeta returns the metadata of all files in the file store that matches
+// the filter
+func ListMeta(fs FileStore, f *Filter) map[string]FileMeta {
+	now := time.Now()
+	ret := make(map[string]FileMeta)
+	for id := range fs.List() {
+		m, ok := fs.Stat(id)
+		if !ok {
+			continue
+		}
+		if f == nil || f.Match(m, now) {
+			ret[id] = m
+		}
+	}
+	return ret
+}
+
+// fileMeta is the metadata kept by the file store, the size and digest are
+// filled when the file is added or first stated if it was not added
+type fileMeta struct {
+	name     string
+	size     int64
+	sha256   string
+	created  time.Time
+	accessed atomic.Int64 // unix nano, updated by Get under the read lock
+	labels   map[string]string
+	leases   int // not persisted
+	bundle   bool
+}
+
+func newFileMeta(name string) *fileMeta {
+	now := time.Now()
+	m := &fileMeta{name: name, size: -1, created: now}
+	m.touch(now)
+	return m
+}
+
+// touch sets the last access time
+func (m *fileMeta) touch(t time.Time) {
+	if t.IsZero() {
+		m.accessed.Store(0)
+		return
+	}
+	m.accessed.Store(t.UnixNano())
+}
+
+// accessedAt returns the last access time
+func (m *fileMeta) accessedAt() time.Time {
+	if n := m.accessed.Load(); n != 0 {
+		return time.Unix(0, n)
+	}
+	return time.Time{}
+}
+
+// stated returns whether the size and the digest are filled
+func (m *fileMeta) stated() bool {
+	return m.size >= 0 && m.sha256 != ""
+}
+
+// setStat fills the size and the digest, caller should hold the lock
+func (m *fileMeta) setStat(st fileStat) {
+	m.size, m.sha256, m.bundle = st.size, st.sha256, st.bundle
+}
+
+// fileStat is the size and the digest of a file or a bundle directory
+type fileStat struct {
+	size   int64
+	sha256 string
+	bundle bool
+}
+
+// statFile computes the size and the digest of the file or the bundle
+// directory by path. It reads the whole content, so it should be called
+// without holding the lock of the file store.
+func statFile(path string) (fileStat, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return fileStat{}, err
+	}
+	if fi.IsDir() {
+		d, size, err := dirDigest(path)
+		return fileStat{size: size, sha256: d, bundle: true}, err
+	}
+	d, err := fileDigest(path)
+	return fileStat{size: fi.Size(), sha256: d}, err
+}
+
+func (m *fileMeta) setLabels(labels map[string]string) {
+	if len(labels) == 0 {
+		return
+	}
+	if m.labels == nil {
+		m.labels = make(map[string]string, len(labels))
+	}
+	maps.Copy(m.labels, labels)
+}
+
+func (m *fileMeta) meta() FileMeta {
+	return FileMeta{
+		Name:     m.name,
+		Size:     m.size,
+		SHA256:   m.sha256,
+		Created:  m.created,
+		Accessed: m.accessedAt(),
+		Labels:   maps.Clone(m.labels),
+		Refs:     1,
+		Leases:   m.leases,
+		Bundle:   m.bundle,
+	}
+}
+
+func (m *fileMeta) release() {
+	if m.leases > 0 {
+		m.leases--
+	}
+}
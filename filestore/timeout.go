@@ -24,11 +24,15 @@ type Timeout struct {
 }
 
 type timeoutFile struct {
-	id   string
-	time time.Time
+	id     string
+	time   time.Time
+	refs   int  // number of references for content-addressed file store
+	leases int  // number of leases taken through the Timeout
+	pinned bool // pinned files never expire and are ordered last
 }
 
-// NewTimeout creates a timeout file system with maximum TTL for a file
+// NewTimeout creates a timeout file system with maximum TTL for a file, the
+// existing files expire by the last access time in the metadata
 func NewTimeout(fs FileStore, timeout time.Duration, checkInterval time.Duration) FileStore {
 	t := &Timeout{
 		FileStore: fs,
@@ -36,6 +40,11 @@ func NewTimeout(fs FileStore, timeout time.Duration, checkInterval time.Duration
 		files:     make([]timeoutFile, 0),
 		idToIndex: make(map[string]int),
 	}
+	for id := range fs.List() {
+		if m, ok := fs.Stat(id); ok {
+			heap.Push(t, timeoutFile{id: id, time: m.Accessed, refs: m.Refs})
+		}
+	}
 	go t.checkTimeoutLoop(checkInterval)
 	return t
 }
@@ -53,9 +62,17 @@ func (t *Timeout) checkTimeoutAndRemove() {
 	defer t.mu.Unlock()
 
 	now := time.Now()
-	for len(t.files) > 0 && t.files[0].time.Add(t.timeout).Before(now) {
+	for len(t.files) > 0 && !t.files[0].pinned && t.files[0].time.Add(t.timeout).Before(now) {
 		f := t.files[0]
-		t.FileStore.Remove(f.id)
+		// leased file expires after the lease is released
+		if f.leases > 0 {
+			t.files[0].time = now
+			heap.Fix(t, 0)
+			continue
+		}
+		for range f.refs {
+			t.FileStore.Remove(f.id)
+		}
 		heap.Pop(t)
 	}
 }
@@ -65,6 +82,9 @@ func (t *Timeout) Len() int {
 }
 
 func (t *Timeout) Less(i, j int) bool {
+	if t.files[i].pinned != t.files[j].pinned {
+		return t.files[j].pinned
+	}
 	return t.files[i].time.Before(t.files[j].time)
 }
 
@@ -97,12 +117,80 @@ func (t *Timeout) Add(name, path string) (string, error) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
-	f := timeoutFile{id, time.Now()}
-	heap.Push(t, f)
-
+	t.addRef(id)
 	return id, nil
 }
 
+// AddRef implements RefFileStore if the underlying file store does
+func (t *Timeout) AddRef(id string) (bool, error) {
+	ok, err := AddRef(t.FileStore, id)
+	if !ok {
+		return false, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.addRef(id)
+	return true, nil
+}
+
+// Lease takes a lease in the underlying file store, leased files do not
+// expire until released. The lease is taken under the lock so that the file
+// does not expire in between.
+func (t *Timeout) Lease(id string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	index, ok := t.idToIndex[id]
+	if !ok || !Lease(t.FileStore, id) {
+		return false
+	}
+	t.files[index].leases++
+	return true
+}
+
+// Release releases a lease taken by Lease
+func (t *Timeout) Release(id string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	Release(t.FileStore, id)
+	if index, ok := t.idToIndex[id]; ok && t.files[index].leases > 0 {
+		t.files[index].leases--
+	}
+}
+
+// Flush implements Flusher by the underlying file store
+func (t *Timeout) Flush() error {
+	return Flush(t.FileStore)
+}
+
+// Pin stops the file from expiring and pins it in the underlying file store
+func (t *Timeout) Pin(id string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	index, ok := t.idToIndex[id]
+	if !ok {
+		return false
+	}
+	Pin(t.FileStore, id)
+	t.files[index].pinned = true
+	heap.Fix(t, index)
+	return true
+}
+
+func (t *Timeout) addRef(id string) {
+	if index, ok := t.idToIndex[id]; ok {
+		t.files[index].time = time.Now()
+		t.files[index].refs++
+		heap.Fix(t, index)
+		return
+	}
+	heap.Push(t, timeoutFile{id: id, time: time.Now(), refs: 1})
+}
+
 func (t *Timeout) Remove(id string) bool {
 	success := t.FileStore.Remove(id)
 
@@ -113,7 +201,10 @@ func (t *Timeout) Remove(id string) bool {
 	if !ok {
 		return success
 	}
-	heap.Remove(t, index)
+	t.files[index].refs--
+	if t.files[index].refs <= 0 {
+		heap.Remove(t, index)
+	}
 	return success
 }
 
@@ -0,0 +1,88 @@
+package filestore
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeoutSkipLeased(t *testing.T) {
+	fs := NewTimeout(NewFileLocalStore(t.TempDir()), time.Millisecond, time.Hour)
+	a := addFile(t, fs, "a", "a")
+	b := addFile(t, fs, "b", "b")
+	c := addFile(t, fs, "c", "c")
+	Lease(fs, a)
+	Pin(fs, c)
+
+	time.Sleep(2 * time.Millisecond)
+	fs.(*Timeout).checkTimeoutAndRemove()
+	for id, exist := range map[string]bool{a: true, b: false, c: true} {
+		if _, ok := fs.Stat(id); ok != exist {
+			t.Fatalf("%s: expected exist %v", id, exist)
+		}
+	}
+
+	if !Lease(fs, c) {
+		t.Fatalf("%s: expected pinned file to be leased", c)
+	}
+	Release(fs, c)
+
+	// leased file expires after the lease is released
+	Release(fs, a)
+	time.Sleep(2 * time.Millisecond)
+	fs.(*Timeout).checkTimeoutAndRemove()
+	for id, exist := range map[string]bool{a: false, c: true} {
+		if _, ok := fs.Stat(id); ok != exist {
+			t.Fatalf("%s: expected exist %v", id, exist)
+		}
+	}
+}
+
+// leaseHookStore runs hook after the lease is taken in the file store
+type leaseHookStore struct {
+	FileStore
+	hook func()
+}
+
+func (s *leaseHookStore) Lease(id string) bool {
+	ok := Lease(s.FileStore, id)
+	s.hook()
+	return ok
+}
+
+func (s *leaseHookStore) Release(id string) {
+	Release(s.FileStore, id)
+}
+
+func TestTimeoutLeaseExpire(t *testing.T) {
+	s := &leaseHookStore{FileStore: NewFileLocalStore(t.TempDir()), hook: func() {}}
+	fs := NewTimeout(s, time.Nanosecond, time.Hour).(*Timeout)
+	id := addFile(t, fs, "a", "a")
+
+	// expiry runs concurrently while the lease is being taken
+	done := make(chan struct{})
+	s.hook = func() {
+		go func() {
+			fs.checkTimeoutAndRemove()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	if !Lease(fs, id) {
+		t.Fatalf("Lease: expected true")
+	}
+	<-done
+	if _, ok := fs.Stat(id); !ok {
+		t.Fatalf("%s: expected leased file not expired", id)
+	}
+
+	s.hook = func() {}
+	Release(fs, id)
+	time.Sleep(time.Millisecond)
+	fs.checkTimeoutAndRemove()
+	if Lease(fs, id) {
+		t.Fatalf("%s: expected lease to fail after expired", id)
+	}
+}
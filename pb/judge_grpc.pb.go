@@ -20,12 +20,14 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Executor_Exec_FullMethodName       = "/pb.Executor/Exec"
-	Executor_ExecStream_FullMethodName = "/pb.Executor/ExecStream"
-	Executor_FileList_FullMethodName   = "/pb.Executor/FileList"
-	Executor_FileGet_FullMethodName    = "/pb.Executor/FileGet"
-	Executor_FileAdd_FullMethodName    = "/pb.Executor/FileAdd"
-	Executor_FileDelete_FullMethodName = "/pb.Executor/FileDelete"
+	Executor_Exec_FullMethodName         = "/pb.Executor/Exec"
+	Executor_ExecStream_FullMethodName   = "/pb.Executor/ExecStream"
+	Executor_FileList_FullMethodName     = "/pb.Executor/FileList"
+	Executor_FileGet_FullMethodName      = "/pb.Executor/FileGet"
+	Executor_FileAdd_FullMethodName      = "/pb.Executor/FileAdd"
+	Executor_FileUpload_FullMethodName   = "/pb.Executor/FileUpload"
+	Executor_FileDownload_FullMethodName = "/pb.Executor/FileDownload"
+	Executor_FileDelete_FullMethodName   = "/pb.Executor/FileDelete"
 )
 
 // ExecutorClient is the client API for Executor service.
@@ -40,12 +42,19 @@ type ExecutorClient interface {
 	// are execOutput. TTY attribute will create single pty for the program thus
 	// stdout & stderr should have same name
 	ExecStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[StreamRequest, StreamResponse], error)
-	// FileList lists all files available in the file store
-	FileList(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*FileListType, error)
+	// FileList lists files available in the file store matching the filter
+	FileList(ctx context.Context, in *FileListRequest, opts ...grpc.CallOption) (*FileListType, error)
 	// FileGet download the file from the file store
 	FileGet(ctx context.Context, in *FileID, opts ...grpc.CallOption) (*FileContent, error)
 	// FileAdd create a file into the file store
 	FileAdd(ctx context.Context, in *FileContent, opts ...grpc.CallOption) (*FileID, error)
+	// FileUpload creates a file into the file store by chunks without message
+	// size limit. The upload is rejected if the SHA-256 digest in the trailer
+	// does not match the content
+	FileUpload(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[FileChunk, FileID], error)
+	// FileDownload downloads the file from the file store by chunks, the
+	// trailer contains the SHA-256 digest of the content
+	FileDownload(ctx context.Context, in *FileID, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FileChunk], error)
 	// FileDelete deletes a file from the file store
 	FileDelete(ctx context.Context, in *FileID, opts ...grpc.CallOption) (*emptypb.Empty, error)
 }
@@ -81,7 +90,7 @@ func (c *executorClient) ExecStream(ctx context.Context, opts ...grpc.CallOption
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type Executor_ExecStreamClient = grpc.BidiStreamingClient[StreamRequest, StreamResponse]
 
-func (c *executorClient) FileList(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*FileListType, error) {
+func (c *executorClient) FileList(ctx context.Context, in *FileListRequest, opts ...grpc.CallOption) (*FileListType, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(FileListType)
 	err := c.cc.Invoke(ctx, Executor_FileList_FullMethodName, in, out, cOpts...)
@@ -111,6 +120,38 @@ func (c *executorClient) FileAdd(ctx context.Context, in *FileContent, opts ...g
 	return out, nil
 }
 
+func (c *executorClient) FileUpload(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[FileChunk, FileID], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Executor_ServiceDesc.Streams[1], Executor_FileUpload_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[FileChunk, FileID]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Executor_FileUploadClient = grpc.ClientStreamingClient[FileChunk, FileID]
+
+func (c *executorClient) FileDownload(ctx context.Context, in *FileID, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FileChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Executor_ServiceDesc.Streams[2], Executor_FileDownload_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[FileID, FileChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Executor_FileDownloadClient = grpc.ServerStreamingClient[FileChunk]
+
 func (c *executorClient) FileDelete(ctx context.Context, in *FileID, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(emptypb.Empty)
@@ -133,12 +174,19 @@ type ExecutorServer interface {
 	// are execOutput. TTY attribute will create single pty for the program thus
 	// stdout & stderr should have same name
 	ExecStream(grpc.BidiStreamingServer[StreamRequest, StreamResponse]) error
-	// FileList lists all files available in the file store
-	FileList(context.Context, *emptypb.Empty) (*FileListType, error)
+	// FileList lists files available in the file store matching the filter
+	FileList(context.Context, *FileListRequest) (*FileListType, error)
 	// FileGet download the file from the file store
 	FileGet(context.Context, *FileID) (*FileContent, error)
 	// FileAdd create a file into the file store
 	FileAdd(context.Context, *FileContent) (*FileID, error)
+	// FileUpload creates a file into the file store by chunks without message
+	// size limit. The upload is rejected if the SHA-256 digest in the trailer
+	// does not match the content
+	FileUpload(grpc.ClientStreamingServer[FileChunk, FileID]) error
+	// FileDownload downloads the file from the file store by chunks, the
+	// trailer contains the SHA-256 digest of the content
+	FileDownload(*FileID, grpc.ServerStreamingServer[FileChunk]) error
 	// FileDelete deletes a file from the file store
 	FileDelete(context.Context, *FileID) (*emptypb.Empty, error)
 	mustEmbedUnimplementedExecutorServer()
@@ -157,7 +205,7 @@ func (UnimplementedExecutorServer) Exec(context.Context, *Request) (*Response, e
 func (UnimplementedExecutorServer) ExecStream(grpc.BidiStreamingServer[StreamRequest, StreamResponse]) error {
 	return status.Errorf(codes.Unimplemented, "method ExecStream not implemented")
 }
-func (UnimplementedExecutorServer) FileList(context.Context, *emptypb.Empty) (*FileListType, error) {
+func (UnimplementedExecutorServer) FileList(context.Context, *FileListRequest) (*FileListType, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FileList not implemented")
 }
 func (UnimplementedExecutorServer) FileGet(context.Context, *FileID) (*FileContent, error) {
@@ -166,6 +214,12 @@ func (UnimplementedExecutorServer) FileGet(context.Context, *FileID) (*FileConte
 func (UnimplementedExecutorServer) FileAdd(context.Context, *FileContent) (*FileID, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FileAdd not implemented")
 }
+func (UnimplementedExecutorServer) FileUpload(grpc.ClientStreamingServer[FileChunk, FileID]) error {
+	return status.Errorf(codes.Unimplemented, "method FileUpload not implemented")
+}
+func (UnimplementedExecutorServer) FileDownload(*FileID, grpc.ServerStreamingServer[FileChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method FileDownload not implemented")
+}
 func (UnimplementedExecutorServer) FileDelete(context.Context, *FileID) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FileDelete not implemented")
 }
@@ -216,7 +270,7 @@ func _Executor_ExecStream_Handler(srv interface{}, stream grpc.ServerStream) err
 type Executor_ExecStreamServer = grpc.BidiStreamingServer[StreamRequest, StreamResponse]
 
 func _Executor_FileList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(emptypb.Empty)
+	in := new(FileListRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -228,7 +282,7 @@ func _Executor_FileList_Handler(srv interface{}, ctx context.Context, dec func(i
 		FullMethod: Executor_FileList_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ExecutorServer).FileList(ctx, req.(*emptypb.Empty))
+		return srv.(ExecutorServer).FileList(ctx, req.(*FileListRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -269,6 +323,24 @@ func _Executor_FileAdd_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Executor_FileUpload_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ExecutorServer).FileUpload(&grpc.GenericServerStream[FileChunk, FileID]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Executor_FileUploadServer = grpc.ClientStreamingServer[FileChunk, FileID]
+
+func _Executor_FileDownload_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(FileID)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ExecutorServer).FileDownload(m, &grpc.GenericServerStream[FileID, FileChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Executor_FileDownloadServer = grpc.ServerStreamingServer[FileChunk]
+
 func _Executor_FileDelete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(FileID)
 	if err := dec(in); err != nil {
@@ -322,6 +394,16 @@ var Executor_ServiceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "FileUpload",
+			Handler:       _Executor_FileUpload_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "FileDownload",
+			Handler:       _Executor_FileDownload_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "judge.proto",
 }
@@ -22,6 +22,101 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type Request_NetworkType int32
+
+const (
+	Request_None     Request_NetworkType = 0
+	Request_Loopback Request_NetworkType = 1
+	Request_Shared   Request_NetworkType = 2
+)
+
+// Enum value maps for Request_NetworkType.
+var (
+	Request_NetworkType_name = map[int32]string{
+		0: "None",
+		1: "Loopback",
+		2: "Shared",
+	}
+	Request_NetworkType_value = map[string]int32{
+		"None":     0,
+		"Loopback": 1,
+		"Shared":   2,
+	}
+)
+
+func (x Request_NetworkType) Enum() *Request_NetworkType {
+	p := new(Request_NetworkType)
+	*p = x
+	return p
+}
+
+func (x Request_NetworkType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Request_NetworkType) Descriptor() protoreflect.EnumDescriptor {
+	return file_request_proto_enumTypes[0].Descriptor()
+}
+
+func (Request_NetworkType) Type() protoreflect.EnumType {
+	return &file_request_proto_enumTypes[0]
+}
+
+func (x Request_NetworkType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Request_NetworkType.Descriptor instead.
+func (Request_NetworkType) EnumDescriptor() ([]byte, []int) {
+	return file_request_proto_rawDescGZIP(), []int{0, 0}
+}
+
+type Request_RoleType int32
+
+const (
+	Request_Default Request_RoleType = 0
+	Request_Service Request_RoleType = 1
+)
+
+// Enum value maps for Request_RoleType.
+var (
+	Request_RoleType_name = map[int32]string{
+		0: "Default",
+		1: "Service",
+	}
+	Request_RoleType_value = map[string]int32{
+		"Default": 0,
+		"Service": 1,
+	}
+)
+
+func (x Request_RoleType) Enum() *Request_RoleType {
+	p := new(Request_RoleType)
+	*p = x
+	return p
+}
+
+func (x Request_RoleType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Request_RoleType) Descriptor() protoreflect.EnumDescriptor {
+	return file_request_proto_enumTypes[1].Descriptor()
+}
+
+func (Request_RoleType) Type() protoreflect.EnumType {
+	return &file_request_proto_enumTypes[1]
+}
+
+func (x Request_RoleType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Request_RoleType.Descriptor instead.
+func (Request_RoleType) EnumDescriptor() ([]byte, []int) {
+	return file_request_proto_rawDescGZIP(), []int{0, 1}
+}
+
 type Request struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	RequestID     string                 `protobuf:"bytes,1,opt,name=requestID" json:"requestID,omitempty"`
@@ -284,7 +379,10 @@ type Request_File struct {
 	//	*Request_File_Pipe
 	//	*Request_File_StreamIn
 	//	*Request_File_StreamOut
-	File          isRequest_File_File `protobuf_oneof:"file"`
+	File isRequest_File_File `protobuf_oneof:"file"`
+	// extract the archive (tar, tar.gz or zip) into the directory (copyIn
+	// only)
+	Extract       bool `protobuf:"varint,7,opt,name=extract" json:"extract,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -380,6 +478,13 @@ func (x *Request_File) GetStreamOut() *emptypb.Empty {
 	return nil
 }
 
+func (x *Request_File) GetExtract() bool {
+	if x != nil {
+		return x.Extract
+	}
+	return false
+}
+
 type isRequest_File_File interface {
 	isRequest_File_File()
 }
@@ -435,16 +540,40 @@ type Request_CmdType struct {
 	ProcLimit         uint64                    `protobuf:"varint,7,opt,name=procLimit" json:"procLimit,omitempty"`
 	CpuRateLimit      uint64                    `protobuf:"varint,15,opt,name=cpuRateLimit" json:"cpuRateLimit,omitempty"`
 	CpuSetLimit       string                    `protobuf:"bytes,17,opt,name=cpuSetLimit" json:"cpuSetLimit,omitempty"`
+	DiskLimit         uint64                    `protobuf:"varint,28,opt,name=diskLimit" json:"diskLimit,omitempty"`
+	FileCountLimit    uint64                    `protobuf:"varint,29,opt,name=fileCountLimit" json:"fileCountLimit,omitempty"`
+	IoReadBps         uint64                    `protobuf:"varint,30,opt,name=ioReadBps" json:"ioReadBps,omitempty"`
+	IoWriteBps        uint64                    `protobuf:"varint,31,opt,name=ioWriteBps" json:"ioWriteBps,omitempty"`
+	IoReadIops        uint64                    `protobuf:"varint,32,opt,name=ioReadIops" json:"ioReadIops,omitempty"`
+	IoWriteIops       uint64                    `protobuf:"varint,33,opt,name=ioWriteIops" json:"ioWriteIops,omitempty"`
+	MemoryHigh        uint64                    `protobuf:"varint,34,opt,name=memoryHigh" json:"memoryHigh,omitempty"`
+	CoreDump          bool                      `protobuf:"varint,35,opt,name=coreDump" json:"coreDump,omitempty"`
+	CoreDumpMax       uint64                    `protobuf:"varint,36,opt,name=coreDumpMax" json:"coreDumpMax,omitempty"`
+	TraceProcess      bool                      `protobuf:"varint,37,opt,name=traceProcess" json:"traceProcess,omitempty"`
+	Perf              bool                      `protobuf:"varint,38,opt,name=perf" json:"perf,omitempty"`
+	Repeat            uint32                    `protobuf:"varint,39,opt,name=repeat" json:"repeat,omitempty"`
+	RepeatThreshold   float64                   `protobuf:"fixed64,40,opt,name=repeatThreshold" json:"repeatThreshold,omitempty"`
+	ScaleTime         bool                      `protobuf:"varint,41,opt,name=scaleTime" json:"scaleTime,omitempty"`
 	DataSegmentLimit  bool                      `protobuf:"varint,16,opt,name=dataSegmentLimit" json:"dataSegmentLimit,omitempty"`
 	AddressSpaceLimit bool                      `protobuf:"varint,19,opt,name=addressSpaceLimit" json:"addressSpaceLimit,omitempty"`
+	SeccompAudit      bool                      `protobuf:"varint,20,opt,name=seccompAudit" json:"seccompAudit,omitempty"`
+	SyscallTrace      string                    `protobuf:"bytes,21,opt,name=syscallTrace" json:"syscallTrace,omitempty"`
+	SyscallTraceMax   uint64                    `protobuf:"varint,22,opt,name=syscallTraceMax" json:"syscallTraceMax,omitempty"`
+	Network           Request_NetworkType       `protobuf:"varint,23,opt,name=network,enum=pb.Request_NetworkType" json:"network,omitempty"`
+	Role              Request_RoleType          `protobuf:"varint,24,opt,name=role,enum=pb.Request_RoleType" json:"role,omitempty"`
+	ReadyPort         int32                     `protobuf:"varint,25,opt,name=readyPort" json:"readyPort,omitempty"`
+	ReadyFile         string                    `protobuf:"bytes,26,opt,name=readyFile" json:"readyFile,omitempty"`
+	ReadyTimeout      uint64                    `protobuf:"varint,27,opt,name=readyTimeout" json:"readyTimeout,omitempty"`
 	CopyIn            map[string]*Request_File  `protobuf:"bytes,8,rep,name=copyIn" json:"copyIn,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	Symlinks          map[string]string         `protobuf:"bytes,18,rep,name=symlinks" json:"symlinks,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	CopyOut           []*Request_CmdCopyOutFile `protobuf:"bytes,9,rep,name=copyOut" json:"copyOut,omitempty"`
 	CopyOutCached     []*Request_CmdCopyOutFile `protobuf:"bytes,10,rep,name=copyOutCached" json:"copyOutCached,omitempty"`
 	CopyOutDir        string                    `protobuf:"bytes,11,opt,name=copyOutDir" json:"copyOutDir,omitempty"`
-	CopyOutMax        uint64                    `protobuf:"varint,14,opt,name=copyOutMax" json:"copyOutMax,omitempty"`
-	unknownFields     protoimpl.UnknownFields
-	sizeCache         protoimpl.SizeCache
+	// copy out the directory as a bundle into the file store
+	CopyOutBundle string `protobuf:"bytes,42,opt,name=copyOutBundle" json:"copyOutBundle,omitempty"`
+	CopyOutMax    uint64 `protobuf:"varint,14,opt,name=copyOutMax" json:"copyOutMax,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Request_CmdType) Reset() {
@@ -554,6 +683,104 @@ func (x *Request_CmdType) GetCpuSetLimit() string {
 	return ""
 }
 
+func (x *Request_CmdType) GetDiskLimit() uint64 {
+	if x != nil {
+		return x.DiskLimit
+	}
+	return 0
+}
+
+func (x *Request_CmdType) GetFileCountLimit() uint64 {
+	if x != nil {
+		return x.FileCountLimit
+	}
+	return 0
+}
+
+func (x *Request_CmdType) GetIoReadBps() uint64 {
+	if x != nil {
+		return x.IoReadBps
+	}
+	return 0
+}
+
+func (x *Request_CmdType) GetIoWriteBps() uint64 {
+	if x != nil {
+		return x.IoWriteBps
+	}
+	return 0
+}
+
+func (x *Request_CmdType) GetIoReadIops() uint64 {
+	if x != nil {
+		return x.IoReadIops
+	}
+	return 0
+}
+
+func (x *Request_CmdType) GetIoWriteIops() uint64 {
+	if x != nil {
+		return x.IoWriteIops
+	}
+	return 0
+}
+
+func (x *Request_CmdType) GetMemoryHigh() uint64 {
+	if x != nil {
+		return x.MemoryHigh
+	}
+	return 0
+}
+
+func (x *Request_CmdType) GetCoreDump() bool {
+	if x != nil {
+		return x.CoreDump
+	}
+	return false
+}
+
+func (x *Request_CmdType) GetCoreDumpMax() uint64 {
+	if x != nil {
+		return x.CoreDumpMax
+	}
+	return 0
+}
+
+func (x *Request_CmdType) GetTraceProcess() bool {
+	if x != nil {
+		return x.TraceProcess
+	}
+	return false
+}
+
+func (x *Request_CmdType) GetPerf() bool {
+	if x != nil {
+		return x.Perf
+	}
+	return false
+}
+
+func (x *Request_CmdType) GetRepeat() uint32 {
+	if x != nil {
+		return x.Repeat
+	}
+	return 0
+}
+
+func (x *Request_CmdType) GetRepeatThreshold() float64 {
+	if x != nil {
+		return x.RepeatThreshold
+	}
+	return 0
+}
+
+func (x *Request_CmdType) GetScaleTime() bool {
+	if x != nil {
+		return x.ScaleTime
+	}
+	return false
+}
+
 func (x *Request_CmdType) GetDataSegmentLimit() bool {
 	if x != nil {
 		return x.DataSegmentLimit
@@ -568,6 +795,62 @@ func (x *Request_CmdType) GetAddressSpaceLimit() bool {
 	return false
 }
 
+func (x *Request_CmdType) GetSeccompAudit() bool {
+	if x != nil {
+		return x.SeccompAudit
+	}
+	return false
+}
+
+func (x *Request_CmdType) GetSyscallTrace() string {
+	if x != nil {
+		return x.SyscallTrace
+	}
+	return ""
+}
+
+func (x *Request_CmdType) GetSyscallTraceMax() uint64 {
+	if x != nil {
+		return x.SyscallTraceMax
+	}
+	return 0
+}
+
+func (x *Request_CmdType) GetNetwork() Request_NetworkType {
+	if x != nil {
+		return x.Network
+	}
+	return Request_None
+}
+
+func (x *Request_CmdType) GetRole() Request_RoleType {
+	if x != nil {
+		return x.Role
+	}
+	return Request_Default
+}
+
+func (x *Request_CmdType) GetReadyPort() int32 {
+	if x != nil {
+		return x.ReadyPort
+	}
+	return 0
+}
+
+func (x *Request_CmdType) GetReadyFile() string {
+	if x != nil {
+		return x.ReadyFile
+	}
+	return ""
+}
+
+func (x *Request_CmdType) GetReadyTimeout() uint64 {
+	if x != nil {
+		return x.ReadyTimeout
+	}
+	return 0
+}
+
 func (x *Request_CmdType) GetCopyIn() map[string]*Request_File {
 	if x != nil {
 		return x.CopyIn
@@ -603,6 +886,13 @@ func (x *Request_CmdType) GetCopyOutDir() string {
 	return ""
 }
 
+func (x *Request_CmdType) GetCopyOutBundle() string {
+	if x != nil {
+		return x.CopyOutBundle
+	}
+	return ""
+}
+
 func (x *Request_CmdType) GetCopyOutMax() uint64 {
 	if x != nil {
 		return x.CopyOutMax
@@ -611,9 +901,13 @@ func (x *Request_CmdType) GetCopyOutMax() uint64 {
 }
 
 type Request_CmdCopyOutFile struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
-	Optional      bool                   `protobuf:"varint,2,opt,name=optional" json:"optional,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// file name, or glob pattern (e.g. out/*.txt, **/*.class) to copy out
+	// each matched file by its path
+	Name     string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Optional bool   `protobuf:"varint,2,opt,name=optional" json:"optional,omitempty"`
+	// copy out the directory as a single tar archive
+	Archive       bool `protobuf:"varint,3,opt,name=archive" json:"archive,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -662,6 +956,13 @@ func (x *Request_CmdCopyOutFile) GetOptional() bool {
 	return false
 }
 
+func (x *Request_CmdCopyOutFile) GetArchive() bool {
+	if x != nil {
+		return x.Archive
+	}
+	return false
+}
+
 type Request_PipeMap struct {
 	state         protoimpl.MessageState     `protogen:"open.v1"`
 	In            *Request_PipeMap_PipeIndex `protobuf:"bytes,1,opt,name=in" json:"in,omitempty"`
@@ -796,7 +1097,7 @@ var file_request_proto_rawDesc = string([]byte{
 	0x0a, 0x0d, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
 	0x02, 0x70, 0x62, 0x1a, 0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74,
 	0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x22, 0x8f, 0x0e, 0x0a, 0x07, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1c, 0x0a, 0x09,
+	0x22, 0xad, 0x15, 0x0a, 0x07, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1c, 0x0a, 0x09,
 	0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
 	0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x44, 0x12, 0x25, 0x0a, 0x03, 0x63, 0x6d,
 	0x64, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x71,
@@ -816,7 +1117,7 @@ var file_request_proto_rawDesc = string([]byte{
 	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x61,
 	0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x6d, 0x61, 0x78, 0x12, 0x12, 0x0a, 0x04,
 	0x70, 0x69, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x70, 0x69, 0x70, 0x65,
-	0x1a, 0xc0, 0x02, 0x0a, 0x04, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x2d, 0x0a, 0x05, 0x6c, 0x6f, 0x63,
+	0x1a, 0xda, 0x02, 0x0a, 0x04, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x2d, 0x0a, 0x05, 0x6c, 0x6f, 0x63,
 	0x61, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65,
 	0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x46, 0x69, 0x6c, 0x65, 0x48,
 	0x00, 0x52, 0x05, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x12, 0x30, 0x0a, 0x06, 0x6d, 0x65, 0x6d, 0x6f,
@@ -835,84 +1136,142 @@ var file_request_proto_rawDesc = string([]byte{
 	0x6d, 0x49, 0x6e, 0x12, 0x36, 0x0a, 0x09, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x4f, 0x75, 0x74,
 	0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
 	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x48, 0x00,
-	0x52, 0x09, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x4f, 0x75, 0x74, 0x42, 0x06, 0x0a, 0x04, 0x66,
-	0x69, 0x6c, 0x65, 0x1a, 0xef, 0x06, 0x0a, 0x07, 0x43, 0x6d, 0x64, 0x54, 0x79, 0x70, 0x65, 0x12,
-	0x12, 0x0a, 0x04, 0x61, 0x72, 0x67, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x61,
-	0x72, 0x67, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x6e, 0x76, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09,
-	0x52, 0x03, 0x65, 0x6e, 0x76, 0x12, 0x26, 0x0a, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x03,
-	0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x10, 0x0a,
-	0x03, 0x74, 0x74, 0x79, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x08, 0x52, 0x03, 0x74, 0x74, 0x79, 0x12,
-	0x22, 0x0a, 0x0c, 0x63, 0x70, 0x75, 0x54, 0x69, 0x6d, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18,
-	0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x63, 0x70, 0x75, 0x54, 0x69, 0x6d, 0x65, 0x4c, 0x69,
-	0x6d, 0x69, 0x74, 0x12, 0x26, 0x0a, 0x0e, 0x63, 0x6c, 0x6f, 0x63, 0x6b, 0x54, 0x69, 0x6d, 0x65,
-	0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0e, 0x63, 0x6c, 0x6f,
-	0x63, 0x6b, 0x54, 0x69, 0x6d, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x20, 0x0a, 0x0b, 0x6d,
-	0x65, 0x6d, 0x6f, 0x72, 0x79, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04,
-	0x52, 0x0b, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x1e, 0x0a,
-	0x0a, 0x73, 0x74, 0x61, 0x63, 0x6b, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x0c, 0x20, 0x01, 0x28,
-	0x04, 0x52, 0x0a, 0x73, 0x74, 0x61, 0x63, 0x6b, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x1c, 0x0a,
-	0x09, 0x70, 0x72, 0x6f, 0x63, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04,
-	0x52, 0x09, 0x70, 0x72, 0x6f, 0x63, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x22, 0x0a, 0x0c, 0x63,
-	0x70, 0x75, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x0f, 0x20, 0x01, 0x28,
-	0x04, 0x52, 0x0c, 0x63, 0x70, 0x75, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12,
-	0x20, 0x0a, 0x0b, 0x63, 0x70, 0x75, 0x53, 0x65, 0x74, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x11,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x70, 0x75, 0x53, 0x65, 0x74, 0x4c, 0x69, 0x6d, 0x69,
-	0x74, 0x12, 0x2a, 0x0a, 0x10, 0x64, 0x61, 0x74, 0x61, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74,
-	0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x10, 0x20, 0x01, 0x28, 0x08, 0x52, 0x10, 0x64, 0x61, 0x74,
-	0x61, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x2c, 0x0a,
-	0x11, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x53, 0x70, 0x61, 0x63, 0x65, 0x4c, 0x69, 0x6d,
-	0x69, 0x74, 0x18, 0x13, 0x20, 0x01, 0x28, 0x08, 0x52, 0x11, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73,
-	0x73, 0x53, 0x70, 0x61, 0x63, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x37, 0x0a, 0x06, 0x63,
-	0x6f, 0x70, 0x79, 0x49, 0x6e, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x70, 0x62,
+	0x52, 0x09, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x4f, 0x75, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x65,
+	0x78, 0x74, 0x72, 0x61, 0x63, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x65, 0x78,
+	0x74, 0x72, 0x61, 0x63, 0x74, 0x42, 0x06, 0x0a, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x1a, 0x80, 0x0d,
+	0x0a, 0x07, 0x43, 0x6d, 0x64, 0x54, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x72, 0x67,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x61, 0x72, 0x67, 0x73, 0x12, 0x10, 0x0a,
+	0x03, 0x65, 0x6e, 0x76, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x03, 0x65, 0x6e, 0x76, 0x12,
+	0x26, 0x0a, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10,
+	0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x46, 0x69, 0x6c, 0x65,
+	0x52, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x74, 0x74, 0x79, 0x18, 0x0d,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x03, 0x74, 0x74, 0x79, 0x12, 0x22, 0x0a, 0x0c, 0x63, 0x70, 0x75,
+	0x54, 0x69, 0x6d, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x0c, 0x63, 0x70, 0x75, 0x54, 0x69, 0x6d, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x26, 0x0a,
+	0x0e, 0x63, 0x6c, 0x6f, 0x63, 0x6b, 0x54, 0x69, 0x6d, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0e, 0x63, 0x6c, 0x6f, 0x63, 0x6b, 0x54, 0x69, 0x6d, 0x65,
+	0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x20, 0x0a, 0x0b, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x4c,
+	0x69, 0x6d, 0x69, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x6d, 0x65, 0x6d, 0x6f,
+	0x72, 0x79, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x63, 0x6b,
+	0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x73, 0x74, 0x61,
+	0x63, 0x6b, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x72, 0x6f, 0x63, 0x4c,
+	0x69, 0x6d, 0x69, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x63,
+	0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x22, 0x0a, 0x0c, 0x63, 0x70, 0x75, 0x52, 0x61, 0x74, 0x65,
+	0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x63, 0x70, 0x75,
+	0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x20, 0x0a, 0x0b, 0x63, 0x70, 0x75,
+	0x53, 0x65, 0x74, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x11, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
+	0x63, 0x70, 0x75, 0x53, 0x65, 0x74, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x64,
+	0x69, 0x73, 0x6b, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x1c, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09,
+	0x64, 0x69, 0x73, 0x6b, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x26, 0x0a, 0x0e, 0x66, 0x69, 0x6c,
+	0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x1d, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x0e, 0x66, 0x69, 0x6c, 0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x4c, 0x69, 0x6d, 0x69,
+	0x74, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x6f, 0x52, 0x65, 0x61, 0x64, 0x42, 0x70, 0x73, 0x18, 0x1e,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x69, 0x6f, 0x52, 0x65, 0x61, 0x64, 0x42, 0x70, 0x73, 0x12,
+	0x1e, 0x0a, 0x0a, 0x69, 0x6f, 0x57, 0x72, 0x69, 0x74, 0x65, 0x42, 0x70, 0x73, 0x18, 0x1f, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x0a, 0x69, 0x6f, 0x57, 0x72, 0x69, 0x74, 0x65, 0x42, 0x70, 0x73, 0x12,
+	0x1e, 0x0a, 0x0a, 0x69, 0x6f, 0x52, 0x65, 0x61, 0x64, 0x49, 0x6f, 0x70, 0x73, 0x18, 0x20, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x0a, 0x69, 0x6f, 0x52, 0x65, 0x61, 0x64, 0x49, 0x6f, 0x70, 0x73, 0x12,
+	0x20, 0x0a, 0x0b, 0x69, 0x6f, 0x57, 0x72, 0x69, 0x74, 0x65, 0x49, 0x6f, 0x70, 0x73, 0x18, 0x21,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x69, 0x6f, 0x57, 0x72, 0x69, 0x74, 0x65, 0x49, 0x6f, 0x70,
+	0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x48, 0x69, 0x67, 0x68, 0x18,
+	0x22, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x48, 0x69, 0x67,
+	0x68, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x6f, 0x72, 0x65, 0x44, 0x75, 0x6d, 0x70, 0x18, 0x23, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x08, 0x63, 0x6f, 0x72, 0x65, 0x44, 0x75, 0x6d, 0x70, 0x12, 0x20, 0x0a,
+	0x0b, 0x63, 0x6f, 0x72, 0x65, 0x44, 0x75, 0x6d, 0x70, 0x4d, 0x61, 0x78, 0x18, 0x24, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x0b, 0x63, 0x6f, 0x72, 0x65, 0x44, 0x75, 0x6d, 0x70, 0x4d, 0x61, 0x78, 0x12,
+	0x22, 0x0a, 0x0c, 0x74, 0x72, 0x61, 0x63, 0x65, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x18,
+	0x25, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x74, 0x72, 0x61, 0x63, 0x65, 0x50, 0x72, 0x6f, 0x63,
+	0x65, 0x73, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x65, 0x72, 0x66, 0x18, 0x26, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x04, 0x70, 0x65, 0x72, 0x66, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x70, 0x65, 0x61,
+	0x74, 0x18, 0x27, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x72, 0x65, 0x70, 0x65, 0x61, 0x74, 0x12,
+	0x28, 0x0a, 0x0f, 0x72, 0x65, 0x70, 0x65, 0x61, 0x74, 0x54, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f,
+	0x6c, 0x64, 0x18, 0x28, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0f, 0x72, 0x65, 0x70, 0x65, 0x61, 0x74,
+	0x54, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x63, 0x61,
+	0x6c, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x29, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x73, 0x63,
+	0x61, 0x6c, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x2a, 0x0a, 0x10, 0x64, 0x61, 0x74, 0x61, 0x53,
+	0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x10, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x10, 0x64, 0x61, 0x74, 0x61, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x4c, 0x69,
+	0x6d, 0x69, 0x74, 0x12, 0x2c, 0x0a, 0x11, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x53, 0x70,
+	0x61, 0x63, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x13, 0x20, 0x01, 0x28, 0x08, 0x52, 0x11,
+	0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x53, 0x70, 0x61, 0x63, 0x65, 0x4c, 0x69, 0x6d, 0x69,
+	0x74, 0x12, 0x22, 0x0a, 0x0c, 0x73, 0x65, 0x63, 0x63, 0x6f, 0x6d, 0x70, 0x41, 0x75, 0x64, 0x69,
+	0x74, 0x18, 0x14, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x73, 0x65, 0x63, 0x63, 0x6f, 0x6d, 0x70,
+	0x41, 0x75, 0x64, 0x69, 0x74, 0x12, 0x22, 0x0a, 0x0c, 0x73, 0x79, 0x73, 0x63, 0x61, 0x6c, 0x6c,
+	0x54, 0x72, 0x61, 0x63, 0x65, 0x18, 0x15, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x79, 0x73,
+	0x63, 0x61, 0x6c, 0x6c, 0x54, 0x72, 0x61, 0x63, 0x65, 0x12, 0x28, 0x0a, 0x0f, 0x73, 0x79, 0x73,
+	0x63, 0x61, 0x6c, 0x6c, 0x54, 0x72, 0x61, 0x63, 0x65, 0x4d, 0x61, 0x78, 0x18, 0x16, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x0f, 0x73, 0x79, 0x73, 0x63, 0x61, 0x6c, 0x6c, 0x54, 0x72, 0x61, 0x63, 0x65,
+	0x4d, 0x61, 0x78, 0x12, 0x31, 0x0a, 0x07, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x18, 0x17,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x54, 0x79, 0x70, 0x65, 0x52, 0x07, 0x6e,
+	0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x12, 0x28, 0x0a, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x18, 0x18,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x2e, 0x52, 0x6f, 0x6c, 0x65, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x72, 0x6f, 0x6c, 0x65,
+	0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x61, 0x64, 0x79, 0x50, 0x6f, 0x72, 0x74, 0x18, 0x19, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x09, 0x72, 0x65, 0x61, 0x64, 0x79, 0x50, 0x6f, 0x72, 0x74, 0x12, 0x1c,
+	0x0a, 0x09, 0x72, 0x65, 0x61, 0x64, 0x79, 0x46, 0x69, 0x6c, 0x65, 0x18, 0x1a, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x72, 0x65, 0x61, 0x64, 0x79, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x22, 0x0a, 0x0c,
+	0x72, 0x65, 0x61, 0x64, 0x79, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x1b, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x0c, 0x72, 0x65, 0x61, 0x64, 0x79, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74,
+	0x12, 0x37, 0x0a, 0x06, 0x63, 0x6f, 0x70, 0x79, 0x49, 0x6e, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x1f, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x43, 0x6d,
+	0x64, 0x54, 0x79, 0x70, 0x65, 0x2e, 0x43, 0x6f, 0x70, 0x79, 0x49, 0x6e, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x52, 0x06, 0x63, 0x6f, 0x70, 0x79, 0x49, 0x6e, 0x12, 0x3d, 0x0a, 0x08, 0x73, 0x79, 0x6d,
+	0x6c, 0x69, 0x6e, 0x6b, 0x73, 0x18, 0x12, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x70, 0x62,
 	0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x43, 0x6d, 0x64, 0x54, 0x79, 0x70, 0x65,
-	0x2e, 0x43, 0x6f, 0x70, 0x79, 0x49, 0x6e, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x63, 0x6f,
-	0x70, 0x79, 0x49, 0x6e, 0x12, 0x3d, 0x0a, 0x08, 0x73, 0x79, 0x6d, 0x6c, 0x69, 0x6e, 0x6b, 0x73,
-	0x18, 0x12, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x2e, 0x43, 0x6d, 0x64, 0x54, 0x79, 0x70, 0x65, 0x2e, 0x53, 0x79, 0x6d, 0x6c,
-	0x69, 0x6e, 0x6b, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x08, 0x73, 0x79, 0x6d, 0x6c, 0x69,
-	0x6e, 0x6b, 0x73, 0x12, 0x34, 0x0a, 0x07, 0x63, 0x6f, 0x70, 0x79, 0x4f, 0x75, 0x74, 0x18, 0x09,
-	0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x2e, 0x43, 0x6d, 0x64, 0x43, 0x6f, 0x70, 0x79, 0x4f, 0x75, 0x74, 0x46, 0x69, 0x6c, 0x65,
-	0x52, 0x07, 0x63, 0x6f, 0x70, 0x79, 0x4f, 0x75, 0x74, 0x12, 0x40, 0x0a, 0x0d, 0x63, 0x6f, 0x70,
-	0x79, 0x4f, 0x75, 0x74, 0x43, 0x61, 0x63, 0x68, 0x65, 0x64, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b,
-	0x32, 0x1a, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x43, 0x6d,
-	0x64, 0x43, 0x6f, 0x70, 0x79, 0x4f, 0x75, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x0d, 0x63, 0x6f,
-	0x70, 0x79, 0x4f, 0x75, 0x74, 0x43, 0x61, 0x63, 0x68, 0x65, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x63,
-	0x6f, 0x70, 0x79, 0x4f, 0x75, 0x74, 0x44, 0x69, 0x72, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x0a, 0x63, 0x6f, 0x70, 0x79, 0x4f, 0x75, 0x74, 0x44, 0x69, 0x72, 0x12, 0x1e, 0x0a, 0x0a, 0x63,
-	0x6f, 0x70, 0x79, 0x4f, 0x75, 0x74, 0x4d, 0x61, 0x78, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x04, 0x52,
-	0x0a, 0x63, 0x6f, 0x70, 0x79, 0x4f, 0x75, 0x74, 0x4d, 0x61, 0x78, 0x1a, 0x4b, 0x0a, 0x0b, 0x43,
-	0x6f, 0x70, 0x79, 0x49, 0x6e, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65,
-	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x26, 0x0a, 0x05,
-	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x70, 0x62,
-	0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x05, 0x76,
-	0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x3b, 0x0a, 0x0d, 0x53, 0x79, 0x6d, 0x6c,
-	0x69, 0x6e, 0x6b, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76,
-	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
-	0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x40, 0x0a, 0x0e, 0x43, 0x6d, 0x64, 0x43, 0x6f, 0x70, 0x79,
-	0x4f, 0x75, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x6f,
-	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x6f,
-	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x1a, 0xd8, 0x01, 0x0a, 0x07, 0x50, 0x69, 0x70, 0x65,
-	0x4d, 0x61, 0x70, 0x12, 0x2d, 0x0a, 0x02, 0x69, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x1d, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x50, 0x69, 0x70,
-	0x65, 0x4d, 0x61, 0x70, 0x2e, 0x50, 0x69, 0x70, 0x65, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x52, 0x02,
-	0x69, 0x6e, 0x12, 0x2f, 0x0a, 0x03, 0x6f, 0x75, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x1d, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x50, 0x69, 0x70,
-	0x65, 0x4d, 0x61, 0x70, 0x2e, 0x50, 0x69, 0x70, 0x65, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x52, 0x03,
-	0x6f, 0x75, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x08, 0x52, 0x05, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
-	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x10, 0x0a,
-	0x03, 0x6d, 0x61, 0x78, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x6d, 0x61, 0x78, 0x1a,
-	0x31, 0x0a, 0x09, 0x50, 0x69, 0x70, 0x65, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x14, 0x0a, 0x05,
-	0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x69, 0x6e, 0x64,
-	0x65, 0x78, 0x12, 0x0e, 0x0a, 0x02, 0x66, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02,
-	0x66, 0x64, 0x42, 0x24, 0x5a, 0x1d, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
-	0x2f, 0x63, 0x72, 0x69, 0x79, 0x6c, 0x65, 0x2f, 0x67, 0x6f, 0x2d, 0x6a, 0x75, 0x64, 0x67, 0x65,
-	0x2f, 0x70, 0x62, 0x92, 0x03, 0x02, 0x08, 0x02, 0x62, 0x08, 0x65, 0x64, 0x69, 0x74, 0x69, 0x6f,
-	0x6e, 0x73, 0x70, 0xe8, 0x07,
+	0x2e, 0x53, 0x79, 0x6d, 0x6c, 0x69, 0x6e, 0x6b, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x08,
+	0x73, 0x79, 0x6d, 0x6c, 0x69, 0x6e, 0x6b, 0x73, 0x12, 0x34, 0x0a, 0x07, 0x63, 0x6f, 0x70, 0x79,
+	0x4f, 0x75, 0x74, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x70, 0x62, 0x2e, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x43, 0x6d, 0x64, 0x43, 0x6f, 0x70, 0x79, 0x4f, 0x75,
+	0x74, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x07, 0x63, 0x6f, 0x70, 0x79, 0x4f, 0x75, 0x74, 0x12, 0x40,
+	0x0a, 0x0d, 0x63, 0x6f, 0x70, 0x79, 0x4f, 0x75, 0x74, 0x43, 0x61, 0x63, 0x68, 0x65, 0x64, 0x18,
+	0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x2e, 0x43, 0x6d, 0x64, 0x43, 0x6f, 0x70, 0x79, 0x4f, 0x75, 0x74, 0x46, 0x69, 0x6c,
+	0x65, 0x52, 0x0d, 0x63, 0x6f, 0x70, 0x79, 0x4f, 0x75, 0x74, 0x43, 0x61, 0x63, 0x68, 0x65, 0x64,
+	0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x70, 0x79, 0x4f, 0x75, 0x74, 0x44, 0x69, 0x72, 0x18, 0x0b,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x6f, 0x70, 0x79, 0x4f, 0x75, 0x74, 0x44, 0x69, 0x72,
+	0x12, 0x24, 0x0a, 0x0d, 0x63, 0x6f, 0x70, 0x79, 0x4f, 0x75, 0x74, 0x42, 0x75, 0x6e, 0x64, 0x6c,
+	0x65, 0x18, 0x2a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x6f, 0x70, 0x79, 0x4f, 0x75, 0x74,
+	0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x70, 0x79, 0x4f, 0x75,
+	0x74, 0x4d, 0x61, 0x78, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x63, 0x6f, 0x70, 0x79,
+	0x4f, 0x75, 0x74, 0x4d, 0x61, 0x78, 0x1a, 0x4b, 0x0a, 0x0b, 0x43, 0x6f, 0x70, 0x79, 0x49, 0x6e,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x26, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a,
+	0x02, 0x38, 0x01, 0x1a, 0x3b, 0x0a, 0x0d, 0x53, 0x79, 0x6d, 0x6c, 0x69, 0x6e, 0x6b, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01,
+	0x1a, 0x5a, 0x0a, 0x0e, 0x43, 0x6d, 0x64, 0x43, 0x6f, 0x70, 0x79, 0x4f, 0x75, 0x74, 0x46, 0x69,
+	0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x61, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x61, 0x6c, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x07, 0x61, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x1a, 0xd8, 0x01, 0x0a,
+	0x07, 0x50, 0x69, 0x70, 0x65, 0x4d, 0x61, 0x70, 0x12, 0x2d, 0x0a, 0x02, 0x69, 0x6e, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x2e, 0x50, 0x69, 0x70, 0x65, 0x4d, 0x61, 0x70, 0x2e, 0x50, 0x69, 0x70, 0x65, 0x49, 0x6e,
+	0x64, 0x65, 0x78, 0x52, 0x02, 0x69, 0x6e, 0x12, 0x2f, 0x0a, 0x03, 0x6f, 0x75, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x2e, 0x50, 0x69, 0x70, 0x65, 0x4d, 0x61, 0x70, 0x2e, 0x50, 0x69, 0x70, 0x65, 0x49, 0x6e,
+	0x64, 0x65, 0x78, 0x52, 0x03, 0x6f, 0x75, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x72, 0x6f, 0x78,
+	0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x12, 0x12,
+	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x61, 0x78, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x03, 0x6d, 0x61, 0x78, 0x1a, 0x31, 0x0a, 0x09, 0x50, 0x69, 0x70, 0x65, 0x49, 0x6e, 0x64, 0x65,
+	0x78, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x0e, 0x0a, 0x02, 0x66, 0x64, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x02, 0x66, 0x64, 0x22, 0x31, 0x0a, 0x0b, 0x4e, 0x65, 0x74, 0x77, 0x6f,
+	0x72, 0x6b, 0x54, 0x79, 0x70, 0x65, 0x12, 0x08, 0x0a, 0x04, 0x4e, 0x6f, 0x6e, 0x65, 0x10, 0x00,
+	0x12, 0x0c, 0x0a, 0x08, 0x4c, 0x6f, 0x6f, 0x70, 0x62, 0x61, 0x63, 0x6b, 0x10, 0x01, 0x12, 0x0a,
+	0x0a, 0x06, 0x53, 0x68, 0x61, 0x72, 0x65, 0x64, 0x10, 0x02, 0x22, 0x24, 0x0a, 0x08, 0x52, 0x6f,
+	0x6c, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x44, 0x65, 0x66, 0x61, 0x75, 0x6c,
+	0x74, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x10, 0x01,
+	0x42, 0x24, 0x5a, 0x1d, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63,
+	0x72, 0x69, 0x79, 0x6c, 0x65, 0x2f, 0x67, 0x6f, 0x2d, 0x6a, 0x75, 0x64, 0x67, 0x65, 0x2f, 0x70,
+	0x62, 0x92, 0x03, 0x02, 0x08, 0x02, 0x62, 0x08, 0x65, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x70, 0xe8, 0x07,
 })
 
 var (
@@ -927,44 +1286,49 @@ func file_request_proto_rawDescGZIP() []byte {
 	return file_request_proto_rawDescData
 }
 
+var file_request_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
 var file_request_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
 var file_request_proto_goTypes = []any{
-	(*Request)(nil),                   // 0: pb.Request
-	(*Request_LocalFile)(nil),         // 1: pb.Request.LocalFile
-	(*Request_MemoryFile)(nil),        // 2: pb.Request.MemoryFile
-	(*Request_CachedFile)(nil),        // 3: pb.Request.CachedFile
-	(*Request_PipeCollector)(nil),     // 4: pb.Request.PipeCollector
-	(*Request_File)(nil),              // 5: pb.Request.File
-	(*Request_CmdType)(nil),           // 6: pb.Request.CmdType
-	(*Request_CmdCopyOutFile)(nil),    // 7: pb.Request.CmdCopyOutFile
-	(*Request_PipeMap)(nil),           // 8: pb.Request.PipeMap
-	nil,                               // 9: pb.Request.CmdType.CopyInEntry
-	nil,                               // 10: pb.Request.CmdType.SymlinksEntry
-	(*Request_PipeMap_PipeIndex)(nil), // 11: pb.Request.PipeMap.PipeIndex
-	(*emptypb.Empty)(nil),             // 12: google.protobuf.Empty
+	(Request_NetworkType)(0),          // 0: pb.Request.NetworkType
+	(Request_RoleType)(0),             // 1: pb.Request.RoleType
+	(*Request)(nil),                   // 2: pb.Request
+	(*Request_LocalFile)(nil),         // 3: pb.Request.LocalFile
+	(*Request_MemoryFile)(nil),        // 4: pb.Request.MemoryFile
+	(*Request_CachedFile)(nil),        // 5: pb.Request.CachedFile
+	(*Request_PipeCollector)(nil),     // 6: pb.Request.PipeCollector
+	(*Request_File)(nil),              // 7: pb.Request.File
+	(*Request_CmdType)(nil),           // 8: pb.Request.CmdType
+	(*Request_CmdCopyOutFile)(nil),    // 9: pb.Request.CmdCopyOutFile
+	(*Request_PipeMap)(nil),           // 10: pb.Request.PipeMap
+	nil,                               // 11: pb.Request.CmdType.CopyInEntry
+	nil,                               // 12: pb.Request.CmdType.SymlinksEntry
+	(*Request_PipeMap_PipeIndex)(nil), // 13: pb.Request.PipeMap.PipeIndex
+	(*emptypb.Empty)(nil),             // 14: google.protobuf.Empty
 }
 var file_request_proto_depIdxs = []int32{
-	6,  // 0: pb.Request.cmd:type_name -> pb.Request.CmdType
-	8,  // 1: pb.Request.pipeMapping:type_name -> pb.Request.PipeMap
-	1,  // 2: pb.Request.File.local:type_name -> pb.Request.LocalFile
-	2,  // 3: pb.Request.File.memory:type_name -> pb.Request.MemoryFile
-	3,  // 4: pb.Request.File.cached:type_name -> pb.Request.CachedFile
-	4,  // 5: pb.Request.File.pipe:type_name -> pb.Request.PipeCollector
-	12, // 6: pb.Request.File.streamIn:type_name -> google.protobuf.Empty
-	12, // 7: pb.Request.File.streamOut:type_name -> google.protobuf.Empty
-	5,  // 8: pb.Request.CmdType.files:type_name -> pb.Request.File
-	9,  // 9: pb.Request.CmdType.copyIn:type_name -> pb.Request.CmdType.CopyInEntry
-	10, // 10: pb.Request.CmdType.symlinks:type_name -> pb.Request.CmdType.SymlinksEntry
-	7,  // 11: pb.Request.CmdType.copyOut:type_name -> pb.Request.CmdCopyOutFile
-	7,  // 12: pb.Request.CmdType.copyOutCached:type_name -> pb.Request.CmdCopyOutFile
-	11, // 13: pb.Request.PipeMap.in:type_name -> pb.Request.PipeMap.PipeIndex
-	11, // 14: pb.Request.PipeMap.out:type_name -> pb.Request.PipeMap.PipeIndex
-	5,  // 15: pb.Request.CmdType.CopyInEntry.value:type_name -> pb.Request.File
-	16, // [16:16] is the sub-list for method output_type
-	16, // [16:16] is the sub-list for method input_type
-	16, // [16:16] is the sub-list for extension type_name
-	16, // [16:16] is the sub-list for extension extendee
-	0,  // [0:16] is the sub-list for field type_name
+	8,  // 0: pb.Request.cmd:type_name -> pb.Request.CmdType
+	10, // 1: pb.Request.pipeMapping:type_name -> pb.Request.PipeMap
+	3,  // 2: pb.Request.File.local:type_name -> pb.Request.LocalFile
+	4,  // 3: pb.Request.File.memory:type_name -> pb.Request.MemoryFile
+	5,  // 4: pb.Request.File.cached:type_name -> pb.Request.CachedFile
+	6,  // 5: pb.Request.File.pipe:type_name -> pb.Request.PipeCollector
+	14, // 6: pb.Request.File.streamIn:type_name -> google.protobuf.Empty
+	14, // 7: pb.Request.File.streamOut:type_name -> google.protobuf.Empty
+	7,  // 8: pb.Request.CmdType.files:type_name -> pb.Request.File
+	0,  // 9: pb.Request.CmdType.network:type_name -> pb.Request.NetworkType
+	1,  // 10: pb.Request.CmdType.role:type_name -> pb.Request.RoleType
+	11, // 11: pb.Request.CmdType.copyIn:type_name -> pb.Request.CmdType.CopyInEntry
+	12, // 12: pb.Request.CmdType.symlinks:type_name -> pb.Request.CmdType.SymlinksEntry
+	9,  // 13: pb.Request.CmdType.copyOut:type_name -> pb.Request.CmdCopyOutFile
+	9,  // 14: pb.Request.CmdType.copyOutCached:type_name -> pb.Request.CmdCopyOutFile
+	13, // 15: pb.Request.PipeMap.in:type_name -> pb.Request.PipeMap.PipeIndex
+	13, // 16: pb.Request.PipeMap.out:type_name -> pb.Request.PipeMap.PipeIndex
+	7,  // 17: pb.Request.CmdType.CopyInEntry.value:type_name -> pb.Request.File
+	18, // [18:18] is the sub-list for method output_type
+	18, // [18:18] is the sub-list for method input_type
+	18, // [18:18] is the sub-list for extension type_name
+	18, // [18:18] is the sub-list for extension extendee
+	0,  // [0:18] is the sub-list for field type_name
 }
 
 func init() { file_request_proto_init() }
@@ -985,13 +1349,14 @@ func file_request_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_request_proto_rawDesc), len(file_request_proto_rawDesc)),
-			NumEnums:      0,
+			NumEnums:      2,
 			NumMessages:   12,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_request_proto_goTypes,
 		DependencyIndexes: file_request_proto_depIdxs,
+		EnumInfos:         file_request_proto_enumTypes,
 		MessageInfos:      file_request_proto_msgTypes,
 	}.Build()
 	File_request_proto = out.File
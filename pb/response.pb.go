@@ -108,6 +108,7 @@ const (
 	Response_Result_JudgementFailed     Response_Result_StatusType = 11 // Not used
 	Response_Result_InvalidInteraction  Response_Result_StatusType = 12 // Not used
 	Response_Result_InternalError       Response_Result_StatusType = 13
+	Response_Result_DiskLimitExceeded   Response_Result_StatusType = 14
 )
 
 // Enum value maps for Response_Result_StatusType.
@@ -127,6 +128,7 @@ var (
 		11: "JudgementFailed",
 		12: "InvalidInteraction",
 		13: "InternalError",
+		14: "DiskLimitExceeded",
 	}
 	Response_Result_StatusType_value = map[string]int32{
 		"Invalid":             0,
@@ -143,6 +145,7 @@ var (
 		"JudgementFailed":     11,
 		"InvalidInteraction":  12,
 		"InternalError":       13,
+		"DiskLimitExceeded":   14,
 	}
 )
 
@@ -170,7 +173,7 @@ func (x Response_Result_StatusType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use Response_Result_StatusType.Descriptor instead.
 func (Response_Result_StatusType) EnumDescriptor() ([]byte, []int) {
-	return file_response_proto_rawDescGZIP(), []int{0, 1, 0}
+	return file_response_proto_rawDescGZIP(), []int{0, 6, 0}
 }
 
 type Response struct {
@@ -293,25 +296,497 @@ func (x *Response_FileError) GetMessage() string {
 	return ""
 }
 
-type Response_Result struct {
+type Response_Crash struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Signal        string                 `protobuf:"bytes,1,opt,name=signal" json:"signal,omitempty"`
+	Addr          uint64                 `protobuf:"varint,2,opt,name=addr" json:"addr,omitempty"`
+	Stack         []string               `protobuf:"bytes,3,rep,name=stack" json:"stack,omitempty"`
+	CoreFileID    string                 `protobuf:"bytes,4,opt,name=coreFileID" json:"coreFileID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Response_Crash) Reset() {
+	*x = Response_Crash{}
+	mi := &file_response_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Response_Crash) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Response_Crash) ProtoMessage() {}
+
+func (x *Response_Crash) ProtoReflect() protoreflect.Message {
+	mi := &file_response_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Response_Crash.ProtoReflect.Descriptor instead.
+func (*Response_Crash) Descriptor() ([]byte, []int) {
+	return file_response_proto_rawDescGZIP(), []int{0, 1}
+}
+
+func (x *Response_Crash) GetSignal() string {
+	if x != nil {
+		return x.Signal
+	}
+	return ""
+}
+
+func (x *Response_Crash) GetAddr() uint64 {
+	if x != nil {
+		return x.Addr
+	}
+	return 0
+}
+
+func (x *Response_Crash) GetStack() []string {
+	if x != nil {
+		return x.Stack
+	}
+	return nil
+}
+
+func (x *Response_Crash) GetCoreFileID() string {
+	if x != nil {
+		return x.CoreFileID
+	}
+	return ""
+}
+
+type Response_ProcessInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pid           int32                  `protobuf:"varint,1,opt,name=pid" json:"pid,omitempty"`
+	Ppid          int32                  `protobuf:"varint,2,opt,name=ppid" json:"ppid,omitempty"`
+	Args          []string               `protobuf:"bytes,3,rep,name=args" json:"args,omitempty"`
+	Start         uint64                 `protobuf:"varint,4,opt,name=start" json:"start,omitempty"`
+	End           uint64                 `protobuf:"varint,5,opt,name=end" json:"end,omitempty"`
+	ExitStatus    int32                  `protobuf:"varint,6,opt,name=exitStatus" json:"exitStatus,omitempty"`
+	Signal        string                 `protobuf:"bytes,7,opt,name=signal" json:"signal,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Response_ProcessInfo) Reset() {
+	*x = Response_ProcessInfo{}
+	mi := &file_response_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Response_ProcessInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Response_ProcessInfo) ProtoMessage() {}
+
+func (x *Response_ProcessInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_response_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Response_ProcessInfo.ProtoReflect.Descriptor instead.
+func (*Response_ProcessInfo) Descriptor() ([]byte, []int) {
+	return file_response_proto_rawDescGZIP(), []int{0, 2}
+}
+
+func (x *Response_ProcessInfo) GetPid() int32 {
+	if x != nil {
+		return x.Pid
+	}
+	return 0
+}
+
+func (x *Response_ProcessInfo) GetPpid() int32 {
+	if x != nil {
+		return x.Ppid
+	}
+	return 0
+}
+
+func (x *Response_ProcessInfo) GetArgs() []string {
+	if x != nil {
+		return x.Args
+	}
+	return nil
+}
+
+func (x *Response_ProcessInfo) GetStart() uint64 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *Response_ProcessInfo) GetEnd() uint64 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+func (x *Response_ProcessInfo) GetExitStatus() int32 {
+	if x != nil {
+		return x.ExitStatus
+	}
+	return 0
+}
+
+func (x *Response_ProcessInfo) GetSignal() string {
+	if x != nil {
+		return x.Signal
+	}
+	return ""
+}
+
+type Response_PerfStat struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskClock     uint64                 `protobuf:"varint,1,opt,name=taskClock" json:"taskClock,omitempty"`
+	Instructions  uint64                 `protobuf:"varint,2,opt,name=instructions" json:"instructions,omitempty"`
+	Cycles        uint64                 `protobuf:"varint,3,opt,name=cycles" json:"cycles,omitempty"`
+	Unsupported   []string               `protobuf:"bytes,4,rep,name=unsupported" json:"unsupported,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Response_PerfStat) Reset() {
+	*x = Response_PerfStat{}
+	mi := &file_response_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Response_PerfStat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Response_PerfStat) ProtoMessage() {}
+
+func (x *Response_PerfStat) ProtoReflect() protoreflect.Message {
+	mi := &file_response_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Response_PerfStat.ProtoReflect.Descriptor instead.
+func (*Response_PerfStat) Descriptor() ([]byte, []int) {
+	return file_response_proto_rawDescGZIP(), []int{0, 3}
+}
+
+func (x *Response_PerfStat) GetTaskClock() uint64 {
+	if x != nil {
+		return x.TaskClock
+	}
+	return 0
+}
+
+func (x *Response_PerfStat) GetInstructions() uint64 {
+	if x != nil {
+		return x.Instructions
+	}
+	return 0
+}
+
+func (x *Response_PerfStat) GetCycles() uint64 {
+	if x != nil {
+		return x.Cycles
+	}
+	return 0
+}
+
+func (x *Response_PerfStat) GetUnsupported() []string {
+	if x != nil {
+		return x.Unsupported
+	}
+	return nil
+}
+
+type Response_Attempt struct {
 	state         protoimpl.MessageState     `protogen:"open.v1"`
 	Status        Response_Result_StatusType `protobuf:"varint,1,opt,name=status,enum=pb.Response_Result_StatusType" json:"status,omitempty"`
 	ExitStatus    int32                      `protobuf:"varint,2,opt,name=exitStatus" json:"exitStatus,omitempty"`
-	Error         string                     `protobuf:"bytes,3,opt,name=error" json:"error,omitempty"`
-	Time          uint64                     `protobuf:"varint,4,opt,name=time" json:"time,omitempty"`
-	RunTime       uint64                     `protobuf:"varint,8,opt,name=runTime" json:"runTime,omitempty"`
-	ProcPeak      uint64                     `protobuf:"varint,10,opt,name=procPeak" json:"procPeak,omitempty"`
+	Time          uint64                     `protobuf:"varint,3,opt,name=time" json:"time,omitempty"`
+	RunTime       uint64                     `protobuf:"varint,4,opt,name=runTime" json:"runTime,omitempty"`
 	Memory        uint64                     `protobuf:"varint,5,opt,name=memory" json:"memory,omitempty"`
-	Files         map[string][]byte          `protobuf:"bytes,6,rep,name=files" json:"files,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	FileIDs       map[string]string          `protobuf:"bytes,7,rep,name=fileIDs" json:"fileIDs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	FileError     []*Response_FileError      `protobuf:"bytes,9,rep,name=fileError" json:"fileError,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
+func (x *Response_Attempt) Reset() {
+	*x = Response_Attempt{}
+	mi := &file_response_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Response_Attempt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Response_Attempt) ProtoMessage() {}
+
+func (x *Response_Attempt) ProtoReflect() protoreflect.Message {
+	mi := &file_response_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Response_Attempt.ProtoReflect.Descriptor instead.
+func (*Response_Attempt) Descriptor() ([]byte, []int) {
+	return file_response_proto_rawDescGZIP(), []int{0, 4}
+}
+
+func (x *Response_Attempt) GetStatus() Response_Result_StatusType {
+	if x != nil {
+		return x.Status
+	}
+	return Response_Result_Invalid
+}
+
+func (x *Response_Attempt) GetExitStatus() int32 {
+	if x != nil {
+		return x.ExitStatus
+	}
+	return 0
+}
+
+func (x *Response_Attempt) GetTime() uint64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+func (x *Response_Attempt) GetRunTime() uint64 {
+	if x != nil {
+		return x.RunTime
+	}
+	return 0
+}
+
+func (x *Response_Attempt) GetMemory() uint64 {
+	if x != nil {
+		return x.Memory
+	}
+	return 0
+}
+
+type Response_Limits struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	CpuLimit       uint64                 `protobuf:"varint,1,opt,name=cpuLimit" json:"cpuLimit,omitempty"`
+	ClockLimit     uint64                 `protobuf:"varint,2,opt,name=clockLimit" json:"clockLimit,omitempty"`
+	MemoryLimit    uint64                 `protobuf:"varint,3,opt,name=memoryLimit" json:"memoryLimit,omitempty"`
+	StackLimit     uint64                 `protobuf:"varint,4,opt,name=stackLimit" json:"stackLimit,omitempty"`
+	OutputLimit    uint64                 `protobuf:"varint,5,opt,name=outputLimit" json:"outputLimit,omitempty"`
+	ProcLimit      uint64                 `protobuf:"varint,6,opt,name=procLimit" json:"procLimit,omitempty"`
+	OpenFileLimit  uint64                 `protobuf:"varint,7,opt,name=openFileLimit" json:"openFileLimit,omitempty"`
+	CpuRateLimit   uint64                 `protobuf:"varint,8,opt,name=cpuRateLimit" json:"cpuRateLimit,omitempty"`
+	DiskLimit      uint64                 `protobuf:"varint,9,opt,name=diskLimit" json:"diskLimit,omitempty"`
+	FileCountLimit uint64                 `protobuf:"varint,10,opt,name=fileCountLimit" json:"fileCountLimit,omitempty"`
+	CopyOutMax     uint64                 `protobuf:"varint,11,opt,name=copyOutMax" json:"copyOutMax,omitempty"`
+	IoReadBps      uint64                 `protobuf:"varint,12,opt,name=ioReadBps" json:"ioReadBps,omitempty"`
+	IoWriteBps     uint64                 `protobuf:"varint,13,opt,name=ioWriteBps" json:"ioWriteBps,omitempty"`
+	IoReadIops     uint64                 `protobuf:"varint,14,opt,name=ioReadIops" json:"ioReadIops,omitempty"`
+	IoWriteIops    uint64                 `protobuf:"varint,15,opt,name=ioWriteIops" json:"ioWriteIops,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Response_Limits) Reset() {
+	*x = Response_Limits{}
+	mi := &file_response_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Response_Limits) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Response_Limits) ProtoMessage() {}
+
+func (x *Response_Limits) ProtoReflect() protoreflect.Message {
+	mi := &file_response_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Response_Limits.ProtoReflect.Descriptor instead.
+func (*Response_Limits) Descriptor() ([]byte, []int) {
+	return file_response_proto_rawDescGZIP(), []int{0, 5}
+}
+
+func (x *Response_Limits) GetCpuLimit() uint64 {
+	if x != nil {
+		return x.CpuLimit
+	}
+	return 0
+}
+
+func (x *Response_Limits) GetClockLimit() uint64 {
+	if x != nil {
+		return x.ClockLimit
+	}
+	return 0
+}
+
+func (x *Response_Limits) GetMemoryLimit() uint64 {
+	if x != nil {
+		return x.MemoryLimit
+	}
+	return 0
+}
+
+func (x *Response_Limits) GetStackLimit() uint64 {
+	if x != nil {
+		return x.StackLimit
+	}
+	return 0
+}
+
+func (x *Response_Limits) GetOutputLimit() uint64 {
+	if x != nil {
+		return x.OutputLimit
+	}
+	return 0
+}
+
+func (x *Response_Limits) GetProcLimit() uint64 {
+	if x != nil {
+		return x.ProcLimit
+	}
+	return 0
+}
+
+func (x *Response_Limits) GetOpenFileLimit() uint64 {
+	if x != nil {
+		return x.OpenFileLimit
+	}
+	return 0
+}
+
+func (x *Response_Limits) GetCpuRateLimit() uint64 {
+	if x != nil {
+		return x.CpuRateLimit
+	}
+	return 0
+}
+
+func (x *Response_Limits) GetDiskLimit() uint64 {
+	if x != nil {
+		return x.DiskLimit
+	}
+	return 0
+}
+
+func (x *Response_Limits) GetFileCountLimit() uint64 {
+	if x != nil {
+		return x.FileCountLimit
+	}
+	return 0
+}
+
+func (x *Response_Limits) GetCopyOutMax() uint64 {
+	if x != nil {
+		return x.CopyOutMax
+	}
+	return 0
+}
+
+func (x *Response_Limits) GetIoReadBps() uint64 {
+	if x != nil {
+		return x.IoReadBps
+	}
+	return 0
+}
+
+func (x *Response_Limits) GetIoWriteBps() uint64 {
+	if x != nil {
+		return x.IoWriteBps
+	}
+	return 0
+}
+
+func (x *Response_Limits) GetIoReadIops() uint64 {
+	if x != nil {
+		return x.IoReadIops
+	}
+	return 0
+}
+
+func (x *Response_Limits) GetIoWriteIops() uint64 {
+	if x != nil {
+		return x.IoWriteIops
+	}
+	return 0
+}
+
+type Response_Result struct {
+	state            protoimpl.MessageState     `protogen:"open.v1"`
+	Status           Response_Result_StatusType `protobuf:"varint,1,opt,name=status,enum=pb.Response_Result_StatusType" json:"status,omitempty"`
+	ExitStatus       int32                      `protobuf:"varint,2,opt,name=exitStatus" json:"exitStatus,omitempty"`
+	Error            string                     `protobuf:"bytes,3,opt,name=error" json:"error,omitempty"`
+	Time             uint64                     `protobuf:"varint,4,opt,name=time" json:"time,omitempty"`
+	RunTime          uint64                     `protobuf:"varint,8,opt,name=runTime" json:"runTime,omitempty"`
+	ProcPeak         uint64                     `protobuf:"varint,10,opt,name=procPeak" json:"procPeak,omitempty"`
+	Memory           uint64                     `protobuf:"varint,5,opt,name=memory" json:"memory,omitempty"`
+	Files            map[string][]byte          `protobuf:"bytes,6,rep,name=files" json:"files,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	FileIDs          map[string]string          `protobuf:"bytes,7,rep,name=fileIDs" json:"fileIDs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	FileError        []*Response_FileError      `protobuf:"bytes,9,rep,name=fileError" json:"fileError,omitempty"`
+	Syscalls         []string                   `protobuf:"bytes,11,rep,name=syscalls" json:"syscalls,omitempty"`
+	DiskUsage        uint64                     `protobuf:"varint,12,opt,name=diskUsage" json:"diskUsage,omitempty"`
+	IoRead           uint64                     `protobuf:"varint,13,opt,name=ioRead" json:"ioRead,omitempty"`
+	IoWrite          uint64                     `protobuf:"varint,14,opt,name=ioWrite" json:"ioWrite,omitempty"`
+	MemoryHighEvents uint64                     `protobuf:"varint,15,opt,name=memoryHighEvents" json:"memoryHighEvents,omitempty"`
+	Crash            *Response_Crash            `protobuf:"bytes,16,opt,name=crash" json:"crash,omitempty"`
+	Processes        []*Response_ProcessInfo    `protobuf:"bytes,17,rep,name=processes" json:"processes,omitempty"`
+	Perf             *Response_PerfStat         `protobuf:"bytes,18,opt,name=perf" json:"perf,omitempty"`
+	Attempts         []*Response_Attempt        `protobuf:"bytes,19,rep,name=attempts" json:"attempts,omitempty"`
+	MinTime          uint64                     `protobuf:"varint,20,opt,name=minTime" json:"minTime,omitempty"`
+	MedianTime       uint64                     `protobuf:"varint,21,opt,name=medianTime" json:"medianTime,omitempty"`
+	Limits           *Response_Limits           `protobuf:"bytes,22,opt,name=limits" json:"limits,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
 func (x *Response_Result) Reset() {
 	*x = Response_Result{}
-	mi := &file_response_proto_msgTypes[2]
+	mi := &file_response_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -323,7 +798,7 @@ func (x *Response_Result) String() string {
 func (*Response_Result) ProtoMessage() {}
 
 func (x *Response_Result) ProtoReflect() protoreflect.Message {
-	mi := &file_response_proto_msgTypes[2]
+	mi := &file_response_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -336,7 +811,7 @@ func (x *Response_Result) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Response_Result.ProtoReflect.Descriptor instead.
 func (*Response_Result) Descriptor() ([]byte, []int) {
-	return file_response_proto_rawDescGZIP(), []int{0, 1}
+	return file_response_proto_rawDescGZIP(), []int{0, 6}
 }
 
 func (x *Response_Result) GetStatus() Response_Result_StatusType {
@@ -409,11 +884,95 @@ func (x *Response_Result) GetFileError() []*Response_FileError {
 	return nil
 }
 
+func (x *Response_Result) GetSyscalls() []string {
+	if x != nil {
+		return x.Syscalls
+	}
+	return nil
+}
+
+func (x *Response_Result) GetDiskUsage() uint64 {
+	if x != nil {
+		return x.DiskUsage
+	}
+	return 0
+}
+
+func (x *Response_Result) GetIoRead() uint64 {
+	if x != nil {
+		return x.IoRead
+	}
+	return 0
+}
+
+func (x *Response_Result) GetIoWrite() uint64 {
+	if x != nil {
+		return x.IoWrite
+	}
+	return 0
+}
+
+func (x *Response_Result) GetMemoryHighEvents() uint64 {
+	if x != nil {
+		return x.MemoryHighEvents
+	}
+	return 0
+}
+
+func (x *Response_Result) GetCrash() *Response_Crash {
+	if x != nil {
+		return x.Crash
+	}
+	return nil
+}
+
+func (x *Response_Result) GetProcesses() []*Response_ProcessInfo {
+	if x != nil {
+		return x.Processes
+	}
+	return nil
+}
+
+func (x *Response_Result) GetPerf() *Response_PerfStat {
+	if x != nil {
+		return x.Perf
+	}
+	return nil
+}
+
+func (x *Response_Result) GetAttempts() []*Response_Attempt {
+	if x != nil {
+		return x.Attempts
+	}
+	return nil
+}
+
+func (x *Response_Result) GetMinTime() uint64 {
+	if x != nil {
+		return x.MinTime
+	}
+	return 0
+}
+
+func (x *Response_Result) GetMedianTime() uint64 {
+	if x != nil {
+		return x.MedianTime
+	}
+	return 0
+}
+
+func (x *Response_Result) GetLimits() *Response_Limits {
+	if x != nil {
+		return x.Limits
+	}
+	return nil
+}
+
 var File_response_proto protoreflect.FileDescriptor
 
 var file_response_proto_rawDesc = string([]byte{
 	0x0a, 0x0e, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x12, 0x02, 0x70, 0x62, 0x22, 0xe6, 0x09, 0x0a, 0x08, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x12, 0x02, 0x70, 0x62, 0x22, 0xfc, 0x15, 0x0a, 0x08, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
 	0x65, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x44, 0x18, 0x01,
 	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x44, 0x12,
 	0x2d, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
@@ -442,59 +1001,157 @@ var file_response_proto_rawDesc = string([]byte{
 	0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x10, 0x07, 0x12, 0x17, 0x0a, 0x13, 0x43, 0x6f, 0x6c,
 	0x6c, 0x65, 0x63, 0x74, 0x53, 0x69, 0x7a, 0x65, 0x45, 0x78, 0x63, 0x65, 0x65, 0x64, 0x65, 0x64,
 	0x10, 0x08, 0x12, 0x0b, 0x0a, 0x07, 0x53, 0x79, 0x6d, 0x6c, 0x69, 0x6e, 0x6b, 0x10, 0x09, 0x1a,
-	0x9b, 0x06, 0x0a, 0x06, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x36, 0x0a, 0x06, 0x73, 0x74,
-	0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1e, 0x2e, 0x70, 0x62, 0x2e,
+	0x69, 0x0a, 0x05, 0x43, 0x72, 0x61, 0x73, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x69, 0x67, 0x6e,
+	0x61, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x6c,
+	0x12, 0x12, 0x0a, 0x04, 0x61, 0x64, 0x64, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04,
+	0x61, 0x64, 0x64, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x63, 0x6b, 0x18, 0x03, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x63, 0x6b, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f,
+	0x72, 0x65, 0x46, 0x69, 0x6c, 0x65, 0x49, 0x44, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
+	0x63, 0x6f, 0x72, 0x65, 0x46, 0x69, 0x6c, 0x65, 0x49, 0x44, 0x1a, 0xa7, 0x01, 0x0a, 0x0b, 0x50,
+	0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x10, 0x0a, 0x03, 0x70, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x70, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04,
+	0x70, 0x70, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x70, 0x70, 0x69, 0x64,
+	0x12, 0x12, 0x0a, 0x04, 0x61, 0x72, 0x67, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04,
+	0x61, 0x72, 0x67, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x6e,
+	0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x12, 0x1e, 0x0a, 0x0a,
+	0x65, 0x78, 0x69, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x0a, 0x65, 0x78, 0x69, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x16, 0x0a, 0x06,
+	0x73, 0x69, 0x67, 0x6e, 0x61, 0x6c, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x69,
+	0x67, 0x6e, 0x61, 0x6c, 0x1a, 0x86, 0x01, 0x0a, 0x08, 0x50, 0x65, 0x72, 0x66, 0x53, 0x74, 0x61,
+	0x74, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x61, 0x73, 0x6b, 0x43, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x74, 0x61, 0x73, 0x6b, 0x43, 0x6c, 0x6f, 0x63, 0x6b, 0x12,
+	0x22, 0x0a, 0x0c, 0x69, 0x6e, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x69, 0x6e, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x73, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x06, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x75,
+	0x6e, 0x73, 0x75, 0x70, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x0b, 0x75, 0x6e, 0x73, 0x75, 0x70, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x1a, 0xa7, 0x01,
+	0x0a, 0x07, 0x41, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x12, 0x36, 0x0a, 0x06, 0x73, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1e, 0x2e, 0x70, 0x62, 0x2e, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x2e, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x54, 0x79, 0x70, 0x65, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x65, 0x78, 0x69, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x65, 0x78, 0x69, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x75, 0x6e, 0x54, 0x69, 0x6d, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x72, 0x75, 0x6e, 0x54, 0x69, 0x6d, 0x65, 0x12,
+	0x16, 0x0a, 0x06, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x06, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x1a, 0xf6, 0x03, 0x0a, 0x06, 0x4c, 0x69, 0x6d, 0x69,
+	0x74, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x70, 0x75, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x63, 0x70, 0x75, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x1e,
+	0x0a, 0x0a, 0x63, 0x6c, 0x6f, 0x63, 0x6b, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x0a, 0x63, 0x6c, 0x6f, 0x63, 0x6b, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x20,
+	0x0a, 0x0b, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x0b, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x4c, 0x69, 0x6d, 0x69, 0x74,
+	0x12, 0x1e, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x63, 0x6b, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x73, 0x74, 0x61, 0x63, 0x6b, 0x4c, 0x69, 0x6d, 0x69, 0x74,
+	0x12, 0x20, 0x0a, 0x0b, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x4c, 0x69, 0x6d,
+	0x69, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x72, 0x6f, 0x63, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x63, 0x4c, 0x69, 0x6d, 0x69, 0x74,
+	0x12, 0x24, 0x0a, 0x0d, 0x6f, 0x70, 0x65, 0x6e, 0x46, 0x69, 0x6c, 0x65, 0x4c, 0x69, 0x6d, 0x69,
+	0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0d, 0x6f, 0x70, 0x65, 0x6e, 0x46, 0x69, 0x6c,
+	0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x22, 0x0a, 0x0c, 0x63, 0x70, 0x75, 0x52, 0x61, 0x74,
+	0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x63, 0x70,
+	0x75, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x64, 0x69,
+	0x73, 0x6b, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x64,
+	0x69, 0x73, 0x6b, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x26, 0x0a, 0x0e, 0x66, 0x69, 0x6c, 0x65,
+	0x43, 0x6f, 0x75, 0x6e, 0x74, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x0e, 0x66, 0x69, 0x6c, 0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x4c, 0x69, 0x6d, 0x69, 0x74,
+	0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x70, 0x79, 0x4f, 0x75, 0x74, 0x4d, 0x61, 0x78, 0x18, 0x0b,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x63, 0x6f, 0x70, 0x79, 0x4f, 0x75, 0x74, 0x4d, 0x61, 0x78,
+	0x12, 0x1c, 0x0a, 0x09, 0x69, 0x6f, 0x52, 0x65, 0x61, 0x64, 0x42, 0x70, 0x73, 0x18, 0x0c, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x09, 0x69, 0x6f, 0x52, 0x65, 0x61, 0x64, 0x42, 0x70, 0x73, 0x12, 0x1e,
+	0x0a, 0x0a, 0x69, 0x6f, 0x57, 0x72, 0x69, 0x74, 0x65, 0x42, 0x70, 0x73, 0x18, 0x0d, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x0a, 0x69, 0x6f, 0x57, 0x72, 0x69, 0x74, 0x65, 0x42, 0x70, 0x73, 0x12, 0x1e,
+	0x0a, 0x0a, 0x69, 0x6f, 0x52, 0x65, 0x61, 0x64, 0x49, 0x6f, 0x70, 0x73, 0x18, 0x0e, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x0a, 0x69, 0x6f, 0x52, 0x65, 0x61, 0x64, 0x49, 0x6f, 0x70, 0x73, 0x12, 0x20,
+	0x0a, 0x0b, 0x69, 0x6f, 0x57, 0x72, 0x69, 0x74, 0x65, 0x49, 0x6f, 0x70, 0x73, 0x18, 0x0f, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x0b, 0x69, 0x6f, 0x57, 0x72, 0x69, 0x74, 0x65, 0x49, 0x6f, 0x70, 0x73,
+	0x1a, 0xf0, 0x09, 0x0a, 0x06, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x36, 0x0a, 0x06, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1e, 0x2e, 0x70, 0x62,
+	0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x54, 0x79, 0x70, 0x65, 0x52, 0x06, 0x73, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x65, 0x78, 0x69, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x65, 0x78, 0x69, 0x74, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x6d,
+	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x18, 0x0a,
+	0x07, 0x72, 0x75, 0x6e, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07,
+	0x72, 0x75, 0x6e, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x63, 0x50,
+	0x65, 0x61, 0x6b, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x63, 0x50,
+	0x65, 0x61, 0x6b, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x06, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x12, 0x34, 0x0a, 0x05, 0x66,
+	0x69, 0x6c, 0x65, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x70, 0x62, 0x2e,
 	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x2e,
-	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x54, 0x79, 0x70, 0x65, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74,
-	0x75, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x65, 0x78, 0x69, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x65, 0x78, 0x69, 0x74, 0x53, 0x74, 0x61, 0x74,
-	0x75, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65,
-	0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07,
-	0x72, 0x75, 0x6e, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x72,
-	0x75, 0x6e, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x63, 0x50, 0x65,
-	0x61, 0x6b, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x63, 0x50, 0x65,
-	0x61, 0x6b, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x18, 0x05, 0x20, 0x01,
-	0x28, 0x04, 0x52, 0x06, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x12, 0x34, 0x0a, 0x05, 0x66, 0x69,
-	0x6c, 0x65, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x70, 0x62, 0x2e, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x2e, 0x46,
-	0x69, 0x6c, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73,
-	0x12, 0x3a, 0x0a, 0x07, 0x66, 0x69, 0x6c, 0x65, 0x49, 0x44, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28,
-	0x0b, 0x32, 0x20, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e,
-	0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x49, 0x44, 0x73, 0x45, 0x6e,
-	0x74, 0x72, 0x79, 0x52, 0x07, 0x66, 0x69, 0x6c, 0x65, 0x49, 0x44, 0x73, 0x12, 0x34, 0x0a, 0x09,
-	0x66, 0x69, 0x6c, 0x65, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32,
-	0x16, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x46, 0x69,
-	0x6c, 0x65, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x09, 0x66, 0x69, 0x6c, 0x65, 0x45, 0x72, 0x72,
-	0x6f, 0x72, 0x1a, 0x38, 0x0a, 0x0a, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
-	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b,
-	0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x3a, 0x0a, 0x0c,
-	0x46, 0x69, 0x6c, 0x65, 0x49, 0x44, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03,
-	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14,
-	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76,
-	0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xa2, 0x02, 0x0a, 0x0a, 0x53, 0x74, 0x61,
-	0x74, 0x75, 0x73, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x49, 0x6e, 0x76, 0x61, 0x6c,
-	0x69, 0x64, 0x10, 0x00, 0x12, 0x0c, 0x0a, 0x08, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64,
-	0x10, 0x01, 0x12, 0x0f, 0x0a, 0x0b, 0x57, 0x72, 0x6f, 0x6e, 0x67, 0x41, 0x6e, 0x73, 0x77, 0x65,
-	0x72, 0x10, 0x02, 0x12, 0x14, 0x0a, 0x10, 0x50, 0x61, 0x72, 0x74, 0x69, 0x61, 0x6c, 0x6c, 0x79,
-	0x43, 0x6f, 0x72, 0x72, 0x65, 0x63, 0x74, 0x10, 0x03, 0x12, 0x17, 0x0a, 0x13, 0x4d, 0x65, 0x6d,
-	0x6f, 0x72, 0x79, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x45, 0x78, 0x63, 0x65, 0x65, 0x64, 0x65, 0x64,
-	0x10, 0x04, 0x12, 0x15, 0x0a, 0x11, 0x54, 0x69, 0x6d, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x45,
-	0x78, 0x63, 0x65, 0x65, 0x64, 0x65, 0x64, 0x10, 0x05, 0x12, 0x17, 0x0a, 0x13, 0x4f, 0x75, 0x74,
-	0x70, 0x75, 0x74, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x45, 0x78, 0x63, 0x65, 0x65, 0x64, 0x65, 0x64,
-	0x10, 0x06, 0x12, 0x0d, 0x0a, 0x09, 0x46, 0x69, 0x6c, 0x65, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x10,
-	0x07, 0x12, 0x15, 0x0a, 0x11, 0x4e, 0x6f, 0x6e, 0x5a, 0x65, 0x72, 0x6f, 0x45, 0x78, 0x69, 0x74,
-	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x10, 0x08, 0x12, 0x0d, 0x0a, 0x09, 0x53, 0x69, 0x67, 0x6e,
-	0x61, 0x6c, 0x6c, 0x65, 0x64, 0x10, 0x09, 0x12, 0x14, 0x0a, 0x10, 0x44, 0x61, 0x6e, 0x67, 0x65,
-	0x72, 0x6f, 0x75, 0x73, 0x53, 0x79, 0x73, 0x63, 0x61, 0x6c, 0x6c, 0x10, 0x0a, 0x12, 0x13, 0x0a,
-	0x0f, 0x4a, 0x75, 0x64, 0x67, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x46, 0x61, 0x69, 0x6c, 0x65, 0x64,
-	0x10, 0x0b, 0x12, 0x16, 0x0a, 0x12, 0x49, 0x6e, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x49, 0x6e, 0x74,
-	0x65, 0x72, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x10, 0x0c, 0x12, 0x11, 0x0a, 0x0d, 0x49, 0x6e,
-	0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x10, 0x0d, 0x42, 0x24, 0x5a,
-	0x1d, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x72, 0x69, 0x79,
-	0x6c, 0x65, 0x2f, 0x67, 0x6f, 0x2d, 0x6a, 0x75, 0x64, 0x67, 0x65, 0x2f, 0x70, 0x62, 0x92, 0x03,
-	0x02, 0x08, 0x02, 0x62, 0x08, 0x65, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x70, 0xe8, 0x07,
+	0x46, 0x69, 0x6c, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x05, 0x66, 0x69, 0x6c, 0x65,
+	0x73, 0x12, 0x3a, 0x0a, 0x07, 0x66, 0x69, 0x6c, 0x65, 0x49, 0x44, 0x73, 0x18, 0x07, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x20, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x49, 0x44, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x66, 0x69, 0x6c, 0x65, 0x49, 0x44, 0x73, 0x12, 0x34, 0x0a,
+	0x09, 0x66, 0x69, 0x6c, 0x65, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x16, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x46,
+	0x69, 0x6c, 0x65, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x09, 0x66, 0x69, 0x6c, 0x65, 0x45, 0x72,
+	0x72, 0x6f, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x79, 0x73, 0x63, 0x61, 0x6c, 0x6c, 0x73, 0x18,
+	0x0b, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x73, 0x79, 0x73, 0x63, 0x61, 0x6c, 0x6c, 0x73, 0x12,
+	0x1c, 0x0a, 0x09, 0x64, 0x69, 0x73, 0x6b, 0x55, 0x73, 0x61, 0x67, 0x65, 0x18, 0x0c, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x09, 0x64, 0x69, 0x73, 0x6b, 0x55, 0x73, 0x61, 0x67, 0x65, 0x12, 0x16, 0x0a,
+	0x06, 0x69, 0x6f, 0x52, 0x65, 0x61, 0x64, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x69,
+	0x6f, 0x52, 0x65, 0x61, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x69, 0x6f, 0x57, 0x72, 0x69, 0x74, 0x65,
+	0x18, 0x0e, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x69, 0x6f, 0x57, 0x72, 0x69, 0x74, 0x65, 0x12,
+	0x2a, 0x0a, 0x10, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x48, 0x69, 0x67, 0x68, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x73, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x04, 0x52, 0x10, 0x6d, 0x65, 0x6d, 0x6f, 0x72,
+	0x79, 0x48, 0x69, 0x67, 0x68, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x28, 0x0a, 0x05, 0x63,
+	0x72, 0x61, 0x73, 0x68, 0x18, 0x10, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x62, 0x2e,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x43, 0x72, 0x61, 0x73, 0x68, 0x52, 0x05,
+	0x63, 0x72, 0x61, 0x73, 0x68, 0x12, 0x36, 0x0a, 0x09, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73,
+	0x65, 0x73, 0x18, 0x11, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x49, 0x6e,
+	0x66, 0x6f, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x65, 0x73, 0x12, 0x29, 0x0a,
+	0x04, 0x70, 0x65, 0x72, 0x66, 0x18, 0x12, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x62,
+	0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x50, 0x65, 0x72, 0x66, 0x53, 0x74,
+	0x61, 0x74, 0x52, 0x04, 0x70, 0x65, 0x72, 0x66, 0x12, 0x30, 0x0a, 0x08, 0x61, 0x74, 0x74, 0x65,
+	0x6d, 0x70, 0x74, 0x73, 0x18, 0x13, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x62, 0x2e,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x41, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74,
+	0x52, 0x08, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x69,
+	0x6e, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x14, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x6d, 0x69, 0x6e,
+	0x54, 0x69, 0x6d, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x6e, 0x54, 0x69,
+	0x6d, 0x65, 0x18, 0x15, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x6e,
+	0x54, 0x69, 0x6d, 0x65, 0x12, 0x2b, 0x0a, 0x06, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x18, 0x16,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x2e, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x52, 0x06, 0x6c, 0x69, 0x6d, 0x69, 0x74,
+	0x73, 0x1a, 0x38, 0x0a, 0x0a, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x3a, 0x0a, 0x0c, 0x46,
+	0x69, 0x6c, 0x65, 0x49, 0x44, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b,
+	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xb9, 0x02, 0x0a, 0x0a, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x49, 0x6e, 0x76, 0x61, 0x6c, 0x69,
+	0x64, 0x10, 0x00, 0x12, 0x0c, 0x0a, 0x08, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64, 0x10,
+	0x01, 0x12, 0x0f, 0x0a, 0x0b, 0x57, 0x72, 0x6f, 0x6e, 0x67, 0x41, 0x6e, 0x73, 0x77, 0x65, 0x72,
+	0x10, 0x02, 0x12, 0x14, 0x0a, 0x10, 0x50, 0x61, 0x72, 0x74, 0x69, 0x61, 0x6c, 0x6c, 0x79, 0x43,
+	0x6f, 0x72, 0x72, 0x65, 0x63, 0x74, 0x10, 0x03, 0x12, 0x17, 0x0a, 0x13, 0x4d, 0x65, 0x6d, 0x6f,
+	0x72, 0x79, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x45, 0x78, 0x63, 0x65, 0x65, 0x64, 0x65, 0x64, 0x10,
+	0x04, 0x12, 0x15, 0x0a, 0x11, 0x54, 0x69, 0x6d, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x45, 0x78,
+	0x63, 0x65, 0x65, 0x64, 0x65, 0x64, 0x10, 0x05, 0x12, 0x17, 0x0a, 0x13, 0x4f, 0x75, 0x74, 0x70,
+	0x75, 0x74, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x45, 0x78, 0x63, 0x65, 0x65, 0x64, 0x65, 0x64, 0x10,
+	0x06, 0x12, 0x0d, 0x0a, 0x09, 0x46, 0x69, 0x6c, 0x65, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x10, 0x07,
+	0x12, 0x15, 0x0a, 0x11, 0x4e, 0x6f, 0x6e, 0x5a, 0x65, 0x72, 0x6f, 0x45, 0x78, 0x69, 0x74, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x10, 0x08, 0x12, 0x0d, 0x0a, 0x09, 0x53, 0x69, 0x67, 0x6e, 0x61,
+	0x6c, 0x6c, 0x65, 0x64, 0x10, 0x09, 0x12, 0x14, 0x0a, 0x10, 0x44, 0x61, 0x6e, 0x67, 0x65, 0x72,
+	0x6f, 0x75, 0x73, 0x53, 0x79, 0x73, 0x63, 0x61, 0x6c, 0x6c, 0x10, 0x0a, 0x12, 0x13, 0x0a, 0x0f,
+	0x4a, 0x75, 0x64, 0x67, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x46, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x10,
+	0x0b, 0x12, 0x16, 0x0a, 0x12, 0x49, 0x6e, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x49, 0x6e, 0x74, 0x65,
+	0x72, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x10, 0x0c, 0x12, 0x11, 0x0a, 0x0d, 0x49, 0x6e, 0x74,
+	0x65, 0x72, 0x6e, 0x61, 0x6c, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x10, 0x0d, 0x12, 0x15, 0x0a, 0x11,
+	0x44, 0x69, 0x73, 0x6b, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x45, 0x78, 0x63, 0x65, 0x65, 0x64, 0x65,
+	0x64, 0x10, 0x0e, 0x42, 0x24, 0x5a, 0x1d, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
+	0x6d, 0x2f, 0x63, 0x72, 0x69, 0x79, 0x6c, 0x65, 0x2f, 0x67, 0x6f, 0x2d, 0x6a, 0x75, 0x64, 0x67,
+	0x65, 0x2f, 0x70, 0x62, 0x92, 0x03, 0x02, 0x08, 0x02, 0x62, 0x08, 0x65, 0x64, 0x69, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x70, 0xe8, 0x07,
 })
 
 var (
@@ -510,28 +1167,39 @@ func file_response_proto_rawDescGZIP() []byte {
 }
 
 var file_response_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_response_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_response_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
 var file_response_proto_goTypes = []any{
 	(Response_FileError_ErrorType)(0), // 0: pb.Response.FileError.ErrorType
 	(Response_Result_StatusType)(0),   // 1: pb.Response.Result.StatusType
 	(*Response)(nil),                  // 2: pb.Response
 	(*Response_FileError)(nil),        // 3: pb.Response.FileError
-	(*Response_Result)(nil),           // 4: pb.Response.Result
-	nil,                               // 5: pb.Response.Result.FilesEntry
-	nil,                               // 6: pb.Response.Result.FileIDsEntry
+	(*Response_Crash)(nil),            // 4: pb.Response.Crash
+	(*Response_ProcessInfo)(nil),      // 5: pb.Response.ProcessInfo
+	(*Response_PerfStat)(nil),         // 6: pb.Response.PerfStat
+	(*Response_Attempt)(nil),          // 7: pb.Response.Attempt
+	(*Response_Limits)(nil),           // 8: pb.Response.Limits
+	(*Response_Result)(nil),           // 9: pb.Response.Result
+	nil,                               // 10: pb.Response.Result.FilesEntry
+	nil,                               // 11: pb.Response.Result.FileIDsEntry
 }
 var file_response_proto_depIdxs = []int32{
-	4, // 0: pb.Response.results:type_name -> pb.Response.Result
-	0, // 1: pb.Response.FileError.type:type_name -> pb.Response.FileError.ErrorType
-	1, // 2: pb.Response.Result.status:type_name -> pb.Response.Result.StatusType
-	5, // 3: pb.Response.Result.files:type_name -> pb.Response.Result.FilesEntry
-	6, // 4: pb.Response.Result.fileIDs:type_name -> pb.Response.Result.FileIDsEntry
-	3, // 5: pb.Response.Result.fileError:type_name -> pb.Response.FileError
-	6, // [6:6] is the sub-list for method output_type
-	6, // [6:6] is the sub-list for method input_type
-	6, // [6:6] is the sub-list for extension type_name
-	6, // [6:6] is the sub-list for extension extendee
-	0, // [0:6] is the sub-list for field type_name
+	9,  // 0: pb.Response.results:type_name -> pb.Response.Result
+	0,  // 1: pb.Response.FileError.type:type_name -> pb.Response.FileError.ErrorType
+	1,  // 2: pb.Response.Attempt.status:type_name -> pb.Response.Result.StatusType
+	1,  // 3: pb.Response.Result.status:type_name -> pb.Response.Result.StatusType
+	10, // 4: pb.Response.Result.files:type_name -> pb.Response.Result.FilesEntry
+	11, // 5: pb.Response.Result.fileIDs:type_name -> pb.Response.Result.FileIDsEntry
+	3,  // 6: pb.Response.Result.fileError:type_name -> pb.Response.FileError
+	4,  // 7: pb.Response.Result.crash:type_name -> pb.Response.Crash
+	5,  // 8: pb.Response.Result.processes:type_name -> pb.Response.ProcessInfo
+	6,  // 9: pb.Response.Result.perf:type_name -> pb.Response.PerfStat
+	7,  // 10: pb.Response.Result.attempts:type_name -> pb.Response.Attempt
+	8,  // 11: pb.Response.Result.limits:type_name -> pb.Response.Limits
+	12, // [12:12] is the sub-list for method output_type
+	12, // [12:12] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
 }
 
 func init() { file_response_proto_init() }
@@ -545,7 +1213,7 @@ func file_response_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_response_proto_rawDesc), len(file_response_proto_rawDesc)),
 			NumEnums:      2,
-			NumMessages:   5,
+			NumMessages:   10,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
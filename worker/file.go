@@ -21,6 +21,7 @@ var (
 	_ CmdFile = &MemoryFile{}
 	_ CmdFile = &CachedFile{}
 	_ CmdFile = &Collector{}
+	_ CmdFile = &ArchiveFile{}
 )
 
 // LocalFile defines file stores on the local file system
@@ -84,3 +85,23 @@ func (f *Collector) EnvFile(fs filestore.FileStore) (envexec.File, error) {
 func (f *Collector) String() string {
 	return fmt.Sprintf("collector:(name:%s,max:%d,pipe:%v)", f.Name, f.Max, f.Pipe)
 }
+
+// ArchiveFile defines archive (tar, tar.gz or zip) to be extracted into the
+// copy in directory
+type ArchiveFile struct {
+	File  CmdFile              // file of the archive
+	Limit envexec.ArchiveLimit // limit to extract, set by the worker
+}
+
+// EnvFile prepares file for envexec file
+func (f *ArchiveFile) EnvFile(fs filestore.FileStore) (envexec.File, error) {
+	ef, err := f.File.EnvFile(fs)
+	if err != nil {
+		return nil, err
+	}
+	return envexec.NewFileArchive(ef, f.Limit), nil
+}
+
+func (f *ArchiveFile) String() string {
+	return fmt.Sprintf("archive:(%v)", f.File)
+}
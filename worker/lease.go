@@ -0,0 +1,48 @@
+package worker
+
+import (
+	"fmt"
+
+	"github.com/criyle/go-judge/filestore"
+)
+
+// leaseFiles takes leases on the cached files referenced by the request, so
+// that they are not expired or evicted before the request finishes. The
+// returned release should be called when the request finished.
+func (w *worker) leaseFiles(req *Request) (release func(), err error) {
+	var leased []string
+	release = func() {
+		for _, id := range leased {
+			filestore.Release(w.fs, id)
+		}
+	}
+	lease := func(f CmdFile) error {
+		if af, ok := f.(*ArchiveFile); ok {
+			f = af.File
+		}
+		cf, ok := f.(*CachedFile)
+		if !ok {
+			return nil
+		}
+		if !filestore.Lease(w.fs, cf.FileID) {
+			return fmt.Errorf("file does not exists with id: %q", cf.FileID)
+		}
+		leased = append(leased, cf.FileID)
+		return nil
+	}
+	for _, c := range req.Cmd {
+		for _, f := range c.Files {
+			if err := lease(f); err != nil {
+				release()
+				return nil, err
+			}
+		}
+		for _, f := range c.CopyIn {
+			if err := lease(f); err != nil {
+				release()
+				return nil, err
+			}
+		}
+	}
+	return release, nil
+}
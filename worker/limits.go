@@ -0,0 +1,144 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Limits defines the resource limits of a command, zero means not set
+type Limits struct {
+	CPU         time.Duration
+	Clock       time.Duration
+	Memory      Size
+	Stack       Size
+	Output      Size
+	Proc        uint64
+	OpenFile    uint64
+	CPURate     uint64
+	Disk        Size
+	FileCount   uint64
+	CopyOut     Size
+	IOReadBps   Size
+	IOWriteBps  Size
+	IOReadIOPS  uint64
+	IOWriteIOPS uint64
+}
+
+// mergeLimits returns l with the zero limits set from d
+func mergeLimits(l, d Limits) Limits {
+	merge(&l.CPU, d.CPU)
+	merge(&l.Clock, d.Clock)
+	merge(&l.Memory, d.Memory)
+	merge(&l.Stack, d.Stack)
+	merge(&l.Output, d.Output)
+	merge(&l.Proc, d.Proc)
+	merge(&l.OpenFile, d.OpenFile)
+	merge(&l.CPURate, d.CPURate)
+	merge(&l.Disk, d.Disk)
+	merge(&l.FileCount, d.FileCount)
+	merge(&l.CopyOut, d.CopyOut)
+	merge(&l.IOReadBps, d.IOReadBps)
+	merge(&l.IOWriteBps, d.IOWriteBps)
+	merge(&l.IOReadIOPS, d.IOReadIOPS)
+	merge(&l.IOWriteIOPS, d.IOWriteIOPS)
+	return l
+}
+
+func merge[T comparable](v *T, d T) {
+	var zero T
+	if *v == zero {
+		*v = d
+	}
+}
+
+// limitsOf returns the limits of the command
+func limitsOf(c Cmd) Limits {
+	return Limits{
+		CPU:         c.CPULimit,
+		Clock:       c.ClockLimit,
+		Memory:      c.MemoryLimit,
+		Stack:       c.StackLimit,
+		Output:      c.OutputLimit,
+		Proc:        c.ProcLimit,
+		OpenFile:    c.OpenFileLimit,
+		CPURate:     c.CPURateLimit,
+		Disk:        c.DiskLimit,
+		FileCount:   c.FileCountLimit,
+		CopyOut:     Size(c.CopyOutMax),
+		IOReadBps:   c.IOReadBps,
+		IOWriteBps:  c.IOWriteBps,
+		IOReadIOPS:  c.IOReadIOPS,
+		IOWriteIOPS: c.IOWriteIOPS,
+	}
+}
+
+// limitCmds returns the copy of commands with effective limits
+func (w *worker) limitCmds(cmds []Cmd) ([]Cmd, error) {
+	cmds = slices.Clone(cmds)
+	for i := range cmds {
+		if err := w.applyLimits(&cmds[i]); err != nil {
+			return nil, fmt.Errorf("cmd %d: %w", i, err)
+		}
+	}
+	return cmds, nil
+}
+
+// applyLimits sets the zero limits of the command to the defaults and applies
+// the maximums by either clamping the limits or rejecting the command. Since
+// zero means unlimited for most limits, zero limits without default are set
+// to the maximums.
+func (w *worker) applyLimits(c *Cmd) error {
+	d, m, r := w.defaultLimits, w.maxLimits, w.rejectExceededLimits
+	cpuErr := applyLimit("cpuLimit", &c.CPULimit, d.CPU, m.CPU, r)
+	// zero clock limit falls back to the cpu limit
+	if c.ClockLimit == 0 && d.Clock == 0 {
+		c.ClockLimit = c.CPULimit
+	}
+	copyOutMax := Size(c.CopyOutMax)
+	syscallTraceMax := Size(c.SyscallTraceMax)
+	coreDumpMax := Size(c.CoreDumpMax)
+	err := errors.Join(
+		cpuErr,
+		applyLimit("clockLimit", &c.ClockLimit, d.Clock, m.Clock, r),
+		applyLimit("memoryLimit", &c.MemoryLimit, d.Memory, m.Memory, r),
+		applyLimit("stackLimit", &c.StackLimit, d.Stack, m.Stack, r),
+		applyLimit("outputLimit", &c.OutputLimit, d.Output, m.Output, r),
+		applyLimit("procLimit", &c.ProcLimit, d.Proc, m.Proc, r),
+		applyLimit("openFileLimit", &c.OpenFileLimit, d.OpenFile, m.OpenFile, r),
+		applyLimit("cpuRateLimit", &c.CPURateLimit, d.CPURate, m.CPURate, r),
+		applyLimit("diskLimit", &c.DiskLimit, d.Disk, m.Disk, r),
+		applyLimit("fileCountLimit", &c.FileCountLimit, d.FileCount, m.FileCount, r),
+		applyLimit("copyOutMax", &copyOutMax, d.CopyOut, m.CopyOut, r),
+		applyLimit("syscallTraceMax", &syscallTraceMax, d.CopyOut, m.CopyOut, r),
+		applyLimit("coreDumpMax", &coreDumpMax, d.CopyOut, m.CopyOut, r),
+		applyLimit("ioReadBps", &c.IOReadBps, d.IOReadBps, m.IOReadBps, r),
+		applyLimit("ioWriteBps", &c.IOWriteBps, d.IOWriteBps, m.IOWriteBps, r),
+		applyLimit("ioReadIops", &c.IOReadIOPS, d.IOReadIOPS, m.IOReadIOPS, r),
+		applyLimit("ioWriteIops", &c.IOWriteIOPS, d.IOWriteIOPS, m.IOWriteIOPS, r),
+	)
+	c.CopyOutMax = uint64(copyOutMax)
+	c.SyscallTraceMax = uint64(syscallTraceMax)
+	c.CoreDumpMax = uint64(coreDumpMax)
+	return err
+}
+
+// applyLimit sets the zero limit to default and applies the maximum
+func applyLimit[T ~int64 | ~uint64](name string, v *T, def, max T, reject bool) error {
+	if *v == 0 {
+		*v = def
+	}
+	switch {
+	case max == 0 || (*v > 0 && *v <= max):
+		return nil
+	case *v == 0:
+		*v = max
+		return nil
+	case reject:
+		return fmt.Errorf("%s %v exceeds the maximum %v", name, *v, max)
+	default:
+		*v = max
+		return nil
+	}
+}
@@ -24,6 +24,7 @@ type Cmd struct {
 	CPULimit      time.Duration
 	ClockLimit    time.Duration
 	MemoryLimit   Size
+	MemoryHigh    Size
 	StackLimit    Size
 	OutputLimit   Size
 	ProcLimit     uint64
@@ -31,6 +32,14 @@ type Cmd struct {
 	CPURateLimit  uint64
 	CPUSetLimit   string
 
+	DiskLimit      Size
+	FileCountLimit uint64
+
+	IOReadBps   Size
+	IOWriteBps  Size
+	IOReadIOPS  uint64
+	IOWriteIOPS uint64
+
 	CopyIn   map[string]CmdFile
 	Symlinks map[string]string
 
@@ -38,12 +47,49 @@ type Cmd struct {
 	CopyOutCached []CmdCopyOutFile
 	CopyOutMax    uint64
 	CopyOutDir    string
+	CopyOutBundle string
 
 	TTY               bool
 	DataSegmentLimit  bool
 	AddressSpaceLimit bool
+	SeccompAudit      bool
+
+	SyscallTrace    string
+	SyscallTraceMax uint64
+
+	CoreDump    bool
+	CoreDumpMax uint64
+
+	TraceProcess bool
+	Perf         bool
+
+	// Repeat reruns the command up to Repeat times when the time exceeds
+	// RepeatThreshold of CPULimit or TLE, and returns the accepted run with
+	// minimum time (or the run with minimum time if none is accepted)
+	Repeat          int
+	RepeatThreshold float64
+
+	// ScaleTime scales the time limits and time usage by the speed factor
+	ScaleTime bool
+
+	Network envexec.NetworkMode
+
+	// Role service starts first and is killed after the other commands finished
+	Role         CmdRole
+	ReadyPort    int
+	ReadyFile    string
+	ReadyTimeout time.Duration
 }
 
+// CmdRole defines the role of the command in a group request
+type CmdRole int
+
+// Defines command roles
+const (
+	RoleDefault CmdRole = iota
+	RoleService
+)
+
 // Request defines single worker request
 type Request struct {
 	RequestID   string
@@ -53,16 +99,36 @@ type Request struct {
 
 // Result defines single command response
 type Result struct {
-	Status     envexec.Status
-	ExitStatus int
-	Error      string
-	Time       time.Duration
-	RunTime    time.Duration
-	Memory     Size
-	ProcPeak   uint64
-	Files      map[string]*os.File
-	FileIDs    map[string]string
-	FileError  []FileError
+	Status           envexec.Status
+	ExitStatus       int
+	Error            string
+	Time             time.Duration
+	RunTime          time.Duration
+	Memory           Size
+	ProcPeak         uint64
+	DiskUsage        Size
+	IORead           Size
+	IOWrite          Size
+	MemoryHighEvents uint64
+	Files            map[string]*os.File
+	FileIDs          map[string]string
+	FileError        []FileError
+	Syscalls         []string
+	Crash            *Crash
+	Processes        []envexec.ProcessInfo
+	Perf             *envexec.PerfStat
+	Attempts         []Attempt
+	MinTime          time.Duration
+	MedianTime       time.Duration
+	Limits           *Limits
+}
+
+// Crash defines the crash details of a signalled command
+type Crash struct {
+	Signal     string
+	Addr       uint64
+	Stack      []string
+	CoreFileID string
 }
 
 // Response defines worker response for single request
@@ -74,28 +140,52 @@ type Response struct {
 
 func (r Result) String() string {
 	type Result struct {
-		Status     envexec.Status
-		ExitStatus int
-		Error      string
-		Time       time.Duration
-		RunTime    time.Duration
-		Memory     Size
-		ProcPeak   uint64
-		Files      map[string]string
-		FileIDs    map[string]string
-		FileError  []FileError
+		Status           envexec.Status
+		ExitStatus       int
+		Error            string
+		Time             time.Duration
+		RunTime          time.Duration
+		Memory           Size
+		ProcPeak         uint64
+		DiskUsage        Size
+		IORead           Size
+		IOWrite          Size
+		MemoryHighEvents uint64
+		Files            map[string]string
+		FileIDs          map[string]string
+		FileError        []FileError
+		Syscalls         []string
+		Crash            *Crash
+		Processes        []envexec.ProcessInfo
+		Perf             *envexec.PerfStat
+		Attempts         []Attempt
+		MinTime          time.Duration
+		MedianTime       time.Duration
+		Limits           *Limits
 	}
 	d := Result{
-		Status:     r.Status,
-		ExitStatus: r.ExitStatus,
-		Error:      r.Error,
-		Time:       r.Time,
-		RunTime:    r.RunTime,
-		Memory:     r.Memory,
-		ProcPeak:   r.ProcPeak,
-		Files:      make(map[string]string),
-		FileIDs:    r.FileIDs,
-		FileError:  r.FileError,
+		Status:           r.Status,
+		ExitStatus:       r.ExitStatus,
+		Error:            r.Error,
+		Time:             r.Time,
+		RunTime:          r.RunTime,
+		Memory:           r.Memory,
+		ProcPeak:         r.ProcPeak,
+		DiskUsage:        r.DiskUsage,
+		IORead:           r.IORead,
+		IOWrite:          r.IOWrite,
+		MemoryHighEvents: r.MemoryHighEvents,
+		Files:            make(map[string]string),
+		FileIDs:          r.FileIDs,
+		FileError:        r.FileError,
+		Syscalls:         r.Syscalls,
+		Crash:            r.Crash,
+		Processes:        r.Processes,
+		Perf:             r.Perf,
+		Attempts:         r.Attempts,
+		MinTime:          r.MinTime,
+		MedianTime:       r.MedianTime,
+		Limits:           r.Limits,
 	}
 	for k, v := range r.Files {
 		d.Files[k] = filepath.Base(v.Name())
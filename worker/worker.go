@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -22,6 +23,12 @@ type EnvironmentPool interface {
 	Destroy()
 }
 
+// NetworkEnvironmentPool is implemented by EnvironmentPool that is able to
+// create environments sharing a private network namespace
+type NetworkEnvironmentPool interface {
+	NewNetwork() (EnvironmentPool, error)
+}
+
 // Config defines worker configuration
 type Config struct {
 	FileStore             filestore.FileStore
@@ -33,7 +40,20 @@ type Config struct {
 	OutputLimit           envexec.Size
 	CopyOutLimit          envexec.Size
 	OpenFileLimit         uint64
+	IOReadBps             envexec.Size
+	IOWriteBps            envexec.Size
+	IOReadIOPS            uint64
+	IOWriteIOPS           uint64
+	EnableSyscallTrace    bool
+	DefaultLimits         Limits // defaults for zero limits, overrides the above defaults
+	MaxLimits             Limits // maximums, zero for no maximum
+	RejectExceededLimits  bool   // reject commands exceed maximums instead of clamping
+	RepeatThreshold       float64
+	RepeatMax             int
+	SpeedFactor           func() float64
 	ExecObserver          func(Response)
+	ArchiveLimit          envexec.ArchiveLimit // limit to extract copy in archives
+	NetShare              bool                 // commands run in the host network
 }
 
 // Worker defines interface for executor
@@ -60,9 +80,15 @@ type worker struct {
 
 	timeLimitTickInterval time.Duration
 	extraMemoryLimit      envexec.Size
-	outputLimit           envexec.Size
-	copyOutLimit          envexec.Size
-	openFileLimit         uint64
+	defaultLimits         Limits
+	maxLimits             Limits
+	rejectExceededLimits  bool
+	enableSyscallTrace    bool
+	repeatThreshold       float64
+	repeatMax             int
+	speedFactor           func() float64
+	archiveLimit          envexec.ArchiveLimit
+	netShare              bool
 
 	execObserver func(Response)
 
@@ -79,10 +105,21 @@ type workRequest struct {
 	context.Context
 	started  chan<- struct{}
 	resultCh chan<- Response
+	release  func() // release leases on cached files
 }
 
 // New creates new worker
 func New(conf Config) Worker {
+	// the former per limit defaults fill the limits not set in DefaultLimits
+	defaultLimits := mergeLimits(conf.DefaultLimits, Limits{
+		Output:      conf.OutputLimit,
+		CopyOut:     conf.CopyOutLimit,
+		OpenFile:    conf.OpenFileLimit,
+		IOReadBps:   conf.IOReadBps,
+		IOWriteBps:  conf.IOWriteBps,
+		IOReadIOPS:  conf.IOReadIOPS,
+		IOWriteIOPS: conf.IOWriteIOPS,
+	})
 	return &worker{
 		fs:                    conf.FileStore,
 		envPool:               conf.EnvironmentPool,
@@ -90,10 +127,16 @@ func New(conf Config) Worker {
 		workDir:               conf.WorkDir,
 		timeLimitTickInterval: conf.TimeLimitTickInterval,
 		extraMemoryLimit:      conf.ExtraMemoryLimit,
-		outputLimit:           conf.OutputLimit,
-		copyOutLimit:          conf.CopyOutLimit,
-		openFileLimit:         conf.OpenFileLimit,
+		defaultLimits:         defaultLimits,
+		maxLimits:             conf.MaxLimits,
+		rejectExceededLimits:  conf.RejectExceededLimits,
+		enableSyscallTrace:    conf.EnableSyscallTrace,
+		repeatThreshold:       conf.RepeatThreshold,
+		repeatMax:             conf.RepeatMax,
+		speedFactor:           conf.SpeedFactor,
 		execObserver:          conf.ExecObserver,
+		archiveLimit:          conf.ArchiveLimit,
+		netShare:              conf.NetShare,
 	}
 }
 
@@ -113,14 +156,25 @@ func (w *worker) Start() {
 func (w *worker) Submit(ctx context.Context, req *Request) (<-chan Response, <-chan struct{}) {
 	ch := make(chan Response, 1)
 	started := make(chan struct{})
+	release, err := w.leaseFiles(req)
+	if err != nil {
+		close(started)
+		ch <- Response{
+			RequestID: req.RequestID,
+			Error:     err,
+		}
+		return ch, started
+	}
 	select {
 	case w.workCh <- workRequest{
 		Request:  req,
 		Context:  ctx,
 		started:  started,
 		resultCh: ch,
+		release:  release,
 	}:
 	default:
+		release()
 		close(started)
 		ch <- Response{
 			RequestID: req.RequestID,
@@ -136,6 +190,15 @@ func (w *worker) Execute(ctx context.Context, req *Request) <-chan Response {
 	w.wg.Add(1)
 	go func() {
 		defer w.wg.Done()
+		release, err := w.leaseFiles(req)
+		if err != nil {
+			ch <- Response{
+				RequestID: req.RequestID,
+				Error:     err,
+			}
+			return
+		}
+		defer release()
 		ch <- w.workDoCmd(ctx, req)
 	}()
 	return ch
@@ -176,6 +239,7 @@ func (w *worker) loop() {
 			default:
 				req.resultCh <- w.workDoCmd(req.Context, req.Request)
 			}
+			req.release()
 
 		case <-w.done:
 			return
@@ -188,10 +252,14 @@ func (w *worker) workDoCmd(ctx context.Context, req *Request) Response {
 	defer w.running.Add(-1)
 
 	var rt Response
-	if len(req.Cmd) == 1 {
-		rt = w.workDoSingle(ctx, req.Cmd[0])
-	} else {
-		rt = w.workDoGroup(ctx, req.Cmd, req.PipeMapping)
+	cmds, err := w.limitCmds(req.Cmd)
+	switch {
+	case err != nil:
+		rt.Error = err
+	case len(cmds) == 1:
+		rt = w.workDoSingle(ctx, cmds[0])
+	default:
+		rt = w.workDoGroup(ctx, cmds, req.PipeMapping)
 	}
 	rt.RequestID = req.RequestID
 	if w.execObserver != nil {
@@ -201,20 +269,55 @@ func (w *worker) workDoCmd(ctx context.Context, req *Request) Response {
 }
 
 func (w *worker) workDoSingle(ctx context.Context, rc Cmd) (rt Response) {
-	c, err := w.prepareCmd(rc, make(map[string]bool))
-	if err != nil {
+	if rc.Role == RoleService {
+		rt.Error = fmt.Errorf("service requires other commands in the request")
+		return
+	}
+	if err := checkRepeat(rc); err != nil {
 		rt.Error = err
 		return
 	}
+	result, failed := w.runSingle(ctx, rc)
+	if failed != nil {
+		return *failed
+	}
+	var attempts []Attempt
+	if rc.Repeat > 0 {
+		result, attempts = w.repeatSingle(ctx, rc, result)
+	}
+	res := w.convertResult(result, rc)
+	if attempts != nil {
+		res.Attempts = attempts
+		res.MinTime, res.MedianTime = attemptTimes(attempts)
+	}
+	rt.Results = []Result{res}
+	return
+}
+
+// runSingle runs the command once in a new environment, it returns the
+// response to be returned if failed
+func (w *worker) runSingle(ctx context.Context, rc Cmd) (envexec.Result, *Response) {
+	c, err := w.prepareCmd(rc, make(map[string]bool))
+	if err != nil {
+		return envexec.Result{}, &Response{Error: err}
+	}
 	// prepare environment
-	env, err := w.envPool.Get()
+	envPool := w.envPool
+	if c.Network != envexec.NetworkNone {
+		np, err := w.newNetworkPool()
+		if err != nil {
+			rt := envErrorResponse(1, err)
+			return envexec.Result{}, &rt
+		}
+		defer np.Destroy()
+		envPool = np
+	}
+	env, err := envPool.Get()
 	if err != nil {
-		return Response{Results: []Result{{
-			Status: envexec.StatusInternalError,
-			Error:  fmt.Sprintf("failed to get environment %v", err),
-		}}}
+		rt := envErrorResponse(1, err)
+		return envexec.Result{}, &rt
 	}
-	defer w.envPool.Put(env)
+	defer envPool.Put(env)
 	c.Environment = env
 
 	s := &envexec.Single{
@@ -223,12 +326,10 @@ func (w *worker) workDoSingle(ctx context.Context, rc Cmd) (rt Response) {
 	}
 	result, err := s.Run(ctx)
 	if err != nil {
-		rt.Error = err
-		return
+		return result, &Response{Error: err}
 	}
-	res := w.convertResult(result, rc)
-	rt.Results = []Result{res}
-	return
+	w.scaleResult(rc, &result)
+	return result, nil
 }
 
 func (w *worker) workDoGroup(ctx context.Context, rc []Cmd, pm []PipeMap) (rt Response) {
@@ -236,6 +337,10 @@ func (w *worker) workDoGroup(ctx context.Context, rc []Cmd, pm []PipeMap) (rt Re
 	cs := make([]*envexec.Cmd, 0, len(rc))
 	pipeFileNames := preparePipeNames(pm, len(rc))
 	for i, cc := range rc {
+		if cc.Repeat > 0 {
+			rt.Error = fmt.Errorf("repeat is only supported for single command")
+			return
+		}
 		c, err := w.prepareCmd(cc, pipeFileNames[i])
 		if err != nil {
 			rt.Error = err
@@ -243,21 +348,11 @@ func (w *worker) workDoGroup(ctx context.Context, rc []Cmd, pm []PipeMap) (rt Re
 		}
 		cs = append(cs, c)
 	}
-	for i := range cs {
-		env, err := w.envPool.Get()
-		if err != nil {
-			res := make([]Result, 0, len(cs))
-			for range cs {
-				res = append(res, Result{
-					Status: envexec.StatusInternalError,
-					Error:  fmt.Sprintf("failed to get environment %v", err),
-				})
-			}
-			return Response{Results: res}
-		}
-		defer w.envPool.Put(env)
-		cs[i].Environment = env
+	release, err := w.groupEnvironments(rc, cs)
+	if err != nil {
+		return envErrorResponse(len(cs), err)
 	}
+	defer release()
 	g := envexec.Group{
 		Cmd:          cs,
 		Pipes:        pm,
@@ -270,6 +365,7 @@ func (w *worker) workDoGroup(ctx context.Context, rc []Cmd, pm []PipeMap) (rt Re
 	}
 	rts = make([]Result, 0, len(results))
 	for i, result := range results {
+		w.scaleResult(rc[i], &result)
 		res := w.convertResult(result, rc[i])
 		rts = append(rts, res)
 	}
@@ -277,6 +373,73 @@ func (w *worker) workDoGroup(ctx context.Context, rc []Cmd, pm []PipeMap) (rt Re
 	return
 }
 
+// groupEnvironments gets the environments for the commands of a group request
+// and returns the function to put them back. Commands with network run inside
+// new network namespaces, which is the same one for the commands with shared
+// network.
+func (w *worker) groupEnvironments(rc []Cmd, cs []*envexec.Cmd) (func(), error) {
+	var cleanUp []func()
+	release := func() {
+		for _, f := range slices.Backward(cleanUp) {
+			f()
+		}
+	}
+	// services and the other commands talk over the shared network, which is
+	// the host network when it is shared
+	if !w.netShare && slices.ContainsFunc(rc, func(c Cmd) bool { return c.Role == RoleService }) {
+		for _, c := range cs {
+			c.Network = envexec.NetworkShared
+		}
+	}
+	var sharedPool EnvironmentPool
+	for i := range cs {
+		envPool := w.envPool
+		switch {
+		case cs[i].Network == envexec.NetworkShared && sharedPool != nil:
+			envPool = sharedPool
+
+		case cs[i].Network != envexec.NetworkNone:
+			np, err := w.newNetworkPool()
+			if err != nil {
+				release()
+				return nil, err
+			}
+			cleanUp = append(cleanUp, np.Destroy)
+			envPool = np
+			if cs[i].Network == envexec.NetworkShared {
+				sharedPool = np
+			}
+		}
+		env, err := envPool.Get()
+		if err != nil {
+			release()
+			return nil, err
+		}
+		cleanUp = append(cleanUp, func() { envPool.Put(env) })
+		cs[i].Environment = env
+	}
+	return release, nil
+}
+
+func (w *worker) newNetworkPool() (EnvironmentPool, error) {
+	np, ok := w.envPool.(NetworkEnvironmentPool)
+	if !ok {
+		return nil, fmt.Errorf("network is not supported")
+	}
+	return np.NewNetwork()
+}
+
+func envErrorResponse(count int, err error) Response {
+	res := make([]Result, 0, count)
+	for range count {
+		res = append(res, Result{
+			Status: envexec.StatusInternalError,
+			Error:  fmt.Sprintf("failed to get environment %v", err),
+		})
+	}
+	return Response{Results: res}
+}
+
 func (w *worker) convertResult(result envexec.Result, cmd Cmd) (res Result) {
 	res.Status = result.Status
 	res.ExitStatus = result.ExitStatus
@@ -285,7 +448,16 @@ func (w *worker) convertResult(result envexec.Result, cmd Cmd) (res Result) {
 	res.RunTime = result.RunTime
 	res.Memory = result.Memory
 	res.ProcPeak = result.ProcPeak
+	res.DiskUsage = result.DiskUsage
+	res.IORead = result.IORead
+	res.IOWrite = result.IOWrite
+	res.MemoryHighEvents = result.MemoryHighEvents
 	res.FileError = result.FileError
+	res.Syscalls = result.Syscalls
+	res.Processes = result.Processes
+	res.Perf = result.Perf
+	limits := limitsOf(cmd)
+	res.Limits = &limits
 	res.Files = make(map[string]*os.File)
 	res.FileIDs = make(map[string]string)
 
@@ -295,13 +467,25 @@ func (w *worker) convertResult(result envexec.Result, cmd Cmd) (res Result) {
 		res.Status = envexec.StatusSignalled
 	}
 
-	copyOutCachedSet := make(map[string]bool, len(cmd.CopyOutCached))
-	for _, f := range cmd.CopyOutCached {
-		copyOutCachedSet[f.Name] = true
+	if result.Crash != nil {
+		res.Crash = w.convertCrash(result.Crash, &res)
+	}
+
+	// files matched by glob patterns are cached by the pattern
+	cached := func(name string) bool {
+		if name == cmd.CopyOutBundle {
+			return true
+		}
+		for _, f := range cmd.CopyOutCached {
+			if f.Match(name) {
+				return true
+			}
+		}
+		return false
 	}
 
 	for name, b := range result.Files {
-		if !copyOutCachedSet[name] {
+		if !cached(name) {
 			res.Files[name] = b
 			continue
 		}
@@ -317,7 +501,37 @@ func (w *worker) convertResult(result envexec.Result, cmd Cmd) (res Result) {
 	return res
 }
 
+// convertCrash stores the core file into the file store
+func (w *worker) convertCrash(c *envexec.Crash, res *Result) *Crash {
+	crash := &Crash{
+		Signal: c.Signal,
+		Addr:   c.Addr,
+		Stack:  c.Stack,
+	}
+	if c.Core == nil {
+		return crash
+	}
+	defer c.Core.Close()
+	id, err := w.fs.Add("core", c.Core.Name())
+	if err != nil {
+		res.Error = fmt.Sprintf("failed to store core file: %v", err)
+		return crash
+	}
+	crash.CoreFileID = id
+	return crash
+}
+
 func (w *worker) prepareCmd(rc Cmd, pipeFileName map[string]bool) (*envexec.Cmd, error) {
+	if rc.SyscallTrace != "" && !w.enableSyscallTrace {
+		return nil, fmt.Errorf("syscall trace is not enabled")
+	}
+	if rc.SyscallTrace != "" {
+		for _, f := range slices.Concat(rc.CopyOut, rc.CopyOutCached) {
+			if f.Match(rc.SyscallTrace) {
+				return nil, fmt.Errorf("syscall trace %q collides with copy out file %q", rc.SyscallTrace, f.Name)
+			}
+		}
+	}
 	files, err := w.prepareCmdFiles(rc.Files, pipeFileName)
 	if err != nil {
 		return nil, err
@@ -339,10 +553,15 @@ func (w *worker) prepareCmd(rc Cmd, pipeFileName map[string]bool) (*envexec.Cmd,
 		}
 	}
 
+	// limits are in time of the reference machine when scaled
+	scale := w.timeScale(rc)
+	cpuLimit := scaleDuration(rc.CPULimit, 1/scale)
+	clockLimit := scaleDuration(rc.ClockLimit, 1/scale)
+
 	wait := &waiter{
 		tickInterval:   w.timeLimitTickInterval,
-		timeLimit:      rc.CPULimit,
-		clockTimeLimit: rc.ClockLimit,
+		timeLimit:      cpuLimit,
+		clockTimeLimit: clockLimit,
 	}
 
 	var copyOutDir string
@@ -354,20 +573,13 @@ func (w *worker) prepareCmd(rc Cmd, pipeFileName map[string]bool) (*envexec.Cmd,
 		}
 	}
 
-	timeLimit := time.Duration(rc.CPULimit)
-	copyOutMax := w.copyOutLimit
-	if rc.CopyOutMax > 0 {
-		copyOutMax = envexec.Size(rc.CopyOutMax)
-	}
-
-	outputLimit := rc.OutputLimit
-	if outputLimit == 0 {
-		outputLimit = w.outputLimit
-	}
-
-	openFileLimit := rc.OpenFileLimit
-	if openFileLimit == 0 {
-		openFileLimit = w.openFileLimit
+	var service *envexec.Service
+	if rc.Role == RoleService {
+		service = &envexec.Service{
+			Port:    rc.ReadyPort,
+			File:    rc.ReadyFile,
+			Timeout: rc.ReadyTimeout,
+		}
 	}
 
 	return &envexec.Cmd{
@@ -375,32 +587,81 @@ func (w *worker) prepareCmd(rc Cmd, pipeFileName map[string]bool) (*envexec.Cmd,
 		Env:               rc.Env,
 		Files:             files,
 		TTY:               rc.TTY,
-		TimeLimit:         timeLimit,
+		TimeLimit:         cpuLimit,
 		MemoryLimit:       envexec.Size(rc.MemoryLimit),
+		MemoryHigh:        rc.MemoryHigh,
 		StackLimit:        envexec.Size(rc.StackLimit),
 		ExtraMemoryLimit:  w.extraMemoryLimit,
-		OutputLimit:       outputLimit,
+		OutputLimit:       rc.OutputLimit,
 		ProcLimit:         rc.ProcLimit,
-		OpenFileLimit:     openFileLimit,
+		OpenFileLimit:     rc.OpenFileLimit,
 		CPURateLimit:      rc.CPURateLimit,
 		CPUSetLimit:       rc.CPUSetLimit,
+		DiskLimit:         rc.DiskLimit,
+		FileCountLimit:    rc.FileCountLimit,
+		IOReadBps:         rc.IOReadBps,
+		IOWriteBps:        rc.IOWriteBps,
+		IOReadIOPS:        rc.IOReadIOPS,
+		IOWriteIOPS:       rc.IOWriteIOPS,
 		DataSegmentLimit:  rc.DataSegmentLimit,
 		AddressSpaceLimit: rc.AddressSpaceLimit,
+		SeccompAudit:      rc.SeccompAudit,
+		SyscallTrace:      rc.SyscallTrace,
+		SyscallTraceMax:   envexec.Size(rc.SyscallTraceMax),
+		CoreDump:          rc.CoreDump,
+		CoreDumpMax:       envexec.Size(rc.CoreDumpMax),
+		TraceProcess:      rc.TraceProcess,
+		Perf:              rc.Perf,
+		Network:           rc.Network,
+		Service:           service,
 		CopyIn:            copyIn,
 		SymLinks:          rc.Symlinks,
 		CopyOut:           copyOut,
 		CopyOutDir:        copyOutDir,
-		CopyOutMax:        copyOutMax,
+		CopyOutBundle:     rc.CopyOutBundle,
+		CopyOutMax:        envexec.Size(rc.CopyOutMax),
+		CopyOutMaxEntries: w.archiveLimit.MaxEntries,
 		Waiter:            wait.Wait,
 	}, nil
 }
 
+// timeScale returns the speed factor for the command, 1 if not scaled
+func (w *worker) timeScale(rc Cmd) float64 {
+	if !rc.ScaleTime || w.speedFactor == nil {
+		return 1
+	}
+	if f := w.speedFactor(); f > 0 {
+		return f
+	}
+	return 1
+}
+
+// scaleResult converts the time usage into time of the reference machine
+func (w *worker) scaleResult(rc Cmd, r *envexec.Result) {
+	scale := w.timeScale(rc)
+	if scale == 1 {
+		return
+	}
+	r.Time = scaleDuration(r.Time, scale)
+	r.RunTime = scaleDuration(r.RunTime, scale)
+}
+
+func scaleDuration(d time.Duration, scale float64) time.Duration {
+	if scale == 1 {
+		return d
+	}
+	return time.Duration(float64(d) * scale)
+}
+
 func (w *worker) prepareCopyIn(cf map[string]CmdFile) (map[string]envexec.File, error) {
 	rt := make(map[string]envexec.File)
 	for name, f := range cf {
 		if f == nil {
 			return nil, fmt.Errorf("nil type cannot be used for copyIn %s", name)
 		}
+		if af, ok := f.(*ArchiveFile); ok {
+			af.Limit = w.archiveLimit
+		}
 		pcf, err := f.EnvFile(w.fs)
 		if err != nil {
 			return nil, err
@@ -0,0 +1,98 @@
+package worker
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/criyle/go-judge/envexec"
+)
+
+// testEnvPool counts the environments, it creates networks if network is set
+type testEnvPool struct {
+	network  bool
+	networks []*testEnvPool
+	got, put int
+}
+
+type testEnv struct {
+	envexec.Environment
+	pool *testEnvPool
+}
+
+func (p *testEnvPool) Get() (envexec.Environment, error) {
+	p.got++
+	return testEnv{pool: p}, nil
+}
+
+func (p *testEnvPool) Put(envexec.Environment) {
+	p.put++
+}
+
+func (p *testEnvPool) Destroy() {}
+
+func (p *testEnvPool) NewNetwork() (EnvironmentPool, error) {
+	if !p.network {
+		return nil, errors.New("network is not supported")
+	}
+	np := &testEnvPool{}
+	p.networks = append(p.networks, np)
+	return np, nil
+}
+
+func TestGroupEnvironmentsService(t *testing.T) {
+	rc := []Cmd{{Role: RoleService}, {}}
+	newCmds := func() []*envexec.Cmd {
+		return []*envexec.Cmd{{}, {}}
+	}
+
+	// services share a new network namespace with the other commands
+	p := &testEnvPool{network: true}
+	w := &worker{envPool: p}
+	cs := newCmds()
+	release, err := w.groupEnvironments(rc, cs)
+	if err != nil {
+		t.Fatalf("groupEnvironments: %v", err)
+	}
+	if len(p.networks) != 1 || p.got != 0 {
+		t.Fatalf("expected one shared network, got %d networks", len(p.networks))
+	}
+	np := p.networks[0]
+	for i, c := range cs {
+		if c.Network != envexec.NetworkShared || c.Environment.(testEnv).pool != np {
+			t.Fatalf("%d: expected environment in shared network, got %v", i, c.Network)
+		}
+	}
+	release()
+	if np.put != 2 {
+		t.Fatalf("expected environments put back, got %d", np.put)
+	}
+
+	// services use the host network when it is shared
+	p = &testEnvPool{}
+	w = &worker{envPool: p, netShare: true}
+	cs = newCmds()
+	release, err = w.groupEnvironments(rc, cs)
+	if err != nil {
+		t.Fatalf("groupEnvironments with net share: %v", err)
+	}
+	for i, c := range cs {
+		if c.Network != envexec.NetworkNone || c.Environment.(testEnv).pool != p {
+			t.Fatalf("%d: expected environment in host network, got %v", i, c.Network)
+		}
+	}
+	release()
+	if p.put != 2 {
+		t.Fatalf("expected environments put back, got %d", p.put)
+	}
+
+	// environments got are put back on failure
+	p = &testEnvPool{}
+	w = &worker{envPool: p}
+	cs = []*envexec.Cmd{{}, {Network: envexec.NetworkLoopback}}
+	if _, err := w.groupEnvironments([]Cmd{{}, {}}, cs); err == nil {
+		t.Fatalf("expected error without network support")
+	}
+	if p.got != 1 || p.put != 1 {
+		t.Fatalf("expected environments put back on failure, got %d, put %d", p.got, p.put)
+	}
+}